@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"github.com/AplaProject/go-apla/packages/model"
+	"github.com/AplaProject/go-apla/packages/script"
+	"github.com/AplaProject/go-apla/packages/smart"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	analyzeFile      string
+	analyzeEcosystem int64
+	analyzeOffline   bool
+)
+
+// analyzeContractCmd represents the analyze-contract command
+var analyzeContractCmd = &cobra.Command{
+	Use:   "analyze-contract",
+	Short: "Check the contract source and print warnings",
+	PreRun: func(cmd *cobra.Command, args []string) {
+		if !analyzeOffline {
+			loadConfig(cmd, args)
+		}
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		logger := log.WithFields(log.Fields{"file": analyzeFile})
+		source, err := ioutil.ReadFile(analyzeFile)
+		if err != nil {
+			logger.WithError(err).Fatal("reading contract source")
+		}
+
+		smart.InitVM()
+		if analyzeOffline {
+			if err := smart.LoadSysFuncs(smart.GetVM(), 1); err != nil {
+				logger.WithError(err).Fatal("loading system functions")
+			}
+		} else {
+			initBlocksFileDB()
+			defer model.GormClose()
+
+			if err := smart.LoadContracts(); err != nil {
+				logger.WithError(err).Fatal("loading contracts")
+			}
+		}
+
+		warnings, err := smart.Analyze(string(source), &script.OwnerInfo{StateID: uint32(analyzeEcosystem)})
+		for _, warning := range warnings {
+			fmt.Printf("%s:%d:%d: %s: %s\n", analyzeFile, warning.Line, warning.Column, warning.Code, warning.Text)
+		}
+		if err != nil {
+			logger.WithError(err).Fatal("compiling contract")
+		}
+	},
+}
+
+func init() {
+	analyzeContractCmd.Flags().StringVar(&analyzeFile, "file", "", "Path to the contract source")
+	analyzeContractCmd.Flags().Int64Var(&analyzeEcosystem, "ecosystem", 1, "Ecosystem of the contract")
+	analyzeContractCmd.Flags().BoolVar(&analyzeOffline, "offline", false, "Don't load contracts from the database")
+	analyzeContractCmd.MarkFlagRequired("file")
+}
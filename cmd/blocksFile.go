@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"github.com/AplaProject/go-apla/packages/conf"
+	"github.com/AplaProject/go-apla/packages/daemons"
+	"github.com/AplaProject/go-apla/packages/model"
+	"github.com/AplaProject/go-apla/packages/smart"
+	"github.com/AplaProject/go-apla/packages/utils"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	blocksFile  string
+	fromBlockID int64
+	toBlockID   int64
+)
+
+func initBlocksFileDB() {
+	if err := model.GormInit(
+		conf.Config.DB.Host,
+		conf.Config.DB.Port,
+		conf.Config.DB.User,
+		conf.Config.DB.Password,
+		conf.Config.DB.Name,
+	); err != nil {
+		log.WithError(err).Fatal("init db")
+	}
+}
+
+// exportBlocksCmd represents the export-blocks command
+var exportBlocksCmd = &cobra.Command{
+	Use:    "export-blocks",
+	Short:  "Export blocks to the block file",
+	PreRun: loadConfig,
+	Run: func(cmd *cobra.Command, args []string) {
+		initBlocksFileDB()
+		defer model.GormClose()
+
+		logger := log.WithFields(log.Fields{"file": blocksFile})
+		count, err := daemons.ExportBlocks(blocksFile, fromBlockID, toBlockID, logger)
+		if err != nil {
+			logger.WithError(err).Fatal("exporting blocks")
+		}
+		logger.WithFields(log.Fields{"count": count}).Info("blocks exported")
+	},
+}
+
+// importBlocksCmd represents the import-blocks command
+var importBlocksCmd = &cobra.Command{
+	Use:    "import-blocks",
+	Short:  "Import blocks from the block file",
+	PreRun: loadConfigWKey,
+	Run: func(cmd *cobra.Command, args []string) {
+		f := utils.LockOrDie(conf.Config.LockFilePath)
+		defer f.Unlock()
+
+		initBlocksFileDB()
+		defer model.GormClose()
+
+		smart.InitVM()
+		logger := log.WithFields(log.Fields{"file": blocksFile})
+		count, err := daemons.ImportBlocks(blocksFile, logger)
+		if err != nil {
+			logger.WithFields(log.Fields{"count": count}).WithError(err).Fatal("importing blocks")
+		}
+		logger.WithFields(log.Fields{"count": count}).Info("blocks imported")
+	},
+}
+
+func init() {
+	exportBlocksCmd.Flags().StringVar(&blocksFile, "file", "", "Path to the block file")
+	exportBlocksCmd.Flags().Int64Var(&fromBlockID, "from", 1, "The first block id to export")
+	exportBlocksCmd.Flags().Int64Var(&toBlockID, "to", 0, "The last block id to export (default the last block)")
+	exportBlocksCmd.MarkFlagRequired("file")
+
+	importBlocksCmd.Flags().StringVar(&blocksFile, "file", "", "Path to the block file")
+	importBlocksCmd.MarkFlagRequired("file")
+}
@@ -13,6 +13,7 @@ import (
 
 	"github.com/AplaProject/go-apla/packages/conf"
 	"github.com/AplaProject/go-apla/packages/consts"
+	"github.com/AplaProject/go-apla/packages/keystore"
 )
 
 // configCmd represents the config command
@@ -60,6 +61,30 @@ func init() {
 	viper.BindPFlag("TCPServer.Host", configCmd.Flags().Lookup("tcpHost"))
 	viper.BindPFlag("TCPServer.Port", configCmd.Flags().Lookup("tcpPort"))
 
+	// Signer
+	configCmd.Flags().StringVar(&conf.Config.Signer.Type, "signer", "file", "Signer of the node key (file | remote)")
+	configCmd.Flags().StringVar(&conf.Config.Signer.Address, "signerAddress", "", "Address of the remote signer (unix:///path | tcp://host:port)")
+	configCmd.Flags().StringVar(&conf.Config.Signer.Token, "signerToken", "", "Token of the remote signer")
+	configCmd.Flags().StringVar(&conf.Config.Signer.CAFile, "signerCA", "", "Filepath to TLS certificate of the tcp remote signer or its CA")
+	configCmd.Flags().IntVar(&conf.Config.Signer.Timeout, "signerTimeout", 5, "Timeout of the remote signer in seconds")
+	viper.BindPFlag("Signer.Type", configCmd.Flags().Lookup("signer"))
+	viper.BindPFlag("Signer.Address", configCmd.Flags().Lookup("signerAddress"))
+	viper.BindPFlag("Signer.Token", configCmd.Flags().Lookup("signerToken"))
+	viper.BindPFlag("Signer.CAFile", configCmd.Flags().Lookup("signerCA"))
+	viper.BindPFlag("Signer.Timeout", configCmd.Flags().Lookup("signerTimeout"))
+
+	// TCP Limits
+	configCmd.Flags().IntVar(&conf.Config.TCPLimits.MaxConnections, "tcpMaxConnections", consts.DefaultTCPMaxConnections, "Concurrent connections from one IP address or node")
+	configCmd.Flags().Float64Var(&conf.Config.TCPLimits.RequestRate, "tcpRequestRate", consts.DefaultTCPRequestRate, "Requests per second from one IP address or node")
+	configCmd.Flags().IntVar(&conf.Config.TCPLimits.RequestBurst, "tcpRequestBurst", consts.DefaultTCPRequestBurst, "Requests which can be done at once")
+	configCmd.Flags().IntVar(&conf.Config.TCPLimits.MaxFails, "tcpMaxFails", consts.DefaultTCPMaxFails, "Malformed requests before the peer is banned")
+	configCmd.Flags().IntVar(&conf.Config.TCPLimits.BanTime, "tcpBanTime", consts.DefaultTCPBanTime, "Ban time of the peer in seconds")
+	viper.BindPFlag("TCPLimits.MaxConnections", configCmd.Flags().Lookup("tcpMaxConnections"))
+	viper.BindPFlag("TCPLimits.RequestRate", configCmd.Flags().Lookup("tcpRequestRate"))
+	viper.BindPFlag("TCPLimits.RequestBurst", configCmd.Flags().Lookup("tcpRequestBurst"))
+	viper.BindPFlag("TCPLimits.MaxFails", configCmd.Flags().Lookup("tcpMaxFails"))
+	viper.BindPFlag("TCPLimits.BanTime", configCmd.Flags().Lookup("tcpBanTime"))
+
 	// HTTP Server
 	configCmd.Flags().StringVar(&conf.Config.HTTP.Host, "httpHost", "127.0.0.1", "Node HTTP host")
 	configCmd.Flags().IntVar(&conf.Config.HTTP.Port, "httpPort", 7079, "Node HTTP port")
@@ -94,6 +119,16 @@ func init() {
 	viper.BindPFlag("Centrifugo.Secret", configCmd.Flags().Lookup("centSecret"))
 	viper.BindPFlag("Centrifugo.URL", configCmd.Flags().Lookup("centUrl"))
 
+	// Publisher
+	configCmd.Flags().StringVar(&conf.Config.Publisher.Type, "publisher", "centrifugo", "Publisher of notifications (centrifugo | local | webhook)")
+	configCmd.Flags().StringVar(&conf.Config.Publisher.WebhookURL, "webhookUrl", "", "Webhook URL of notifications")
+	configCmd.Flags().StringVar(&conf.Config.Publisher.WebhookSecret, "webhookSecret", "", "Webhook HMAC secret")
+	configCmd.Flags().IntVar(&conf.Config.Publisher.Timeout, "webhookTimeout", 5, "Webhook timeout in seconds")
+	viper.BindPFlag("Publisher.Type", configCmd.Flags().Lookup("publisher"))
+	viper.BindPFlag("Publisher.WebhookURL", configCmd.Flags().Lookup("webhookUrl"))
+	viper.BindPFlag("Publisher.WebhookSecret", configCmd.Flags().Lookup("webhookSecret"))
+	viper.BindPFlag("Publisher.Timeout", configCmd.Flags().Lookup("webhookTimeout"))
+
 	// Log
 	configCmd.Flags().StringVar(&conf.Config.Log.LogTo, "logTo", "stdout", "Send logs to stdout|(filename)|syslog")
 	configCmd.Flags().StringVar(&conf.Config.Log.LogLevel, "logLevel", "ERROR", "Log verbosity (DEBUG | INFO | WARN | ERROR)")
@@ -130,8 +165,12 @@ func init() {
 		fmt.Sprintf("Apla lock file name (default dataDir/%s)", consts.DefaultLockFilename),
 	)
 	configCmd.Flags().StringVar(&conf.Config.KeysDir, "keysDir", "", "Keys directory (default dataDir)")
+	configCmd.Flags().StringVar(&conf.Config.KeysPassphraseFile, "keysPassphraseFile", "", "Filepath to the passphrase of the encrypted keys (default $"+keystore.PassphraseEnv+")")
 	configCmd.Flags().StringVar(&conf.Config.DataDir, "dataDir", "", "Data directory (default cwd/apla-data)")
 	configCmd.Flags().StringVar(&conf.Config.TempDir, "tempDir", "", "Temporary directory (default temporary directory of OS)")
+	configCmd.Flags().StringVar(&conf.Config.ContractCacheDir, "contractCacheDir", "",
+		fmt.Sprintf("Directory of the compiled contracts cache (default dataDir/%s)", consts.DefaultContractCacheDirName),
+	)
 	configCmd.Flags().StringVar(&conf.Config.FirstBlockPath, "firstBlock", "", "First block path (default dataDir/1block)")
 	configCmd.Flags().BoolVar(&conf.Config.TLS, "tls", false, "Enable https")
 	configCmd.Flags().StringVar(&conf.Config.TLSCert, "tls-cert", "", "Filepath to the fullchain of certificates")
@@ -140,10 +179,16 @@ func init() {
 	configCmd.Flags().Int64Var(&conf.Config.HTTPServerMaxBodySize, "mbs", 1<<20, "Max server body size in byte")
 	configCmd.Flags().StringSliceVar(&conf.Config.NodesAddr, "nodesAddr", []string{}, "List of addresses for downloading blockchain")
 	configCmd.Flags().StringVar(&conf.Config.OBSMode, "obsMode", consts.NoneVDE, "OBS running mode")
+	configCmd.Flags().Int64Var(&conf.Config.NetworkID, "networkID", 0, "Network ID, nodes of the different networks refuse connections")
+	configCmd.Flags().BoolVar(&conf.Config.SecureNodes, "secureNodes", false, "Connect only to full nodes over encrypted sessions authenticated by their keys")
+	configCmd.Flags().BoolVar(&conf.Config.RejectUnknownNodes, "rejectUnknownNodes", false, "Refuse connections from nodes which aren't in full_nodes")
+	configCmd.Flags().Int64Var(&conf.Config.TxPoolTTL, "txPoolTTL", consts.DefaultTxPoolTTL, "Lifetime of the unused transactions in the pool (in seconds)")
+	configCmd.Flags().Float64Var(&conf.Config.FinalityThreshold, "finalityThreshold", consts.DefaultFinalityThreshold, "Fraction of full nodes which must sign the block to finalize it")
 
 	viper.BindPFlag("PidFilePath", configCmd.Flags().Lookup("pid"))
 	viper.BindPFlag("LockFilePath", configCmd.Flags().Lookup("lock"))
 	viper.BindPFlag("KeysDir", configCmd.Flags().Lookup("keysDir"))
+	viper.BindPFlag("KeysPassphraseFile", configCmd.Flags().Lookup("keysPassphraseFile"))
 	viper.BindPFlag("DataDir", configCmd.Flags().Lookup("dataDir"))
 	viper.BindPFlag("FirstBlockPath", configCmd.Flags().Lookup("firstBlock"))
 	viper.BindPFlag("TLS", configCmd.Flags().Lookup("tls"))
@@ -152,6 +197,12 @@ func init() {
 	viper.BindPFlag("MaxPageGenerationTime", configCmd.Flags().Lookup("mpgt"))
 	viper.BindPFlag("HTTPServerMaxBodySize", configCmd.Flags().Lookup("mbs"))
 	viper.BindPFlag("TempDir", configCmd.Flags().Lookup("tempDir"))
+	viper.BindPFlag("ContractCacheDir", configCmd.Flags().Lookup("contractCacheDir"))
 	viper.BindPFlag("NodesAddr", configCmd.Flags().Lookup("nodesAddr"))
 	viper.BindPFlag("OBSMode", configCmd.Flags().Lookup("obsMode"))
+	viper.BindPFlag("NetworkID", configCmd.Flags().Lookup("networkID"))
+	viper.BindPFlag("SecureNodes", configCmd.Flags().Lookup("secureNodes"))
+	viper.BindPFlag("RejectUnknownNodes", configCmd.Flags().Lookup("rejectUnknownNodes"))
+	viper.BindPFlag("FinalityThreshold", configCmd.Flags().Lookup("finalityThreshold"))
+	viper.BindPFlag("TxPoolTTL", configCmd.Flags().Lookup("txPoolTTL"))
 }
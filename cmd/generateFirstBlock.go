@@ -97,7 +97,7 @@ var generateFirstBlockCmd = &cobra.Command{
 		block, err := block.MarshallBlock(header, [][]byte{tx}, &utils.BlockData{
 			Hash:          []byte(`0`),
 			RollbacksHash: []byte(`0`),
-		}, "")
+		}, nil)
 		if err != nil {
 			log.WithFields(log.Fields{"type": consts.MarshallingError, "error": err}).Fatal("first block marshalling")
 			return
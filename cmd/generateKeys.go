@@ -11,6 +11,7 @@ import (
 	"github.com/AplaProject/go-apla/packages/conf"
 	"github.com/AplaProject/go-apla/packages/consts"
 	"github.com/AplaProject/go-apla/packages/crypto"
+	"github.com/AplaProject/go-apla/packages/keystore"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
@@ -18,15 +19,22 @@ import (
 
 const fileMode = 0600
 
+var encryptKeys bool
+
 // generateKeysCmd represents the generateKeys command
 var generateKeysCmd = &cobra.Command{
 	Use:    "generateKeys",
 	Short:  "Keys generation",
 	PreRun: loadConfig,
 	Run: func(cmd *cobra.Command, args []string) {
+		var passphrase []byte
+		if encryptKeys {
+			passphrase = mustNewPassphrase(conf.Config.KeysPassphraseFile, true)
+		}
 		_, publicKey, err := createKeyPair(
 			filepath.Join(conf.Config.KeysDir, consts.PrivateKeyFilename),
 			filepath.Join(conf.Config.KeysDir, consts.PublicKeyFilename),
+			passphrase,
 		)
 		if err != nil {
 			log.WithFields(log.Fields{"error": err}).Fatal("generating user keys")
@@ -35,6 +43,7 @@ var generateKeysCmd = &cobra.Command{
 		_, _, err = createKeyPair(
 			filepath.Join(conf.Config.KeysDir, consts.NodePrivateKeyFilename),
 			filepath.Join(conf.Config.KeysDir, consts.NodePublicKeyFilename),
+			passphrase,
 		)
 		if err != nil {
 			log.WithFields(log.Fields{"error": err}).Fatal("generating node keys")
@@ -63,19 +72,30 @@ func createFile(filename string, data []byte) error {
 	return ioutil.WriteFile(filename, data, fileMode)
 }
 
-func createKeyPair(privFilename, pubFilename string) (priv, pub []byte, err error) {
+// createKeyPair writes the private key as hex or as the keystore if the passphrase isn't empty
+func createKeyPair(privFilename, pubFilename string, passphrase []byte) (priv, pub []byte, err error) {
 	priv, pub, err = crypto.GenBytesKeys()
 	if err != nil {
 		log.WithFields(log.Fields{"error": err}).Error("generate keys")
 		return
 	}
 
-	err = createFile(privFilename, []byte(hex.EncodeToString(priv)))
+	data := []byte(hex.EncodeToString(priv))
+	if len(passphrase) > 0 {
+		if data, err = keystore.Encrypt(priv, passphrase); err != nil {
+			log.WithFields(log.Fields{"error": err}).Error("encrypting private key")
+			return
+		}
+	}
+	err = createFile(privFilename, data)
 	if err != nil {
 		log.WithFields(log.Fields{"error": err, "path": privFilename}).Error("creating private key")
 		return
 	}
 
+	if len(pubFilename) == 0 {
+		return
+	}
 	err = createFile(pubFilename, []byte(crypto.PubToHex(pub)))
 	if err != nil {
 		log.WithFields(log.Fields{"error": err, "path": pubFilename}).Error("creating public key")
@@ -84,3 +104,7 @@ func createKeyPair(privFilename, pubFilename string) (priv, pub []byte, err erro
 
 	return
 }
+
+func init() {
+	generateKeysCmd.Flags().BoolVar(&encryptKeys, "encrypt", false, "Encrypt the private keys by the passphrase (keysPassphraseFile, $"+keystore.PassphraseEnv+" or terminal)")
+}
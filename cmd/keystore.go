@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/AplaProject/go-apla/packages/crypto"
+	"github.com/AplaProject/go-apla/packages/keystore"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+var (
+	keystoreKeyFile           string
+	keystorePubFile           string
+	keystoreHexFile           string
+	keystoreOutFile           string
+	keystorePassphraseFile    string
+	keystoreNewPassphraseFile string
+)
+
+// keystoreCmd represents the keystore command
+var keystoreCmd = &cobra.Command{
+	Use:   "keystore",
+	Short: "Managing the encrypted private keys",
+}
+
+var keystoreCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Generating the new key pair into the encrypted key file",
+	Run: func(cmd *cobra.Command, args []string) {
+		passphrase := mustNewPassphrase(keystorePassphraseFile, true)
+		_, publicKey, err := createKeyPair(keystoreKeyFile, keystorePubFile, passphrase)
+		if err != nil {
+			log.WithError(err).Fatal("Generating keys")
+		}
+		log.WithFields(log.Fields{"public_key": crypto.PubToHex(publicKey), "key_id": crypto.Address(publicKey)}).Info("Keys generated")
+	},
+}
+
+var keystoreImportCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Encrypting the private key from the hex file",
+	Run: func(cmd *cobra.Command, args []string) {
+		data, err := ioutil.ReadFile(keystoreHexFile)
+		if err != nil {
+			log.WithError(err).WithFields(log.Fields{"path": keystoreHexFile}).Fatal("Reading private key")
+		}
+		if keystore.IsEncrypted(data) {
+			log.WithFields(log.Fields{"path": keystoreHexFile}).Fatal("Private key is already encrypted")
+		}
+		privateKey, err := keystore.Decode(data, nil)
+		if err != nil {
+			log.WithError(err).Fatal("Decoding private key")
+		}
+		passphrase := mustNewPassphrase(keystorePassphraseFile, true)
+		if err = keystore.WriteFile(keystoreKeyFile, privateKey, passphrase); err != nil {
+			log.WithError(err).Fatal("Writing encrypted key")
+		}
+		log.WithFields(log.Fields{"path": keystoreKeyFile}).Info("Private key imported")
+	},
+}
+
+var keystoreExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Decrypting the private key to hex",
+	Run: func(cmd *cobra.Command, args []string) {
+		privateKey := mustDecryptKey(keystoreKeyFile, keystorePassphraseFile)
+		if len(keystoreOutFile) == 0 {
+			fmt.Println(hex.EncodeToString(privateKey))
+			return
+		}
+		if err := keystore.WriteFile(keystoreOutFile, privateKey, nil); err != nil {
+			log.WithError(err).Fatal("Writing private key")
+		}
+		log.WithFields(log.Fields{"path": keystoreOutFile}).Info("Private key exported")
+	},
+}
+
+var keystorePasswdCmd = &cobra.Command{
+	Use:   "passwd",
+	Short: "Changing the passphrase of the encrypted key file",
+	Run: func(cmd *cobra.Command, args []string) {
+		privateKey := mustDecryptKey(keystoreKeyFile, keystorePassphraseFile)
+		passphrase := mustNewPassphrase(keystoreNewPassphraseFile, false)
+		if err := keystore.WriteFile(keystoreKeyFile, privateKey, passphrase); err != nil {
+			log.WithError(err).Fatal("Writing encrypted key")
+		}
+		log.WithFields(log.Fields{"path": keystoreKeyFile}).Info("Passphrase changed")
+	},
+}
+
+// readPassphrase returns the passphrase from the file, from the environment variable or from the terminal
+func readPassphrase(path string, fromEnv bool, prompt string) (passphrase []byte, interactive bool, err error) {
+	if len(path) > 0 || fromEnv {
+		passphrase, err = keystore.LoadPassphrase(path)
+		if err != nil || len(passphrase) > 0 {
+			return
+		}
+	}
+
+	fd := int(os.Stdin.Fd())
+	if !terminal.IsTerminal(fd) {
+		return nil, false, keystore.ErrEmptyPassphrase
+	}
+	fmt.Fprint(os.Stderr, prompt)
+	passphrase, err = terminal.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+	return passphrase, true, err
+}
+
+// mustNewPassphrase returns the passphrase for the encryption, the passphrase from the terminal is asked twice
+func mustNewPassphrase(path string, fromEnv bool) []byte {
+	passphrase, interactive, err := readPassphrase(path, fromEnv, "New passphrase: ")
+	if err == nil && len(passphrase) == 0 {
+		err = keystore.ErrEmptyPassphrase
+	}
+	if err != nil {
+		log.WithError(err).Fatal("Reading passphrase")
+	}
+	if !interactive {
+		return passphrase
+	}
+
+	confirm, _, err := readPassphrase("", false, "Repeat passphrase: ")
+	if err != nil {
+		log.WithError(err).Fatal("Reading passphrase")
+	}
+	if !bytes.Equal(passphrase, confirm) {
+		log.WithError(errors.New("Passphrases don't match")).Fatal("Reading passphrase")
+	}
+	return passphrase
+}
+
+func mustDecryptKey(path, passphraseFile string) []byte {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		log.WithError(err).WithFields(log.Fields{"path": path}).Fatal("Reading key file")
+	}
+	if !keystore.IsEncrypted(data) {
+		log.WithFields(log.Fields{"path": path}).Fatal("Key file isn't encrypted")
+	}
+	passphrase, _, err := readPassphrase(passphraseFile, true, "Passphrase: ")
+	if err != nil {
+		log.WithError(err).Fatal("Reading passphrase")
+	}
+	privateKey, err := keystore.Decrypt(data, passphrase)
+	if err != nil {
+		log.WithError(err).WithFields(log.Fields{"path": path}).Fatal("Decrypting key file")
+	}
+	return privateKey
+}
+
+func init() {
+	keystoreCmd.PersistentFlags().StringVar(&keystoreKeyFile, "key", "", "Filepath to the encrypted private key")
+	keystoreCmd.PersistentFlags().StringVar(&keystorePassphraseFile, "passphraseFile", "", "Filepath to the passphrase (default $"+keystore.PassphraseEnv+" or terminal)")
+	keystoreCmd.MarkPersistentFlagRequired("key")
+
+	keystoreCreateCmd.Flags().StringVar(&keystorePubFile, "pub", "", "Filepath to the public key")
+	keystoreImportCmd.Flags().StringVar(&keystoreHexFile, "hex", "", "Filepath to the private key in hex")
+	keystoreImportCmd.MarkFlagRequired("hex")
+	keystoreExportCmd.Flags().StringVar(&keystoreOutFile, "out", "", "Filepath to the private key in hex (default stdout)")
+	keystorePasswdCmd.Flags().StringVar(&keystoreNewPassphraseFile, "newPassphraseFile", "", "Filepath to the new passphrase (default terminal)")
+
+	keystoreCmd.AddCommand(
+		keystoreCreateCmd,
+		keystoreImportCmd,
+		keystoreExportCmd,
+		keystorePasswdCmd,
+	)
+}
@@ -26,6 +26,13 @@ func init() {
 		configCmd,
 		stopNetworkCmd,
 		versionCmd,
+		exportBlocksCmd,
+		importBlocksCmd,
+		createSnapshotCmd,
+		restoreSnapshotCmd,
+		analyzeContractCmd,
+		signerCmd,
+		keystoreCmd,
 	)
 
 	// This flags are visible for all child commands
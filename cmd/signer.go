@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"github.com/AplaProject/go-apla/packages/consts"
+	"github.com/AplaProject/go-apla/packages/crypto"
+	"github.com/AplaProject/go-apla/packages/keystore"
+	"github.com/AplaProject/go-apla/packages/signer"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	signerListen   string
+	signerKeyFile  string
+	signerToken    string
+	signerPassFile string
+	signerTLSCert  string
+	signerTLSKey   string
+)
+
+// signerCmd represents the signer command
+var signerCmd = &cobra.Command{
+	Use:   "signer",
+	Short: "Starting the remote signer of the node key",
+	Run: func(cmd *cobra.Command, args []string) {
+		passphrase, err := keystore.LoadPassphrase(signerPassFile)
+		if err != nil {
+			log.WithFields(log.Fields{"error": err}).Fatal("Reading passphrase")
+		}
+		if err = keystore.Unlock(passphrase, signerKeyFile); err != nil {
+			log.WithFields(log.Fields{"error": err, "path": signerKeyFile}).Fatal("Unlocking node key")
+		}
+
+		s := signer.NewFileSigner(signerKeyFile)
+		publicKey, err := s.PublicKey()
+		if err != nil {
+			log.WithFields(log.Fields{"error": err, "path": signerKeyFile}).Fatal("Reading node key")
+		}
+
+		l, err := signer.Listen(signerListen, signerToken, signerTLSCert, signerTLSKey)
+		if err != nil {
+			log.WithFields(log.Fields{"error": err, "address": signerListen}).Fatal("Listening")
+		}
+		defer l.Close()
+
+		log.WithFields(log.Fields{"address": signerListen, "public_key": crypto.PubToHex(publicKey)}).Info("Signer started")
+		if err = signer.Serve(l, s, signerToken); err != nil {
+			log.WithFields(log.Fields{"error": err, "type": consts.NetworkError}).Fatal("Serving")
+		}
+	},
+}
+
+func init() {
+	signerCmd.Flags().StringVar(&signerListen, "listen", "", "Address of the signer (unix:///path | tcp://host:port)")
+	signerCmd.Flags().StringVar(&signerKeyFile, "key", "", "Filepath to the node private key")
+	signerCmd.Flags().StringVar(&signerToken, "token", "", "Token which is required from the nodes, it's required for tcp address")
+	signerCmd.Flags().StringVar(&signerTLSCert, "tlsCert", "", "Filepath to TLS certificate, it's required for tcp address")
+	signerCmd.Flags().StringVar(&signerTLSKey, "tlsKey", "", "Filepath to TLS private key, it's required for tcp address")
+	signerCmd.Flags().StringVar(&signerPassFile, "passphraseFile", "", "Filepath to the passphrase of the encrypted node key (default $"+keystore.PassphraseEnv+")")
+	signerCmd.MarkFlagRequired("listen")
+	signerCmd.MarkFlagRequired("key")
+}
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"github.com/AplaProject/go-apla/packages/conf"
+	"github.com/AplaProject/go-apla/packages/model"
+	"github.com/AplaProject/go-apla/packages/snapshot"
+	"github.com/AplaProject/go-apla/packages/utils"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	snapshotFile     string
+	snapshotNoVerify bool
+)
+
+// createSnapshotCmd represents the create-snapshot command
+var createSnapshotCmd = &cobra.Command{
+	Use:    "create-snapshot",
+	Short:  "Create the snapshot of state at the current block",
+	PreRun: loadConfig,
+	Run: func(cmd *cobra.Command, args []string) {
+		initBlocksFileDB()
+		defer model.GormClose()
+
+		logger := log.WithFields(log.Fields{"file": snapshotFile})
+		header, err := snapshot.Create(snapshotFile, logger)
+		if err != nil {
+			logger.WithError(err).Fatal("creating snapshot")
+		}
+		logger.WithFields(log.Fields{"block_id": header.BlockID}).Info("snapshot created")
+	},
+}
+
+// restoreSnapshotCmd represents the restore-snapshot command
+var restoreSnapshotCmd = &cobra.Command{
+	Use:    "restore-snapshot",
+	Short:  "Restore the state from the snapshot",
+	PreRun: loadConfigWKey,
+	Run: func(cmd *cobra.Command, args []string) {
+		f := utils.LockOrDie(conf.Config.LockFilePath)
+		defer f.Unlock()
+
+		initBlocksFileDB()
+		defer model.GormClose()
+
+		logger := log.WithFields(log.Fields{"file": snapshotFile})
+		header, err := snapshot.Restore(snapshotFile, !snapshotNoVerify, logger)
+		if err != nil {
+			logger.WithError(err).Fatal("restoring snapshot")
+		}
+		logger.WithFields(log.Fields{"block_id": header.BlockID}).Info("snapshot restored")
+	},
+}
+
+func init() {
+	createSnapshotCmd.Flags().StringVar(&snapshotFile, "file", "", "Path to the snapshot file")
+	createSnapshotCmd.MarkFlagRequired("file")
+
+	restoreSnapshotCmd.Flags().StringVar(&snapshotFile, "file", "", "Path to the snapshot file")
+	restoreSnapshotCmd.Flags().BoolVar(&snapshotNoVerify, "noVerify", false, "Don't check that the snapshot block belongs to the blockchain of the trusted nodes")
+	restoreSnapshotCmd.MarkFlagRequired("file")
+}
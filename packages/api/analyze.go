@@ -0,0 +1,73 @@
+// Apla Software includes an integrated development
+// environment with a multi-level system for the management
+// of access rights to data, interfaces, and Smart contracts. The
+// technical characteristics of the Apla Software are indicated in
+// Apla Technical Paper.
+
+// Apla Users are granted a permission to deal in the Apla
+// Software without restrictions, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of Apla Software, and to permit persons
+// to whom Apla Software is furnished to do so, subject to the
+// following conditions:
+// * the copyright notice of GenesisKernel and EGAAS S.A.
+// and this permission notice shall be included in all copies or
+// substantial portions of the software;
+// * a result of the dealing in Apla Software cannot be
+// implemented outside of the Apla Platform environment.
+
+// THE APLA SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY
+// OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED
+// TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE, ERROR FREE AND NONINFRINGEMENT. IN
+// NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR
+// THE USE OR OTHER DEALINGS IN THE APLA SOFTWARE.
+
+package api
+
+import (
+	"net/http"
+
+	"github.com/AplaProject/go-apla/packages/script"
+	"github.com/AplaProject/go-apla/packages/smart"
+)
+
+type analyzeForm struct {
+	Source string `schema:"source"`
+}
+
+func (f *analyzeForm) Validate(r *http.Request) error {
+	if len(f.Source) == 0 {
+		return errParamNotFound.Errorf("source")
+	}
+	return nil
+}
+
+type analyzeResult struct {
+	Warnings []script.Warning `json:"warnings"`
+	Error    string           `json:"error,omitempty"`
+}
+
+// analyzeHandler checks the contract source without deployment and returns the found warnings
+func analyzeHandler(w http.ResponseWriter, r *http.Request) {
+	form := &analyzeForm{}
+	if err := parseForm(r, form); err != nil {
+		errorResponse(w, err, http.StatusBadRequest)
+		return
+	}
+
+	client := getClient(r)
+	warnings, err := smart.Analyze(form.Source, &script.OwnerInfo{StateID: uint32(client.EcosystemID)})
+	result := &analyzeResult{Warnings: warnings}
+	if result.Warnings == nil {
+		result.Warnings = []script.Warning{}
+	}
+	if err != nil {
+		result.Error = err.Error()
+	}
+
+	jsonResponse(w, result)
+}
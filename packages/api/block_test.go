@@ -29,9 +29,14 @@
 package api
 
 import (
+	"encoding/hex"
+	"net/url"
 	"testing"
 
+	"github.com/AplaProject/go-apla/packages/block"
+
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestGetMaxBlockID(t *testing.T) {
@@ -45,3 +50,30 @@ func TestGetBlockInfo(t *testing.T) {
 	err := sendGet(`block/1`, nil, &ret)
 	assert.NoError(t, err)
 }
+
+func TestGetTxProof(t *testing.T) {
+	var blocks map[int64][]TxInfo
+	require.NoError(t, sendGet(`blocks`, &url.Values{"block_id": {"1"}, "count": {"1"}}, &blocks))
+	require.NotEmpty(t, blocks[1])
+
+	var info blockInfoResult
+	require.NoError(t, sendGet(`block/1`, nil, &info))
+
+	var proof block.TxProof
+	require.NoError(t, sendGet(`txproof/`+hex.EncodeToString(blocks[1][0].Hash), nil, &proof))
+	assert.Equal(t, int64(1), proof.Header.BlockID)
+	assert.NoError(t, block.VerifyTxProof(&proof, info.Hash))
+
+	assert.EqualError(t, sendGet(`txproof/`+hex.EncodeToString(make([]byte, 32)), nil, &proof),
+		`404 {"error":"E_NOTFOUND","msg":"Page not found"}`)
+}
+
+func TestGetFinality(t *testing.T) {
+	var ret finalityResult
+	require.NoError(t, sendGet(`finality`, nil, &ret))
+	assert.True(t, ret.Required > 0)
+
+	require.NoError(t, sendGet(`finality`, &url.Values{"block_id": {"1"}}, &ret))
+	assert.Equal(t, int64(1), ret.BlockID)
+	assert.NotEmpty(t, ret.Hash)
+}
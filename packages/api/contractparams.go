@@ -0,0 +1,123 @@
+// Apla Software includes an integrated development
+// environment with a multi-level system for the management
+// of access rights to data, interfaces, and Smart contracts. The
+// technical characteristics of the Apla Software are indicated in
+// Apla Technical Paper.
+
+// Apla Users are granted a permission to deal in the Apla
+// Software without restrictions, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of Apla Software, and to permit persons
+// to whom Apla Software is furnished to do so, subject to the
+// following conditions:
+// * the copyright notice of GenesisKernel and EGAAS S.A.
+// and this permission notice shall be included in all copies or
+// substantial portions of the software;
+// * a result of the dealing in Apla Software cannot be
+// implemented outside of the Apla Platform environment.
+
+// THE APLA SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY
+// OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED
+// TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE, ERROR FREE AND NONINFRINGEMENT. IN
+// NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR
+// THE USE OR OTHER DEALINGS IN THE APLA SOFTWARE.
+
+package api
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/AplaProject/go-apla/packages/converter"
+	"github.com/AplaProject/go-apla/packages/script"
+)
+
+// parseContractParams converts the json object of contract parameters to the values
+// which are expected by the fields of contract
+func parseContractParams(fields []*script.FieldInfo, data string) (map[string]interface{}, error) {
+	values := make(map[string]interface{})
+	if len(strings.TrimSpace(data)) > 0 {
+		dec := json.NewDecoder(strings.NewReader(data))
+		dec.UseNumber()
+		if err := dec.Decode(&values); err != nil {
+			return nil, err
+		}
+	}
+
+	params := make(map[string]interface{})
+	for _, field := range fields {
+		v, ok := values[field.Name]
+		if !ok {
+			continue
+		}
+		value, err := contractParam(field.Original, v)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid param '%s': %s", field.Name, err)
+		}
+		params[field.Name] = value
+	}
+	return params, nil
+}
+
+func contractParam(original uint32, v interface{}) (interface{}, error) {
+	switch original {
+	case script.DtInt, script.DtAddress:
+		switch val := v.(type) {
+		case json.Number:
+			return val.Int64()
+		case string:
+			if original == script.DtAddress {
+				return converter.StringToAddress(val), nil
+			}
+			return converter.StrToInt64(val), nil
+		}
+	case script.DtFloat:
+		switch val := v.(type) {
+		case json.Number:
+			return val.Float64()
+		case string:
+			return converter.StrToFloat64(val), nil
+		}
+	case script.DtMoney:
+		switch val := v.(type) {
+		case json.Number:
+			return val.String(), nil
+		case string:
+			return val, nil
+		}
+	case script.DtBool:
+		if val, ok := v.(bool); ok {
+			return val, nil
+		}
+	case script.DtString:
+		switch val := v.(type) {
+		case json.Number:
+			return val.String(), nil
+		case string:
+			return val, nil
+		}
+	case script.DtBytes:
+		if val, ok := v.(string); ok {
+			return hex.DecodeString(val)
+		}
+	case script.DtArray:
+		if val, ok := v.([]interface{}); ok {
+			return val, nil
+		}
+	case script.DtMap:
+		if val, ok := v.(map[string]interface{}); ok {
+			ret := make(map[interface{}]interface{}, len(val))
+			for key, item := range val {
+				ret[key] = item
+			}
+			return ret, nil
+		}
+	}
+	return nil, fmt.Errorf("unsupported value %v for type %s", v, script.OriginalToString(original))
+}
@@ -0,0 +1,120 @@
+// Apla Software includes an integrated development
+// environment with a multi-level system for the management
+// of access rights to data, interfaces, and Smart contracts. The
+// technical characteristics of the Apla Software are indicated in
+// Apla Technical Paper.
+
+// Apla Users are granted a permission to deal in the Apla
+// Software without restrictions, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of Apla Software, and to permit persons
+// to whom Apla Software is furnished to do so, subject to the
+// following conditions:
+// * the copyright notice of GenesisKernel and EGAAS S.A.
+// and this permission notice shall be included in all copies or
+// substantial portions of the software;
+// * a result of the dealing in Apla Software cannot be
+// implemented outside of the Apla Platform environment.
+
+// THE APLA SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY
+// OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED
+// TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE, ERROR FREE AND NONINFRINGEMENT. IN
+// NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR
+// THE USE OR OTHER DEALINGS IN THE APLA SOFTWARE.
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/AplaProject/go-apla/packages/consts"
+	"github.com/AplaProject/go-apla/packages/script"
+	"github.com/AplaProject/go-apla/packages/smart"
+	"github.com/AplaProject/go-apla/packages/utils/tx"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const maxDebugSteps = 100000
+
+type debugContractForm struct {
+	Contract string `schema:"contract"`
+	Params   string `schema:"params"`
+	MaxSteps int    `schema:"max_steps"`
+}
+
+func (f *debugContractForm) Validate(r *http.Request) error {
+	if len(f.Contract) == 0 {
+		return errParamNotFound.Errorf("contract")
+	}
+	if f.MaxSteps <= 0 || f.MaxSteps > maxDebugSteps {
+		f.MaxSteps = script.DefaultTraceSteps
+	}
+	return nil
+}
+
+type debugContractResult struct {
+	Result  string         `json:"result,omitempty"`
+	Message *txstatusError `json:"errmsg,omitempty"`
+	Fuel    int64          `json:"fuel"`
+	Trace   *script.Tracer `json:"trace"`
+}
+
+// contractError converts the error of contract execution to the format of transaction status
+func contractError(err error) *txstatusError {
+	msg := &txstatusError{}
+	if json.Unmarshal([]byte(err.Error()), msg) != nil || len(msg.Error) == 0 {
+		msg = &txstatusError{Type: "panic", Error: err.Error()}
+	}
+	return msg
+}
+
+// debugContractHandler runs the contract against the current state in a rolled-back
+// database transaction and returns the trace of executed bytecode
+func debugContractHandler(w http.ResponseWriter, r *http.Request) {
+	form := &debugContractForm{}
+	if err := parseForm(r, form); err != nil {
+		errorResponse(w, err, http.StatusBadRequest)
+		return
+	}
+
+	client := getClient(r)
+	logger := getLogger(r)
+
+	contract := getContract(r, form.Contract)
+	if contract == nil {
+		logger.WithFields(log.Fields{"type": consts.ContractError, "contract_name": form.Contract}).Error("contract name")
+		errorResponse(w, errContract.Errorf(form.Contract))
+		return
+	}
+
+	var fields []*script.FieldInfo
+	if info := getContractInfo(contract).Tx; info != nil {
+		fields = *info
+	}
+	params, err := parseContractParams(fields, form.Params)
+	if err != nil {
+		errorResponse(w, err, http.StatusBadRequest)
+		return
+	}
+
+	header := tx.Header{
+		ID:          int(getContractInfo(contract).Owner.TableID + consts.ShiftContractID),
+		Time:        time.Now().Unix(),
+		EcosystemID: client.EcosystemID,
+		KeyID:       client.KeyID,
+	}
+	result := &debugContractResult{Trace: script.NewTracer(form.MaxSteps)}
+	result.Result, result.Fuel, err = smart.DryRun(contract, header, params, result.Trace)
+	if err != nil {
+		result.Message = contractError(err)
+	}
+
+	jsonResponse(w, result)
+}
@@ -0,0 +1,68 @@
+// Apla Software includes an integrated development
+// environment with a multi-level system for the management
+// of access rights to data, interfaces, and Smart contracts. The
+// technical characteristics of the Apla Software are indicated in
+// Apla Technical Paper.
+
+// Apla Users are granted a permission to deal in the Apla
+// Software without restrictions, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of Apla Software, and to permit persons
+// to whom Apla Software is furnished to do so, subject to the
+// following conditions:
+// * the copyright notice of GenesisKernel and EGAAS S.A.
+// and this permission notice shall be included in all copies or
+// substantial portions of the software;
+// * a result of the dealing in Apla Software cannot be
+// implemented outside of the Apla Platform environment.
+
+// THE APLA SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY
+// OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED
+// TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE, ERROR FREE AND NONINFRINGEMENT. IN
+// NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR
+// THE USE OR OTHER DEALINGS IN THE APLA SOFTWARE.
+
+package api
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/AplaProject/go-apla/packages/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDebugContract(t *testing.T) {
+	require.NoError(t, keyLogin(1))
+
+	rnd := `debug` + crypto.RandSeq(4)
+	form := url.Values{`Value`: {`contract ` + rnd + ` {
+	data {
+		Value int
+	}
+	action {
+		var i int
+		i = $Value * 2
+		$result = i
+	}
+}`}, "ApplicationId": {"1"}, `Conditions`: {`true`}}
+	require.NoError(t, postTx(`NewContract`, &form))
+
+	var ret debugContractResult
+	require.NoError(t, sendPost(`debug/contract`, &url.Values{`contract`: {rnd},
+		`params`: {`{"Value": 21}`}}, &ret))
+	assert.Nil(t, ret.Message)
+	assert.Equal(t, `42`, ret.Result)
+	assert.True(t, ret.Fuel > 0)
+	require.NotEmpty(t, ret.Trace.Steps)
+	assert.Equal(t, `@1`+rnd+`.action`, ret.Trace.Steps[0].Func)
+
+	require.NoError(t, sendPost(`debug/contract`, &url.Values{`contract`: {rnd},
+		`params`: {`{"Value": "abc"}`}, `max_steps`: {`1`}}, &ret))
+	assert.True(t, ret.Trace.Truncated)
+}
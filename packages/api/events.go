@@ -0,0 +1,118 @@
+// Apla Software includes an integrated development
+// environment with a multi-level system for the management
+// of access rights to data, interfaces, and Smart contracts. The
+// technical characteristics of the Apla Software are indicated in
+// Apla Technical Paper.
+
+// Apla Users are granted a permission to deal in the Apla
+// Software without restrictions, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of Apla Software, and to permit persons
+// to whom Apla Software is furnished to do so, subject to the
+// following conditions:
+// * the copyright notice of GenesisKernel and EGAAS S.A.
+// and this permission notice shall be included in all copies or
+// substantial portions of the software;
+// * a result of the dealing in Apla Software cannot be
+// implemented outside of the Apla Platform environment.
+
+// THE APLA SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY
+// OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED
+// TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE, ERROR FREE AND NONINFRINGEMENT. IN
+// NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR
+// THE USE OR OTHER DEALINGS IN THE APLA SOFTWARE.
+
+package api
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+
+	"github.com/AplaProject/go-apla/packages/consts"
+	"github.com/AplaProject/go-apla/packages/model"
+
+	log "github.com/sirupsen/logrus"
+)
+
+type eventItem struct {
+	TxHash    string          `json:"tx_hash"`
+	Idx       int64           `json:"idx"`
+	BlockID   int64           `json:"block_id"`
+	Ecosystem int64           `json:"ecosystem"`
+	Contract  string          `json:"contract"`
+	Name      string          `json:"name"`
+	Data      json.RawMessage `json:"data"`
+	Time      int64           `json:"time"`
+}
+
+type eventsResult struct {
+	Count int64       `json:"count"`
+	List  []eventItem `json:"list"`
+}
+
+type eventsForm struct {
+	ecosystemForm
+	paginatorForm
+	Contract  string `schema:"contract"`
+	Name      string `schema:"name"`
+	FromBlock int64  `schema:"from_block"`
+	ToBlock   int64  `schema:"to_block"`
+}
+
+func (f *eventsForm) Validate(r *http.Request) error {
+	if err := f.paginatorForm.Validate(r); err != nil {
+		return err
+	}
+	return f.ecosystemForm.Validate(r)
+}
+
+func (m Mode) getEventsHandler(w http.ResponseWriter, r *http.Request) {
+	form := &eventsForm{
+		ecosystemForm: ecosystemForm{
+			Validator: m.EcosysIDValidator,
+		},
+	}
+	if err := parseForm(r, form); err != nil {
+		errorResponse(w, err, http.StatusBadRequest)
+		return
+	}
+
+	logger := getLogger(r)
+
+	events, count, err := model.GetEvents(&model.EventFilter{
+		Ecosystem: form.EcosystemID,
+		Contract:  form.Contract,
+		Name:      form.Name,
+		FromBlock: form.FromBlock,
+		ToBlock:   form.ToBlock,
+	}, form.Limit, form.Offset)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting events")
+		errorResponse(w, err)
+		return
+	}
+
+	result := &eventsResult{
+		Count: count,
+		List:  make([]eventItem, 0, len(events)),
+	}
+	for _, item := range events {
+		result.List = append(result.List, eventItem{
+			TxHash:    hex.EncodeToString(item.TxHash),
+			Idx:       item.Idx,
+			BlockID:   item.BlockID,
+			Ecosystem: item.Ecosystem,
+			Contract:  item.Contract,
+			Name:      item.Name,
+			Data:      json.RawMessage(item.Data),
+			Time:      item.Time,
+		})
+	}
+
+	jsonResponse(w, result)
+}
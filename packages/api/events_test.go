@@ -0,0 +1,97 @@
+// Apla Software includes an integrated development
+// environment with a multi-level system for the management
+// of access rights to data, interfaces, and Smart contracts. The
+// technical characteristics of the Apla Software are indicated in
+// Apla Technical Paper.
+
+// Apla Users are granted a permission to deal in the Apla
+// Software without restrictions, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of Apla Software, and to permit persons
+// to whom Apla Software is furnished to do so, subject to the
+// following conditions:
+// * the copyright notice of GenesisKernel and EGAAS S.A.
+// and this permission notice shall be included in all copies or
+// substantial portions of the software;
+// * a result of the dealing in Apla Software cannot be
+// implemented outside of the Apla Platform environment.
+
+// THE APLA SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY
+// OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED
+// TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE, ERROR FREE AND NONINFRINGEMENT. IN
+// NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR
+// THE USE OR OTHER DEALINGS IN THE APLA SOFTWARE.
+
+package api
+
+import (
+	"encoding/json"
+	"net/url"
+	"testing"
+
+	"github.com/AplaProject/go-apla/packages/crypto"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvents(t *testing.T) {
+	require.NoError(t, keyLogin(1))
+
+	rnd := `event` + crypto.RandSeq(4)
+	form := url.Values{`Value`: {`contract ` + rnd + ` {
+	data {
+		Value string
+	}
+	action {
+		EmitEvent("Paid", {"value": $Value, "amount": 10})
+		try {
+			EmitEvent("Skipped", {})
+			error "skip"
+		} catch err {
+		}
+		EmitEvent("Done", {})
+	}
+}`}, "ApplicationId": {"1"}, `Conditions`: {`true`}}
+	require.NoError(t, postTx(`NewContract`, &form))
+	require.NoError(t, postTx(rnd, &url.Values{`Value`: {rnd}}))
+
+	var ret eventsResult
+	require.NoError(t, sendGet(`events`, &url.Values{`contract`: {`@1` + rnd}}, &ret))
+	require.Equal(t, int64(2), ret.Count)
+	assert.Equal(t, `Paid`, ret.List[0].Name)
+	assert.Equal(t, `Done`, ret.List[1].Name)
+	var data map[string]interface{}
+	require.NoError(t, json.Unmarshal(ret.List[0].Data, &data))
+	assert.Equal(t, rnd, data[`value`])
+
+	require.NoError(t, sendGet(`events`, &url.Values{`name`: {`Paid`},
+		`from_block`: {`1`}, `to_block`: {`1`}}, &ret))
+	assert.Equal(t, int64(0), ret.Count)
+
+	form = url.Values{`Value`: {`contract ` + rnd + `Wrong {
+	action {
+		EmitEvent("Wrong name", {})
+	}
+}`}, "ApplicationId": {"1"}, `Conditions`: {`true`}}
+	require.NoError(t, postTx(`NewContract`, &form))
+	assert.EqualError(t, postTx(rnd+`Wrong`, &url.Values{}),
+		`{"type":"panic","error":"Incorrect event name Wrong name"}`)
+
+	form = url.Values{`Value`: {`contract ` + rnd + `Many {
+	action {
+		var i int
+		while i <= 100 {
+			EmitEvent("Many", {})
+			i = i + 1
+		}
+	}
+}`}, "ApplicationId": {"1"}, `Conditions`: {`true`}}
+	require.NoError(t, postTx(`NewContract`, &form))
+	assert.EqualError(t, postTx(rnd+`Many`, &url.Values{}),
+		`{"type":"panic","error":"The number of events is more than 100"}`)
+}
@@ -0,0 +1,117 @@
+// Apla Software includes an integrated development
+// environment with a multi-level system for the management
+// of access rights to data, interfaces, and Smart contracts. The
+// technical characteristics of the Apla Software are indicated in
+// Apla Technical Paper.
+
+// Apla Users are granted a permission to deal in the Apla
+// Software without restrictions, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of Apla Software, and to permit persons
+// to whom Apla Software is furnished to do so, subject to the
+// following conditions:
+// * the copyright notice of GenesisKernel and EGAAS S.A.
+// and this permission notice shall be included in all copies or
+// substantial portions of the software;
+// * a result of the dealing in Apla Software cannot be
+// implemented outside of the Apla Platform environment.
+
+// THE APLA SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY
+// OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED
+// TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE, ERROR FREE AND NONINFRINGEMENT. IN
+// NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR
+// THE USE OR OTHER DEALINGS IN THE APLA SOFTWARE.
+
+package api
+
+import (
+	"net/http"
+
+	"github.com/AplaProject/go-apla/packages/block"
+	"github.com/AplaProject/go-apla/packages/conf/syspar"
+	"github.com/AplaProject/go-apla/packages/consts"
+	"github.com/AplaProject/go-apla/packages/model"
+
+	log "github.com/sirupsen/logrus"
+)
+
+type finalitySign struct {
+	KeyID     int64  `json:"key_id"`
+	Signature []byte `json:"signature"`
+}
+
+type finalityResult struct {
+	FinalizedBlockID int64          `json:"finalized_block_id"`
+	FullNodes        int            `json:"full_nodes"`
+	Required         int            `json:"required"`
+	BlockID          int64          `json:"block_id"`
+	Hash             []byte         `json:"hash"`
+	Final            bool           `json:"final"`
+	Signatures       []finalitySign `json:"signatures"`
+}
+
+type finalityForm struct {
+	nopeValidator
+	BlockID int64 `schema:"block_id"`
+}
+
+func getFinalityHandler(w http.ResponseWriter, r *http.Request) {
+	form := &finalityForm{}
+	if err := parseForm(r, form); err != nil {
+		errorResponse(w, err, http.StatusBadRequest)
+		return
+	}
+
+	logger := getLogger(r)
+
+	finalized, err := block.GetFinalizedBlockID()
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	result := &finalityResult{
+		FinalizedBlockID: finalized,
+		FullNodes:        len(syspar.GetNodes()),
+		Required:         block.FinalityRequired(),
+		BlockID:          form.BlockID,
+		Signatures:       make([]finalitySign, 0),
+	}
+	if result.BlockID == 0 {
+		result.BlockID = finalized
+	}
+	if result.BlockID == 0 {
+		jsonResponse(w, result)
+		return
+	}
+
+	b := &model.Block{}
+	found, err := b.Get(result.BlockID)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting block")
+		errorResponse(w, err)
+		return
+	}
+	if !found {
+		errorResponse(w, errNotFound)
+		return
+	}
+	result.Hash = b.Hash
+	result.Final = result.BlockID <= finalized
+
+	signs, err := model.GetConfirmationSignatures(b.ID, b.Hash)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting confirmation signatures")
+		errorResponse(w, err)
+		return
+	}
+	for _, s := range signs {
+		result.Signatures = append(result.Signatures, finalitySign{KeyID: s.KeyID, Signature: s.Signature})
+	}
+
+	jsonResponse(w, result)
+}
@@ -42,8 +42,8 @@ import (
 	"github.com/AplaProject/go-apla/packages/model"
 	"github.com/AplaProject/go-apla/packages/publisher"
 	"github.com/AplaProject/go-apla/packages/script"
+	"github.com/AplaProject/go-apla/packages/signer"
 	"github.com/AplaProject/go-apla/packages/smart"
-	"github.com/AplaProject/go-apla/packages/utils"
 	"github.com/AplaProject/go-apla/packages/utils/tx"
 
 	jwt "github.com/dgrijalva/jwt-go"
@@ -57,6 +57,7 @@ type loginForm struct {
 	EcosystemID int64          `schema:"ecosystem"`
 	Expire      int64          `schema:"expire"`
 	PublicKey   publicKeyValue `schema:"pubkey"`
+	KeyType     int64          `schema:"key_type"`
 	KeyID       string         `schema:"key_id"`
 	Signature   hexValue       `schema:"signature"`
 	RoleID      int64          `schema:"role_id"`
@@ -77,6 +78,9 @@ func (f *loginForm) Validate(r *http.Request) error {
 	if f.Expire == 0 {
 		f.Expire = int64(jwtExpire)
 	}
+	if !crypto.KeyType(f.KeyType).IsValid() {
+		return crypto.ErrUnknownKeyType
+	}
 
 	return nil
 }
@@ -102,6 +106,7 @@ type rolesResult struct {
 func (m Mode) loginHandler(w http.ResponseWriter, r *http.Request) {
 	var (
 		publicKey []byte
+		keyType   crypto.KeyType
 		wallet    int64
 		uid       string
 		err       error
@@ -149,6 +154,7 @@ func (m Mode) loginHandler(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 		publicKey = account.PublicKey
+		keyType = crypto.KeyType(account.KeyType)
 	} else {
 		if !allowCreateUser(client) {
 			errorResponse(w, errKeyNotFound)
@@ -156,22 +162,13 @@ func (m Mode) loginHandler(w http.ResponseWriter, r *http.Request) {
 		}
 
 		publicKey = form.PublicKey.Bytes()
+		keyType = crypto.KeyType(form.KeyType)
 		if len(publicKey) == 0 {
 			logger.WithFields(log.Fields{"type": consts.EmptyObject}).Error("public key is empty")
 			errorResponse(w, errEmptyPublic)
 			return
 		}
 
-		nodePrivateKey, err := utils.GetNodePrivateKey()
-		if err != nil || len(nodePrivateKey) < 1 {
-			if err == nil {
-				log.WithFields(log.Fields{"type": consts.EmptyObject}).Error("node private key is empty")
-			}
-
-			errorResponse(w, err)
-			return
-		}
-
 		contract := smart.GetContract("NewUser", 1)
 		sc := tx.SmartContract{
 			Header: tx.Header{
@@ -186,7 +183,11 @@ func (m Mode) loginHandler(w http.ResponseWriter, r *http.Request) {
 			},
 		}
 
-		txData, txHash, err := tx.NewInternalTransaction(sc, nodePrivateKey)
+		if keyType != crypto.KeyTypeP256 {
+			sc.Params["KeyType"] = int64(keyType)
+		}
+
+		txData, txHash, err := tx.NewInternalTransaction(sc, signer.Node())
 		if err != nil {
 			log.WithFields(log.Fields{"type": consts.ContractError}).Error("Building transaction")
 		} else {
@@ -223,7 +224,7 @@ func (m Mode) loginHandler(w http.ResponseWriter, r *http.Request) {
 		client.RoleID = checkedRole
 	}
 
-	verify, err := crypto.CheckSign(publicKey, []byte(nonceSalt+uid), form.Signature.Bytes())
+	verify, err := keyType.CheckSign(publicKey, []byte(nonceSalt+uid), form.Signature.Bytes())
 	if err != nil {
 		logger.WithFields(log.Fields{"type": consts.CryptoError, "pubkey": publicKey, "uid": uid, "signature": form.Signature.Bytes()}).Error("checking signature")
 		errorResponse(w, err)
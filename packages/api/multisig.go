@@ -0,0 +1,178 @@
+// Apla Software includes an integrated development
+// environment with a multi-level system for the management
+// of access rights to data, interfaces, and Smart contracts. The
+// technical characteristics of the Apla Software are indicated in
+// Apla Technical Paper.
+
+// Apla Users are granted a permission to deal in the Apla
+// Software without restrictions, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of Apla Software, and to permit persons
+// to whom Apla Software is furnished to do so, subject to the
+// following conditions:
+// * the copyright notice of GenesisKernel and EGAAS S.A.
+// and this permission notice shall be included in all copies or
+// substantial portions of the software;
+// * a result of the dealing in Apla Software cannot be
+// implemented outside of the Apla Platform environment.
+
+// THE APLA SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY
+// OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED
+// TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE, ERROR FREE AND NONINFRINGEMENT. IN
+// NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR
+// THE USE OR OTHER DEALINGS IN THE APLA SOFTWARE.
+
+package api
+
+import (
+	"bytes"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	"github.com/AplaProject/go-apla/packages/consts"
+	"github.com/AplaProject/go-apla/packages/converter"
+	"github.com/AplaProject/go-apla/packages/model"
+	"github.com/AplaProject/go-apla/packages/smart"
+	"github.com/AplaProject/go-apla/packages/transaction"
+	"github.com/AplaProject/go-apla/packages/utils/tx"
+
+	"github.com/gorilla/mux"
+	log "github.com/sirupsen/logrus"
+	msgpack "gopkg.in/vmihailenco/msgpack.v2"
+)
+
+type multisigPendingItem struct {
+	Hash      string                 `json:"hash"`
+	KeyID     string                 `json:"key_id"`
+	Contract  string                 `json:"contract"`
+	Params    map[string]interface{} `json:"params,omitempty"`
+	Threshold int64                  `json:"threshold"`
+	Signers   []string               `json:"signers"`
+	Time      int64                  `json:"time"`
+}
+
+type multisigPendingResult struct {
+	Count int64                 `json:"count"`
+	List  []multisigPendingItem `json:"list"`
+}
+
+type multisigSignForm struct {
+	nopeValidator
+	Signature string `schema:"signature"`
+}
+
+type multisigSignResult struct {
+	Hash string `json:"hash"`
+	Sent bool   `json:"sent"`
+}
+
+func multisigContract(data []byte) (string, map[string]interface{}, error) {
+	rtx := &transaction.RawTransaction{}
+	if err := rtx.Unmarshall(bytes.NewBuffer(data)); err != nil {
+		return ``, nil, err
+	}
+	smartTx := tx.SmartContract{}
+	if err := msgpack.Unmarshal(rtx.Payload(), &smartTx); err != nil {
+		return ``, nil, err
+	}
+	contract := smart.GetContractByID(int32(smartTx.ID))
+	if contract == nil {
+		return ``, smartTx.Params, nil
+	}
+	return contract.Name, smartTx.Params, nil
+}
+
+func getMultisigPendingHandler(w http.ResponseWriter, r *http.Request) {
+	form := &paginatorForm{}
+	if err := parseForm(r, form); err != nil {
+		errorResponse(w, err, http.StatusBadRequest)
+		return
+	}
+
+	client := getClient(r)
+	logger := getLogger(r)
+
+	accounts, err := model.GetMultisigAccounts(client.EcosystemID, client.KeyID)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting multi-signature accounts")
+		errorResponse(w, err)
+		return
+	}
+	txs, count, err := model.GetMultisigPending(client.EcosystemID, accounts, form.Limit, form.Offset)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting pending multi-signature transactions")
+		errorResponse(w, err)
+		return
+	}
+
+	result := &multisigPendingResult{
+		Count: count,
+		List:  make([]multisigPendingItem, 0, len(txs)),
+	}
+	for _, item := range txs {
+		contract, params, err := multisigContract(item.Data)
+		if err != nil {
+			logger.WithFields(log.Fields{"type": consts.UnmarshallingError, "error": err}).Error("parsing pending multi-signature transaction")
+			errorResponse(w, err)
+			return
+		}
+		result.List = append(result.List, multisigPendingItem{
+			Hash:      hex.EncodeToString(item.Hash),
+			KeyID:     converter.Int64ToStr(item.KeyID),
+			Contract:  contract,
+			Params:    params,
+			Threshold: item.Threshold,
+			Signers:   strings.Split(item.Signers, `,`),
+			Time:      item.Time,
+		})
+	}
+
+	jsonResponse(w, result)
+}
+
+func multisigSignHandler(w http.ResponseWriter, r *http.Request) {
+	form := &multisigSignForm{}
+	if err := parseForm(r, form); err != nil {
+		errorResponse(w, err, http.StatusBadRequest)
+		return
+	}
+
+	client := getClient(r)
+	logger := getLogger(r)
+
+	params := mux.Vars(r)
+	hash, err := hex.DecodeString(params["hash"])
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.ConversionError, "error": err}).Error("decoding tx hash from hex")
+		errorResponse(w, errHashWrong)
+		return
+	}
+	sign, err := hex.DecodeString(form.Signature)
+	if err != nil || len(sign) == 0 {
+		logger.WithFields(log.Fields{"type": consts.ConversionError, "error": err}).Error("decoding signature from hex")
+		errorResponse(w, errEmptySign)
+		return
+	}
+
+	sent, err := transaction.SignMultisig(hash, sign, client.KeyID)
+	if err != nil {
+		switch err {
+		case transaction.ErrMultisigPending:
+			err = errHashNotFound
+		case transaction.ErrMultisigSigner:
+			err = errPermission
+		}
+		errorResponse(w, err)
+		return
+	}
+
+	jsonResponse(w, &multisigSignResult{
+		Hash: params["hash"],
+		Sent: sent,
+	})
+}
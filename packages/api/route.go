@@ -68,6 +68,7 @@ func (m Mode) SetCommonRoutes(r Router) {
 
 	api.HandleFunc("/contract/{name}", authRequire(getContractInfoHandler)).Methods("GET")
 	api.HandleFunc("/contracts", authRequire(getContractsHandler)).Methods("GET")
+	api.HandleFunc("/analyze", authRequire(analyzeHandler)).Methods("POST")
 	api.HandleFunc("/getuid", getUIDHandler).Methods("GET")
 	api.HandleFunc("/keyinfo/{wallet}", m.getKeyInfoHandler).Methods("GET")
 	api.HandleFunc("/list/{name}", authRequire(getListHandler)).Methods("GET")
@@ -110,14 +111,24 @@ func (m Mode) SetBlockchainRoutes(r Router) {
 	api.HandleFunc("/history/{name}/{id}", authRequire(getHistoryHandler)).Methods("GET")
 	api.HandleFunc("/balance/{wallet}", authRequire(m.getBalanceHandler)).Methods("GET")
 	api.HandleFunc("/block/{id}", getBlockInfoHandler).Methods("GET")
+	api.HandleFunc("/txproof/{hash}", getTxProofHandler).Methods("GET")
+	api.HandleFunc("/finality", getFinalityHandler).Methods("GET")
+	api.HandleFunc("/txpool", authRequire(getTxPoolHandler)).Methods("GET")
+	api.HandleFunc("/multisig/pending", authRequire(getMultisigPendingHandler)).Methods("GET")
+	api.HandleFunc("/multisig/sign/{hash}", authRequire(multisigSignHandler)).Methods("POST")
 	api.HandleFunc("/maxblockid", getMaxBlockHandler).Methods("GET")
 	api.HandleFunc("/blocks", getBlocksTxInfoHandler).Methods("GET")
 	api.HandleFunc("/detailed_blocks", getBlocksDetailedInfoHandler).Methods("GET")
 	api.HandleFunc("/ecosystemparams", authRequire(m.getEcosystemParamsHandler)).Methods("GET")
 	api.HandleFunc("/systemparams", authRequire(getSystemParamsHandler)).Methods("GET")
+	api.HandleFunc("/fuelschedule", authRequire(getFuelScheduleHandler)).Methods("GET")
+	api.HandleFunc("/events", authRequire(m.getEventsHandler)).Methods("GET")
 	api.HandleFunc("/ecosystems", authRequire(getEcosystemsHandler)).Methods("GET")
 	api.HandleFunc("/ecosystemparam/{name}", authRequire(m.getEcosystemParamHandler)).Methods("GET")
 	api.HandleFunc("/ecosystemname", getEcosystemNameHandler).Methods("GET")
+	api.HandleFunc("/stream", authRequire(streamHandler)).Methods("GET")
+	api.HandleFunc("/debug/contract", authRequire(debugContractHandler)).Methods("POST")
+	api.HandleFunc("/simulate", authRequire(simulateHandler)).Methods("POST")
 }
 
 func NewRouter(m Mode) Router {
@@ -0,0 +1,150 @@
+// Apla Software includes an integrated development
+// environment with a multi-level system for the management
+// of access rights to data, interfaces, and Smart contracts. The
+// technical characteristics of the Apla Software are indicated in
+// Apla Technical Paper.
+
+// Apla Users are granted a permission to deal in the Apla
+// Software without restrictions, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of Apla Software, and to permit persons
+// to whom Apla Software is furnished to do so, subject to the
+// following conditions:
+// * the copyright notice of GenesisKernel and EGAAS S.A.
+// and this permission notice shall be included in all copies or
+// substantial portions of the software;
+// * a result of the dealing in Apla Software cannot be
+// implemented outside of the Apla Platform environment.
+
+// THE APLA SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY
+// OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED
+// TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE, ERROR FREE AND NONINFRINGEMENT. IN
+// NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR
+// THE USE OR OTHER DEALINGS IN THE APLA SOFTWARE.
+
+package api
+
+import (
+	"encoding/hex"
+	"net/http"
+	"time"
+
+	"github.com/AplaProject/go-apla/packages/block"
+	"github.com/AplaProject/go-apla/packages/consts"
+	"github.com/AplaProject/go-apla/packages/converter"
+	"github.com/AplaProject/go-apla/packages/script"
+	"github.com/AplaProject/go-apla/packages/transaction"
+	"github.com/AplaProject/go-apla/packages/utils/tx"
+
+	log "github.com/sirupsen/logrus"
+)
+
+type simulateForm struct {
+	Contract       string `schema:"contract"`
+	Params         string `schema:"params"`
+	KeyID          int64  `schema:"key_id"`
+	PublicKey      string `schema:"pubkey"`
+	TokenEcosystem int64  `schema:"token_ecosystem"`
+	MaxSum         string `schema:"max_sum"`
+	PayOver        string `schema:"pay_over"`
+
+	publicKey []byte
+}
+
+func (f *simulateForm) Validate(r *http.Request) error {
+	if len(f.Contract) == 0 {
+		return errParamNotFound.Errorf("contract")
+	}
+	if f.KeyID != 0 && f.KeyID != getClient(r).KeyID {
+		return errDiffKey
+	}
+	if len(f.PublicKey) > 0 {
+		var err error
+		if f.publicKey, err = hex.DecodeString(f.PublicKey); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type simulateResult struct {
+	Hash     string                   `json:"hash"`
+	Result   string                   `json:"result,omitempty"`
+	Message  *txstatusError           `json:"errmsg,omitempty"`
+	Fuel     int64                    `json:"fuel"`
+	FuelStat *script.FuelStat         `json:"fuelstat"`
+	Payment  string                   `json:"payment"`
+	Rows     []transaction.ChangedRow `json:"rows"`
+}
+
+// simulateHandler executes the unsigned contract call of the client in the database transaction
+// which is rolled back and returns the result with the spent fuel, the payment and changed rows
+func simulateHandler(w http.ResponseWriter, r *http.Request) {
+	form := &simulateForm{}
+	if err := parseForm(r, form); err != nil {
+		errorResponse(w, err, http.StatusBadRequest)
+		return
+	}
+
+	client := getClient(r)
+	logger := getLogger(r)
+
+	if block.IsKeyBanned(client.KeyID) {
+		errorResponse(w, errBannded.Errorf(block.BannedTill(client.KeyID)))
+		return
+	}
+
+	contract := getContract(r, form.Contract)
+	if contract == nil {
+		logger.WithFields(log.Fields{"type": consts.ContractError, "contract_name": form.Contract}).Error("contract name")
+		errorResponse(w, errContract.Errorf(form.Contract))
+		return
+	}
+	info := getContractInfo(contract)
+
+	var fields []*script.FieldInfo
+	if info.Tx != nil {
+		fields = *info.Tx
+	}
+	params, err := parseContractParams(fields, form.Params)
+	if err != nil {
+		errorResponse(w, err, http.StatusBadRequest)
+		return
+	}
+
+	sim, err := transaction.Simulate(&tx.SmartContract{
+		Header: tx.Header{
+			ID:          int(info.Owner.TableID + consts.ShiftContractID),
+			Time:        time.Now().Unix(),
+			EcosystemID: client.EcosystemID,
+			KeyID:       client.KeyID,
+			NetworkID:   consts.NETWORK_ID,
+			PublicKey:   form.publicKey,
+		},
+		TokenEcosystem: form.TokenEcosystem,
+		MaxSum:         form.MaxSum,
+		PayOver:        form.PayOver,
+		Params:         params,
+	})
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	result := &simulateResult{
+		Hash:     string(converter.BinToHex(sim.Hash)),
+		Result:   sim.Result,
+		Fuel:     sim.Fuel,
+		FuelStat: sim.FuelStat,
+		Payment:  sim.Payment.String(),
+		Rows:     sim.Rows,
+	}
+	if sim.Error != nil {
+		result.Message = contractError(sim.Error)
+	}
+	jsonResponse(w, result)
+}
@@ -0,0 +1,68 @@
+// Apla Software includes an integrated development
+// environment with a multi-level system for the management
+// of access rights to data, interfaces, and Smart contracts. The
+// technical characteristics of the Apla Software are indicated in
+// Apla Technical Paper.
+
+// Apla Users are granted a permission to deal in the Apla
+// Software without restrictions, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of Apla Software, and to permit persons
+// to whom Apla Software is furnished to do so, subject to the
+// following conditions:
+// * the copyright notice of GenesisKernel and EGAAS S.A.
+// and this permission notice shall be included in all copies or
+// substantial portions of the software;
+// * a result of the dealing in Apla Software cannot be
+// implemented outside of the Apla Platform environment.
+
+// THE APLA SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY
+// OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED
+// TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE, ERROR FREE AND NONINFRINGEMENT. IN
+// NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR
+// THE USE OR OTHER DEALINGS IN THE APLA SOFTWARE.
+
+package api
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/AplaProject/go-apla/packages/crypto"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSimulate(t *testing.T) {
+	require.NoError(t, keyLogin(1))
+
+	rnd := `sim` + crypto.RandSeq(4)
+	form := url.Values{`Value`: {`contract ` + rnd + ` {
+	data {
+		Value string
+	}
+	action {
+		DBInsert("notifications", "page_name", $Value)
+		$result = $Value
+	}
+}`}, "ApplicationId": {"1"}, `Conditions`: {`true`}}
+	require.NoError(t, postTx(`NewContract`, &form))
+
+	var res simulateResult
+	require.NoError(t, sendPost("simulate", &url.Values{
+		"contract": {rnd},
+		"params":   {`{"Value":"` + rnd + `"}`},
+	}, &res))
+	assert.Nil(t, res.Message)
+	assert.Equal(t, rnd, res.Result)
+	assert.True(t, res.Fuel > 0)
+	assert.True(t, res.FuelStat.Queries["DBInsert"] > 0)
+	require.Len(t, res.Rows, 1)
+	assert.Equal(t, "1_notifications", res.Rows[0].Table)
+	assert.True(t, res.Rows[0].Insert)
+}
@@ -0,0 +1,262 @@
+// Apla Software includes an integrated development
+// environment with a multi-level system for the management
+// of access rights to data, interfaces, and Smart contracts. The
+// technical characteristics of the Apla Software are indicated in
+// Apla Technical Paper.
+
+// Apla Users are granted a permission to deal in the Apla
+// Software without restrictions, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of Apla Software, and to permit persons
+// to whom Apla Software is furnished to do so, subject to the
+// following conditions:
+// * the copyright notice of GenesisKernel and EGAAS S.A.
+// and this permission notice shall be included in all copies or
+// substantial portions of the software;
+// * a result of the dealing in Apla Software cannot be
+// implemented outside of the Apla Platform environment.
+
+// THE APLA SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY
+// OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED
+// TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE, ERROR FREE AND NONINFRINGEMENT. IN
+// NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR
+// THE USE OR OTHER DEALINGS IN THE APLA SOFTWARE.
+
+package api
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/AplaProject/go-apla/packages/block"
+	"github.com/AplaProject/go-apla/packages/consts"
+	"github.com/AplaProject/go-apla/packages/converter"
+	"github.com/AplaProject/go-apla/packages/model"
+	"github.com/AplaProject/go-apla/packages/publisher"
+	"github.com/AplaProject/go-apla/packages/stream"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	streamPingInterval = 15 * time.Second
+	streamReplayLimit  = 100
+	lastEventIDHeader  = "Last-Event-ID"
+)
+
+var errStreaming = errType{"E_STREAMING", "Streaming is not supported", http.StatusInternalServerError}
+
+type streamForm struct {
+	Blocks    bool   `schema:"blocks"`
+	Txs       string `schema:"tx"`
+	Tables    string `schema:"tables"`
+	FromBlock int64  `schema:"from_block"`
+	// Notifications enables notifications of the client from the in-process publisher broker
+	Notifications bool `schema:"notifications"`
+
+	txs    []string
+	tables []string
+}
+
+func (f *streamForm) Validate(r *http.Request) error {
+	client := getClient(r)
+
+	for _, hash := range splitList(f.Txs) {
+		if _, err := hex.DecodeString(hash); err != nil {
+			return errHashWrong
+		}
+		f.txs = append(f.txs, hash)
+	}
+	for _, table := range splitList(f.Tables) {
+		// table names without the ecosystem prefix belong to the client ecosystem
+		if converter.StrToInt64(strings.SplitN(table, "_", 2)[0]) == 0 {
+			table = client.Prefix() + "_" + table
+		}
+		f.tables = append(f.tables, table)
+	}
+	if !f.Blocks && !f.Notifications && len(f.txs) == 0 && len(f.tables) == 0 {
+		f.Blocks = true
+	}
+
+	if lastID := r.Header.Get(lastEventIDHeader); len(lastID) > 0 {
+		f.FromBlock = converter.StrToInt64(lastID) + 1
+	}
+	return nil
+}
+
+func splitList(s string) (list []string) {
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); len(item) > 0 {
+			list = append(list, item)
+		}
+	}
+	return
+}
+
+type streamWriter struct {
+	w       http.ResponseWriter
+	flusher http.Flusher
+	filter  stream.Filter
+}
+
+func (sw *streamWriter) write(e *stream.Event) error {
+	if !sw.filter.Match(e) {
+		return nil
+	}
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	lastID := e.BlockID
+	if e.Type == stream.TypeRollback {
+		// the client resumes from the rolled back block after reconnecting
+		lastID--
+	}
+	if lastID > 0 {
+		if _, err = fmt.Fprintf(sw.w, "id: %d\n", lastID); err != nil {
+			return err
+		}
+	}
+	if _, err = fmt.Fprintf(sw.w, "event: %s\ndata: %s\n\n", e.Type, data); err != nil {
+		return err
+	}
+	sw.flusher.Flush()
+	return nil
+}
+
+func (sw *streamWriter) writeNotification(msg publisher.Message) error {
+	if _, err := fmt.Fprintf(sw.w, "event: notifications\ndata: %s\n\n", msg.Data); err != nil {
+		return err
+	}
+	sw.flusher.Flush()
+	return nil
+}
+
+func (sw *streamWriter) ping() error {
+	if _, err := fmt.Fprint(sw.w, ": ping\n\n"); err != nil {
+		return err
+	}
+	sw.flusher.Flush()
+	return nil
+}
+
+// replay sends events of the stored blocks starting from blockID and returns the last sent block
+func (sw *streamWriter) replay(blockID int64) (int64, error) {
+	lastID := blockID - 1
+	for {
+		blocks, err := (&model.Block{}).GetBlocksFrom(lastID, string(model.OrderASC), streamReplayLimit)
+		if err != nil {
+			return lastID, err
+		}
+		if len(blocks) == 0 {
+			return lastID, nil
+		}
+		for i := range blocks {
+			events, err := block.GetStoredBlockEvents(&blocks[i])
+			if err != nil {
+				return lastID, err
+			}
+			for _, e := range events {
+				if err = sw.write(e); err != nil {
+					return lastID, err
+				}
+			}
+			lastID = blocks[i].ID
+		}
+	}
+}
+
+func streamHandler(w http.ResponseWriter, r *http.Request) {
+	form := &streamForm{}
+	if err := parseForm(r, form); err != nil {
+		errorResponse(w, err, http.StatusBadRequest)
+		return
+	}
+
+	logger := getLogger(r)
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		logger.WithFields(log.Fields{"type": consts.NetworkError}).Error("response writer doesn't support flushing")
+		errorResponse(w, errStreaming)
+		return
+	}
+
+	sw := &streamWriter{
+		w:       w,
+		flusher: flusher,
+		filter:  stream.NewFilter(form.Blocks, form.txs, form.tables),
+	}
+
+	// subscribe before replaying so that blocks committed meanwhile aren't lost
+	sub := stream.Subscribe(sw.filter, stream.DefaultBufferSize)
+	defer stream.Unsubscribe(sub)
+
+	var notifications <-chan publisher.Message
+	if form.Notifications {
+		broker := publisher.GetBroker()
+		ns := broker.Subscribe(getClient(r).KeyID)
+		defer broker.Unsubscribe(ns)
+		notifications = ns.Messages
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	var lastID int64
+	if form.FromBlock > 0 {
+		var err error
+		if lastID, err = sw.replay(form.FromBlock); err != nil {
+			logger.WithFields(log.Fields{"error": err, "block_id": lastID}).Warn("replaying blocks")
+			return
+		}
+	}
+
+	ticker := time.NewTicker(streamPingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+			if err := sw.ping(); err != nil {
+				return
+			}
+		case e, ok := <-sub.Events:
+			if !ok {
+				logger.WithFields(log.Fields{"type": consts.NetworkError}).Warn("stream subscriber is too slow")
+				return
+			}
+			if e.Type == stream.TypeRollback {
+				// the blocks with the same ids will be committed again
+				if e.BlockID <= lastID {
+					lastID = e.BlockID - 1
+				}
+			} else if e.BlockID > 0 && e.BlockID <= lastID {
+				continue
+			}
+			if err := sw.write(e); err != nil {
+				return
+			}
+		case msg, ok := <-notifications:
+			if !ok {
+				return
+			}
+			if err := sw.writeNotification(msg); err != nil {
+				return
+			}
+		}
+	}
+}
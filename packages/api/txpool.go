@@ -0,0 +1,95 @@
+// Apla Software includes an integrated development
+// environment with a multi-level system for the management
+// of access rights to data, interfaces, and Smart contracts. The
+// technical characteristics of the Apla Software are indicated in
+// Apla Technical Paper.
+
+// Apla Users are granted a permission to deal in the Apla
+// Software without restrictions, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of Apla Software, and to permit persons
+// to whom Apla Software is furnished to do so, subject to the
+// following conditions:
+// * the copyright notice of GenesisKernel and EGAAS S.A.
+// and this permission notice shall be included in all copies or
+// substantial portions of the software;
+// * a result of the dealing in Apla Software cannot be
+// implemented outside of the Apla Platform environment.
+
+// THE APLA SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY
+// OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED
+// TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE, ERROR FREE AND NONINFRINGEMENT. IN
+// NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR
+// THE USE OR OTHER DEALINGS IN THE APLA SOFTWARE.
+
+package api
+
+import (
+	"encoding/hex"
+	"net/http"
+
+	"github.com/AplaProject/go-apla/packages/consts"
+	"github.com/AplaProject/go-apla/packages/converter"
+	"github.com/AplaProject/go-apla/packages/model"
+
+	log "github.com/sirupsen/logrus"
+)
+
+type txPoolItem struct {
+	Hash       string `json:"hash"`
+	KeyID      string `json:"key_id"`
+	ContractID int64  `json:"contract_id"`
+	Fee        int64  `json:"fee"`
+	Time       int64  `json:"time"`
+	HighRate   int8   `json:"high_rate"`
+	Attempt    int8   `json:"attempt"`
+}
+
+type txPoolResult struct {
+	Count int64        `json:"count"`
+	List  []txPoolItem `json:"list"`
+}
+
+type txPoolForm struct {
+	paginatorForm
+	KeyID string `schema:"key_id"`
+}
+
+func getTxPoolHandler(w http.ResponseWriter, r *http.Request) {
+	form := &txPoolForm{}
+	if err := parseForm(r, form); err != nil {
+		errorResponse(w, err, http.StatusBadRequest)
+		return
+	}
+
+	logger := getLogger(r)
+
+	txs, count, err := model.GetPendingTransactions(converter.StringToAddress(form.KeyID), form.Limit, form.Offset)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting pending transactions")
+		errorResponse(w, err)
+		return
+	}
+
+	result := &txPoolResult{
+		Count: count,
+		List:  make([]txPoolItem, 0, len(txs)),
+	}
+	for _, tx := range txs {
+		result.List = append(result.List, txPoolItem{
+			Hash:       hex.EncodeToString(tx.Hash),
+			KeyID:      converter.Int64ToStr(tx.KeyID),
+			ContractID: tx.ContractID,
+			Fee:        tx.Fee,
+			Time:       tx.TxTime,
+			HighRate:   int8(tx.HighRate),
+			Attempt:    tx.Attempt,
+		})
+	}
+
+	jsonResponse(w, result)
+}
@@ -41,6 +41,7 @@ import (
 	"github.com/AplaProject/go-apla/packages/model"
 	"github.com/AplaProject/go-apla/packages/notificator"
 	"github.com/AplaProject/go-apla/packages/protocols"
+	"github.com/AplaProject/go-apla/packages/signer"
 	"github.com/AplaProject/go-apla/packages/smart"
 	"github.com/AplaProject/go-apla/packages/transaction"
 	"github.com/AplaProject/go-apla/packages/transaction/custom"
@@ -88,13 +89,7 @@ func (b *Block) PlaySafe() error {
 		for _, tr := range doneTx {
 			trData = append(trData, tr.TxFullData)
 		}
-		NodePrivateKey, _, err := utils.GetNodeKeys()
-		if err != nil || len(NodePrivateKey) < 1 {
-			log.WithFields(log.Fields{"type": consts.NodePrivateKeyFilename, "error": err}).Error("reading node private key")
-			return err
-		}
-
-		newBlockData, err := MarshallBlock(&b.Header, trData, b.PrevHeader, NodePrivateKey)
+		newBlockData, err := MarshallBlock(&b.Header, trData, b.PrevHeader, signer.Node())
 		if err != nil {
 			log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("marshalling new block")
 			return err
@@ -148,6 +143,7 @@ func (b *Block) PlaySafe() error {
 			notificator.UpdateNotifications(item.EcosystemID, item.List)
 		}
 	}
+	b.PublishEvents()
 	return nil
 }
 
@@ -167,6 +163,11 @@ func (b *Block) readPreviousBlockFromBlockchainTable() error {
 
 func (b *Block) Play(dbTransaction *model.DbTransaction) error {
 	logger := b.GetLogger()
+	// the changes of the fuel schedule are applied from the next block
+	if err := syspar.LoadFuelSchedule(dbTransaction, b.Header.BlockID); err != nil {
+		return err
+	}
+	syspar.LoadLoops(b.Header.BlockID)
 	if _, err := model.DeleteUsedTransactions(dbTransaction); err != nil {
 		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("delete used transactions")
 		return err
@@ -192,6 +193,7 @@ func (b *Block) Play(dbTransaction *model.DbTransaction) error {
 		)
 		t.DbTransaction = dbTransaction
 		t.Rand = randBlock
+		t.TxIndex = int64(curTx)
 
 		model.IncrementTxAttemptCount(nil, t.TxHash)
 		err = dbTransaction.Savepoint(curTx)
@@ -200,27 +202,15 @@ func (b *Block) Play(dbTransaction *model.DbTransaction) error {
 			return err
 		}
 		var flush []smart.FlushInfo
-		msg, flush, err = t.Play()
+		if err = t.CheckSlot(b.Header.BlockID); err == nil {
+			msg, flush, err = t.Play()
+		}
 		if err == nil && t.TxSmart != nil {
 			err = limits.CheckLimit(t)
 		}
 		if err != nil {
 			if flush != nil {
-				for i := len(flush) - 1; i >= 0; i-- {
-					finfo := flush[i]
-					if finfo.Prev == nil {
-						if finfo.ID != uint32(len(smart.GetVM().Children)-1) {
-							logger.WithFields(log.Fields{"type": consts.ContractError, "value": finfo.ID,
-								"len": len(smart.GetVM().Children) - 1}).Error("flush rollback")
-						} else {
-							smart.GetVM().Children = smart.GetVM().Children[:len(smart.GetVM().Children)-1]
-							delete(smart.GetVM().Objects, finfo.Name)
-						}
-					} else {
-						smart.GetVM().Children[finfo.ID] = finfo.Prev
-						smart.GetVM().Objects[finfo.Name] = finfo.Info
-					}
-				}
+				smart.RollbackFlush(flush, logger)
 			}
 			if err == custom.ErrNetworkStopping {
 				return err
@@ -0,0 +1,140 @@
+// Apla Software includes an integrated development
+// environment with a multi-level system for the management
+// of access rights to data, interfaces, and Smart contracts. The
+// technical characteristics of the Apla Software are indicated in
+// Apla Technical Paper.
+
+// Apla Users are granted a permission to deal in the Apla
+// Software without restrictions, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of Apla Software, and to permit persons
+// to whom Apla Software is furnished to do so, subject to the
+// following conditions:
+// * the copyright notice of GenesisKernel and EGAAS S.A.
+// and this permission notice shall be included in all copies or
+// substantial portions of the software;
+// * a result of the dealing in Apla Software cannot be
+// implemented outside of the Apla Platform environment.
+
+// THE APLA SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY
+// OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED
+// TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE, ERROR FREE AND NONINFRINGEMENT. IN
+// NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR
+// THE USE OR OTHER DEALINGS IN THE APLA SOFTWARE.
+
+package block
+
+import (
+	"bytes"
+
+	"github.com/AplaProject/go-apla/packages/consts"
+	"github.com/AplaProject/go-apla/packages/converter"
+	"github.com/AplaProject/go-apla/packages/model"
+	"github.com/AplaProject/go-apla/packages/stream"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// GetEvents returns the stream events of the committed block
+func (b *Block) GetEvents() ([]*stream.Event, error) {
+	blockID := b.Header.BlockID
+	events := make([]*stream.Event, 0, len(b.Transactions)+1)
+	events = append(events, &stream.Event{
+		Type:    stream.TypeBlock,
+		BlockID: blockID,
+		Block: &stream.BlockEvent{
+			ID:           blockID,
+			Hash:         b.Header.Hash,
+			Time:         b.Header.Time,
+			EcosystemID:  b.Header.EcosystemID,
+			KeyID:        b.Header.KeyID,
+			NodePosition: b.Header.NodePosition,
+			Tx:           len(b.Transactions),
+		},
+	})
+
+	for _, t := range b.Transactions {
+		txEvent := &stream.TxEvent{
+			Hash: string(converter.BinToHex(t.TxHash)),
+		}
+		ts := &model.TransactionStatus{}
+		found, err := ts.Get(t.TxHash)
+		if err != nil {
+			log.WithFields(log.Fields{"type": consts.DBError, "error": err, "tx_hash": t.TxHash}).Error("getting transaction status")
+			return nil, err
+		}
+		if found {
+			txEvent.BlockID = ts.BlockID
+			if ts.BlockID > 0 {
+				txEvent.Result = ts.Error
+			} else {
+				txEvent.Error = ts.Error
+			}
+		}
+		events = append(events, &stream.Event{Type: stream.TypeTx, BlockID: blockID, Tx: txEvent})
+	}
+
+	rollbackTxs, err := (&model.RollbackTx{}).GetBlockRollbackTransactions(nil, blockID)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err, "block_id": blockID}).Error("getting block rollback txs")
+		return nil, err
+	}
+	changed := make(map[string]bool)
+	for _, rtx := range rollbackTxs {
+		key := rtx.NameTable + "/" + rtx.TableID
+		if changed[key] {
+			continue
+		}
+		changed[key] = true
+		events = append(events, &stream.Event{
+			Type:    stream.TypeRow,
+			BlockID: blockID,
+			Row: &stream.RowEvent{
+				Table:  rtx.NameTable,
+				ID:     rtx.TableID,
+				TxHash: string(converter.BinToHex(rtx.TxHash)),
+			},
+		})
+	}
+	return events, nil
+}
+
+// GetStoredBlockEvents returns the stream events of the block from the blockchain table
+func GetStoredBlockEvents(mb *model.Block) ([]*stream.Event, error) {
+	b, err := UnmarshallBlock(bytes.NewBuffer(mb.Data), mb.ID == 1, false)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.UnmarshallingError, "error": err, "block_id": mb.ID}).Error("unmarshalling block")
+		return nil, err
+	}
+	b.Header.Hash = mb.Hash
+	return b.GetEvents()
+}
+
+// PublishEvents sends the events of the committed block to the subscribers
+func (b *Block) PublishEvents() {
+	if stream.Len() == 0 {
+		return
+	}
+	events, err := b.GetEvents()
+	if err != nil {
+		b.GetLogger().WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting block events")
+		return
+	}
+	stream.Publish(events...)
+}
+
+// PublishRollback notifies the subscribers that the block has been rolled back
+func (b *Block) PublishRollback() {
+	if stream.Len() == 0 {
+		return
+	}
+	stream.Publish(&stream.Event{
+		Type:     stream.TypeRollback,
+		BlockID:  b.Header.BlockID,
+		Rollback: &stream.RollbackEvent{ID: b.Header.BlockID},
+	})
+}
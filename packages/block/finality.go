@@ -0,0 +1,134 @@
+// Apla Software includes an integrated development
+// environment with a multi-level system for the management
+// of access rights to data, interfaces, and Smart contracts. The
+// technical characteristics of the Apla Software are indicated in
+// Apla Technical Paper.
+
+// Apla Users are granted a permission to deal in the Apla
+// Software without restrictions, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of Apla Software, and to permit persons
+// to whom Apla Software is furnished to do so, subject to the
+// following conditions:
+// * the copyright notice of GenesisKernel and EGAAS S.A.
+// and this permission notice shall be included in all copies or
+// substantial portions of the software;
+// * a result of the dealing in Apla Software cannot be
+// implemented outside of the Apla Platform environment.
+
+// THE APLA SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY
+// OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED
+// TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE, ERROR FREE AND NONINFRINGEMENT. IN
+// NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR
+// THE USE OR OTHER DEALINGS IN THE APLA SOFTWARE.
+
+package block
+
+import (
+	"errors"
+	"math"
+
+	"github.com/AplaProject/go-apla/packages/conf"
+	"github.com/AplaProject/go-apla/packages/conf/syspar"
+	"github.com/AplaProject/go-apla/packages/consts"
+	"github.com/AplaProject/go-apla/packages/crypto"
+	"github.com/AplaProject/go-apla/packages/model"
+	"github.com/AplaProject/go-apla/packages/network"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	// ErrFinalizedRollback is returned on the attempt to rollback the finalized block
+	ErrFinalizedRollback = errors.New("Finalized block can't be rolled back")
+	// ErrSnapshotRollback is returned on the attempt to rollback the blocks restored from the snapshot
+	ErrSnapshotRollback = errors.New("Blocks before snapshot can't be rolled back")
+)
+
+// FinalityRequired returns the number of full nodes which must sign the block to finalize it
+func FinalityRequired() int {
+	threshold := conf.Config.FinalityThreshold
+	if threshold <= 0 || threshold > 1 {
+		threshold = consts.DefaultFinalityThreshold
+	}
+	required := int(math.Ceil(float64(len(syspar.GetNodes())) * threshold))
+	if required < 1 {
+		required = 1
+	}
+	return required
+}
+
+// GetFinalizedBlockID returns the highest block which has been signed by enough full nodes.
+// Such block and all previous blocks can't be rolled back.
+func GetFinalizedBlockID() (int64, error) {
+	nodes := syspar.GetNodes()
+	keyIDs := make([]int64, len(nodes))
+	for i, node := range nodes {
+		keyIDs[i] = node.KeyID
+	}
+	blockID, err := model.GetFinalizedBlockID(keyIDs, FinalityRequired())
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting finalized block")
+	}
+	return blockID, err
+}
+
+// CheckFinalizedRollback returns the error if the rollback of blocks starting with blockID
+// reverts the finalized block
+func CheckFinalizedRollback(blockID int64) error {
+	if err := CheckSnapshotRollback(blockID); err != nil {
+		return err
+	}
+	finalized, err := GetFinalizedBlockID()
+	if err != nil {
+		return err
+	}
+	if blockID <= finalized {
+		log.WithFields(log.Fields{"type": consts.BlockError, "block_id": blockID, "finalized": finalized}).Error("rollback of finalized block")
+		return ErrFinalizedRollback
+	}
+	return nil
+}
+
+// CheckSnapshotRollback returns the error if the rollback of blocks starting with blockID
+// reverts the snapshot block. There is no rollback history for the state restored from the snapshot.
+func CheckSnapshotRollback(blockID int64) error {
+	snapshotID, err := model.GetSnapshotBlockID()
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting snapshot block")
+		return err
+	}
+	if blockID <= snapshotID {
+		log.WithFields(log.Fields{"type": consts.BlockError, "block_id": blockID, "snapshot": snapshotID}).Error("rollback of snapshot block")
+		return ErrSnapshotRollback
+	}
+	return nil
+}
+
+// SaveConfirmationSignature checks the signature of the full node and saves it
+// if the node has confirmed the hash of our block
+func SaveConfirmationSignature(blockID int64, hash []byte, resp *network.SignedConfirmResponse, time int64) error {
+	node := syspar.GetNode(resp.KeyID)
+	if node == nil {
+		return nil
+	}
+	ok, err := crypto.CheckSign(node.PublicKey, network.ConfirmationForSign(blockID, resp.Hash), resp.Sign)
+	if err != nil || !ok {
+		log.WithFields(log.Fields{"type": consts.CryptoError, "error": err, "key_id": resp.KeyID, "block_id": blockID}).Warning("wrong signature of confirmation")
+		return nil
+	}
+	if string(hash) != string(resp.Hash) {
+		return nil
+	}
+	cs := &model.ConfirmationSignature{BlockID: blockID, KeyID: resp.KeyID, Hash: hash,
+		Signature: resp.Sign, Time: time}
+	if err = cs.Save(); err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("saving confirmation signature")
+		return err
+	}
+	return nil
+}
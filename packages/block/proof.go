@@ -0,0 +1,170 @@
+// Apla Software includes an integrated development
+// environment with a multi-level system for the management
+// of access rights to data, interfaces, and Smart contracts. The
+// technical characteristics of the Apla Software are indicated in
+// Apla Technical Paper.
+
+// Apla Users are granted a permission to deal in the Apla
+// Software without restrictions, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of Apla Software, and to permit persons
+// to whom Apla Software is furnished to do so, subject to the
+// following conditions:
+// * the copyright notice of GenesisKernel and EGAAS S.A.
+// and this permission notice shall be included in all copies or
+// substantial portions of the software;
+// * a result of the dealing in Apla Software cannot be
+// implemented outside of the Apla Platform environment.
+
+// THE APLA SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY
+// OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED
+// TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE, ERROR FREE AND NONINFRINGEMENT. IN
+// NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR
+// THE USE OR OTHER DEALINGS IN THE APLA SOFTWARE.
+
+package block
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/AplaProject/go-apla/packages/consts"
+	"github.com/AplaProject/go-apla/packages/converter"
+	"github.com/AplaProject/go-apla/packages/crypto"
+	"github.com/AplaProject/go-apla/packages/model"
+	"github.com/AplaProject/go-apla/packages/transaction"
+	"github.com/AplaProject/go-apla/packages/utils"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	// ErrTxNotInBlock is returned if the block doesn't contain the transaction
+	ErrTxNotInBlock = errors.New("Transaction isn't in the block")
+	// ErrProofTxHash is returned if the transaction data don't match the hash
+	ErrProofTxHash = errors.New("Transaction data don't match the hash")
+	// ErrProofMerkle is returned if the transaction isn't in Merkle tree of the block
+	ErrProofMerkle = errors.New("Transaction isn't in Merkle tree of the block")
+	// ErrProofBlockHash is returned if the header doesn't match the block hash
+	ErrProofBlockHash = errors.New("Block header doesn't match the hash")
+)
+
+// ProofHeader contains the fields of the block header which are used for the block hash
+type ProofHeader struct {
+	BlockID           int64  `json:"block_id"`
+	Time              int64  `json:"time"`
+	EcosystemID       int64  `json:"ecosystem_id"`
+	KeyID             int64  `json:"key_id"`
+	NodePosition      int64  `json:"node_position"`
+	Version           int    `json:"version"`
+	PrevHash          []byte `json:"prev_hash"`
+	PrevRollbacksHash []byte `json:"prev_rollbacks_hash"`
+	MrklRoot          string `json:"mrkl_root"`
+	Hash              []byte `json:"hash"`
+}
+
+// TxProof is the proof of the inclusion of the transaction into the block
+type TxProof struct {
+	TxHash   []byte             `json:"tx_hash"`
+	Data     []byte             `json:"data"` // full data of the transaction
+	Position int                `json:"position"`
+	Count    int                `json:"count"`
+	Path     []utils.MerkleStep `json:"path"`
+	Header   ProofHeader        `json:"header"`
+}
+
+// GetTxProof returns the proof of the inclusion of the transaction into the block
+func GetTxProof(blockID int64, txHash []byte) (*TxProof, error) {
+	logger := log.WithFields(log.Fields{"block_id": blockID, "tx_hash": txHash})
+	b := &model.Block{}
+	found, err := b.Get(blockID)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting block")
+		return nil, err
+	}
+	if !found {
+		return nil, ErrTxNotInBlock
+	}
+	blck, err := UnmarshallBlock(bytes.NewBuffer(b.Data), blockID == 1, false)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.UnmarshallingError, "error": err}).Error("unmarshalling block")
+		return nil, err
+	}
+	prev := &utils.BlockData{}
+	if blockID > 1 {
+		if prev, err = GetBlockDataFromBlockChain(blockID - 1); err != nil {
+			return nil, err
+		}
+	}
+
+	proof := &TxProof{
+		TxHash:   txHash,
+		Position: -1,
+		Header: ProofHeader{
+			BlockID:           blck.Header.BlockID,
+			Time:              blck.Header.Time,
+			EcosystemID:       blck.Header.EcosystemID,
+			KeyID:             blck.Header.KeyID,
+			NodePosition:      blck.Header.NodePosition,
+			Version:           blck.Header.Version,
+			PrevHash:          prev.Hash,
+			PrevRollbacksHash: prev.RollbacksHash,
+			MrklRoot:          string(blck.MrklRoot),
+			Hash:              b.Hash,
+		},
+	}
+	mrklArray := make([][]byte, 0, len(blck.Transactions))
+	for _, tx := range blck.Transactions {
+		if len(tx.TxFullData) == 0 {
+			continue
+		}
+		if bytes.Equal(tx.TxHash, txHash) {
+			proof.Position = len(mrklArray)
+			proof.Data = tx.TxFullData
+		}
+		mrklArray = append(mrklArray, merkleLeaf(tx.TxFullData))
+	}
+	if proof.Position < 0 {
+		return nil, ErrTxNotInBlock
+	}
+	proof.Count = len(mrklArray)
+	proof.Path = utils.MerkleTreeProof(mrklArray, proof.Position)
+	return proof, nil
+}
+
+func merkleLeaf(data []byte) []byte {
+	hash, _ := crypto.DoubleHash(data)
+	return converter.BinToHex(hash)
+}
+
+// VerifyTxProof checks that the transaction of the proof is included into the block with the specified hash.
+// The block hash must be received from the trusted source.
+func VerifyTxProof(proof *TxProof, blockHash []byte) error {
+	rtx := &transaction.RawTransaction{}
+	if err := rtx.Unmarshall(bytes.NewBuffer(proof.Data)); err != nil {
+		return err
+	}
+	if !bytes.Equal(rtx.Hash(), proof.TxHash) {
+		return ErrProofTxHash
+	}
+	root := []byte(proof.Header.MrklRoot)
+	if !utils.CheckMerkleProof(merkleLeaf(proof.Data), proof.Path, root) {
+		return ErrProofMerkle
+	}
+	h := proof.Header
+	header := utils.BlockData{BlockID: h.BlockID, Time: h.Time, EcosystemID: h.EcosystemID,
+		KeyID: h.KeyID, NodePosition: h.NodePosition, Version: h.Version}
+	prev := &utils.BlockData{Hash: h.PrevHash, RollbacksHash: h.PrevRollbacksHash}
+	hash, err := crypto.DoubleHash([]byte(header.ForSha(prev, root)))
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(hash, blockHash) {
+		return ErrProofBlockHash
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+// Apla Software includes an integrated development
+// environment with a multi-level system for the management
+// of access rights to data, interfaces, and Smart contracts. The
+// technical characteristics of the Apla Software are indicated in
+// Apla Technical Paper.
+
+// Apla Users are granted a permission to deal in the Apla
+// Software without restrictions, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of Apla Software, and to permit persons
+// to whom Apla Software is furnished to do so, subject to the
+// following conditions:
+// * the copyright notice of GenesisKernel and EGAAS S.A.
+// and this permission notice shall be included in all copies or
+// substantial portions of the software;
+// * a result of the dealing in Apla Software cannot be
+// implemented outside of the Apla Platform environment.
+
+// THE APLA SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY
+// OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED
+// TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE, ERROR FREE AND NONINFRINGEMENT. IN
+// NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR
+// THE USE OR OTHER DEALINGS IN THE APLA SOFTWARE.
+
+package block
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/AplaProject/go-apla/packages/consts"
+	"github.com/AplaProject/go-apla/packages/crypto"
+	"github.com/AplaProject/go-apla/packages/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyTxProof(t *testing.T) {
+	txs := make([][]byte, 5)
+	mrklArray := make([][]byte, len(txs))
+	for i := range txs {
+		txs[i] = []byte(fmt.Sprintf("\x01transaction %d", i))
+		mrklArray[i] = merkleLeaf(txs[i])
+	}
+	header := utils.BlockData{BlockID: 10, Time: 1540000000, KeyID: -5, NodePosition: 1,
+		Version: consts.BV_ROLLBACK_HASH}
+	prev := &utils.BlockData{Hash: []byte("prev hash"), RollbacksHash: []byte("rollbacks")}
+	root := utils.MerkleTreeRoot(mrklArray)
+	blockHash, err := crypto.DoubleHash([]byte(header.ForSha(prev, root)))
+	require.NoError(t, err)
+
+	txHash, err := crypto.DoubleHash(txs[3])
+	require.NoError(t, err)
+	newProof := func() *TxProof {
+		return &TxProof{
+			TxHash:   txHash,
+			Data:     txs[3],
+			Position: 3,
+			Count:    len(txs),
+			Path:     utils.MerkleTreeProof(mrklArray, 3),
+			Header: ProofHeader{BlockID: header.BlockID, Time: header.Time, KeyID: header.KeyID,
+				NodePosition: header.NodePosition, Version: header.Version, PrevHash: prev.Hash,
+				PrevRollbacksHash: prev.RollbacksHash, MrklRoot: string(root), Hash: blockHash},
+		}
+	}
+	assert.NoError(t, VerifyTxProof(newProof(), blockHash))
+
+	proof := newProof()
+	proof.Data = txs[2]
+	assert.Equal(t, ErrProofTxHash, VerifyTxProof(proof, blockHash))
+
+	proof = newProof()
+	proof.Path = utils.MerkleTreeProof(mrklArray, 2)
+	assert.Equal(t, ErrProofMerkle, VerifyTxProof(proof, blockHash))
+
+	proof = newProof()
+	proof.Header.Time++
+	assert.Equal(t, ErrProofBlockHash, VerifyTxProof(proof, blockHash))
+}
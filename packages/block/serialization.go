@@ -35,13 +35,14 @@ import (
 	"github.com/AplaProject/go-apla/packages/consts"
 	"github.com/AplaProject/go-apla/packages/converter"
 	"github.com/AplaProject/go-apla/packages/crypto"
+	"github.com/AplaProject/go-apla/packages/signer"
 	"github.com/AplaProject/go-apla/packages/transaction"
 	"github.com/AplaProject/go-apla/packages/utils"
 	log "github.com/sirupsen/logrus"
 )
 
-// MarshallBlock is marshalling block
-func MarshallBlock(header *utils.BlockData, trData [][]byte, prev *utils.BlockData, key string) ([]byte, error) {
+// MarshallBlock returns binary data of the block, the block is signed by s if it isn't nil
+func MarshallBlock(header *utils.BlockData, trData [][]byte, prev *utils.BlockData, s signer.Signer) ([]byte, error) {
 	var mrklArray [][]byte
 	var blockDataTx []byte
 	var signed []byte
@@ -57,14 +58,14 @@ func MarshallBlock(header *utils.BlockData, trData [][]byte, prev *utils.BlockDa
 		blockDataTx = append(blockDataTx, converter.EncodeLengthPlusData(tr)...)
 	}
 
-	if key != "" {
+	if s != nil {
 		if len(mrklArray) == 0 {
 			mrklArray = append(mrklArray, []byte("0"))
 		}
 		mrklRoot := utils.MerkleTreeRoot(mrklArray)
 
 		var err error
-		signed, err = crypto.SignString(key, header.ForSign(prev, mrklRoot))
+		signed, err = s.Sign([]byte(header.ForSign(prev, mrklRoot)))
 		if err != nil {
 			logger.WithFields(log.Fields{"type": consts.CryptoError, "error": err}).Error("signing blocko")
 			return nil, err
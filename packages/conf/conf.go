@@ -77,6 +77,14 @@ type CentrifugoConfig struct {
 	URL    string
 }
 
+// PublisherConfig selects the backend of client notifications
+type PublisherConfig struct {
+	Type          string // centrifugo (by default), local or webhook
+	WebhookURL    string
+	WebhookSecret string // HMAC secret of the webhook body
+	Timeout       int    // webhook timeout in seconds
+}
+
 // Syslog represents parameters of syslog
 type Syslog struct {
 	Facility string
@@ -92,6 +100,23 @@ type LogConfig struct {
 }
 
 // TokenMovementConfig smtp config for token movement
+type SignerConfig struct {
+	Type    string // file (by default) or remote
+	Address string // address of the remote signer: unix:///path or tcp://host:port
+	Token   string // token of the remote signer
+	CAFile  string // TLS certificate of the tcp remote signer or its CA
+	Timeout int    // timeout of the remote signer in seconds
+}
+
+// TCPLimitsConfig contains limits for the peers of the tcp server, zero value disables the limit
+type TCPLimitsConfig struct {
+	MaxConnections int     // concurrent connections from one IP address or node
+	RequestRate    float64 // requests per second from one IP address or node
+	RequestBurst   int     // requests which can be done at once
+	MaxFails       int     // malformed requests before the ban
+	BanTime        int     // in seconds
+}
+
 type TokenMovementConfig struct {
 	Host     string
 	Port     int
@@ -113,22 +138,32 @@ type GlobalConfig struct {
 	LockFilePath          string
 	DataDir               string // application work dir (cwd by default)
 	KeysDir               string // place for private keys files: NodePrivateKey, PrivateKey
+	KeysPassphraseFile    string // file with the passphrase of the encrypted private keys
 	TempDir               string // temporary dir
+	ContractCacheDir      string // place for the cache of the compiled contracts
 	FirstBlockPath        string
 	TLS                   bool   // TLS is on/off. It is required for https
 	TLSCert               string // TLSCert is a filepath of the fullchain of certificate.
 	TLSKey                string // TLSKey is a filepath of the private key.
 	OBSMode               string
 	HTTPServerMaxBodySize int64
+	NetworkID             int64   // nodes with different network id refuse the handshake
+	SecureNodes           bool    // connect only to full nodes over the encrypted session authenticated by their keys
+	RejectUnknownNodes    bool    // refuse connections from the nodes which aren't in full_nodes
+	FinalityThreshold     float64 // fraction of full nodes which must sign the block to finalize it
+	TxPoolTTL             int64   // in seconds, unused transactions older than it are dropped from the pool
 
 	MaxPageGenerationTime int64 // in milliseconds
 
 	TCPServer HostPort
 	HTTP      HostPort
+	TCPLimits TCPLimitsConfig
 
 	DB            DBConfig
 	StatsD        StatsDConfig
 	Centrifugo    CentrifugoConfig
+	Publisher     PublisherConfig
+	Signer        SignerConfig
 	Log           LogConfig
 	TokenMovement TokenMovementConfig
 
@@ -236,6 +271,10 @@ func FillRuntimePaths() error {
 		Config.TempDir = filepath.Join(os.TempDir(), consts.DefaultTempDirName)
 	}
 
+	if Config.ContractCacheDir == "" {
+		Config.ContractCacheDir = filepath.Join(Config.DataDir, consts.DefaultContractCacheDirName)
+	}
+
 	if Config.FirstBlockPath == "" {
 		Config.FirstBlockPath = filepath.Join(Config.DataDir, consts.FirstBlockFilename)
 	}
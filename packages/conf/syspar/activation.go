@@ -0,0 +1,82 @@
+// Apla Software includes an integrated development
+// environment with a multi-level system for the management
+// of access rights to data, interfaces, and Smart contracts. The
+// technical characteristics of the Apla Software are indicated in
+// Apla Technical Paper.
+
+// Apla Users are granted a permission to deal in the Apla
+// Software without restrictions, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of Apla Software, and to permit persons
+// to whom Apla Software is furnished to do so, subject to the
+// following conditions:
+// * the copyright notice of GenesisKernel and EGAAS S.A.
+// and this permission notice shall be included in all copies or
+// substantial portions of the software;
+// * a result of the dealing in Apla Software cannot be
+// implemented outside of the Apla Platform environment.
+
+// THE APLA SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY
+// OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED
+// TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE, ERROR FREE AND NONINFRINGEMENT. IN
+// NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR
+// THE USE OR OTHER DEALINGS IN THE APLA SOFTWARE.
+
+package syspar
+
+import (
+	"github.com/AplaProject/go-apla/packages/converter"
+)
+
+// The system parameters below contain the id of the block since which the feature is enabled.
+// The empty or zero value disables the feature, so all nodes validate the blocks by the same rules.
+const (
+	// KeyTypesBlock enables Ed25519 and secp256k1 keys
+	KeyTypesBlock = `key_types_block`
+	// FuelScheduleBlock enables the costs of fuel_schedule parameter
+	FuelScheduleBlock = `fuel_schedule_block`
+	// TxSlotBlock enables one transaction per sender, time and contract
+	TxSlotBlock = `tx_slot_block`
+	// LoopsBlock enables for loops in the contracts, for and in become key words
+	LoopsBlock = `loops_block`
+)
+
+// loops is true if the contracts which are compiled now can contain for loops
+var loops bool
+
+// IsActivationParam returns true if the system parameter contains the activation block
+func IsActivationParam(name string) bool {
+	return name == KeyTypesBlock || name == FuelScheduleBlock || name == TxSlotBlock ||
+		name == LoopsBlock
+}
+
+// IsActive returns true if the feature of the activation parameter is enabled in the block
+func IsActive(name string, blockID int64) bool {
+	activation := converter.StrToInt64(SysString(name))
+	return activation > 0 && blockID >= activation
+}
+
+// LoadLoops enables or disables for loops in the contracts which are compiled in the block.
+// It must be called at the beginning of the block and before loading the contracts at the start
+// of the node, so the existing contracts with 'for' and 'in' identifiers are compiled as before.
+func LoadLoops(blockID int64) {
+	SetLoops(IsActive(LoopsBlock, blockID))
+}
+
+// SetLoops enables or disables for loops in the contracts which are compiled after it
+func SetLoops(enabled bool) {
+	mutex.Lock()
+	loops = enabled
+	mutex.Unlock()
+}
+
+// IsLoops returns true if 'for' and 'in' are the key words of the contract language
+func IsLoops() bool {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	return loops
+}
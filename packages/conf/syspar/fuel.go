@@ -0,0 +1,144 @@
+// Apla Software includes an integrated development
+// environment with a multi-level system for the management
+// of access rights to data, interfaces, and Smart contracts. The
+// technical characteristics of the Apla Software are indicated in
+// Apla Technical Paper.
+
+// Apla Users are granted a permission to deal in the Apla
+// Software without restrictions, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of Apla Software, and to permit persons
+// to whom Apla Software is furnished to do so, subject to the
+// following conditions:
+// * the copyright notice of GenesisKernel and EGAAS S.A.
+// and this permission notice shall be included in all copies or
+// substantial portions of the software;
+// * a result of the dealing in Apla Software cannot be
+// implemented outside of the Apla Platform environment.
+
+// THE APLA SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY
+// OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED
+// TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE, ERROR FREE AND NONINFRINGEMENT. IN
+// NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR
+// THE USE OR OTHER DEALINGS IN THE APLA SOFTWARE.
+
+package syspar
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/AplaProject/go-apla/packages/consts"
+	"github.com/AplaProject/go-apla/packages/converter"
+	"github.com/AplaProject/go-apla/packages/model"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// FuelSchedule is the system parameter which overrides the fuel costs of VM operations
+// and embedded functions, for example {"ops": {"call": 50}, "funcs": {"Sha256": 50}}
+const FuelSchedule = `fuel_schedule`
+
+var errFuelSchedule = errors.New(`Invalid value of the fuel_schedule parameter`)
+
+// Schedule contains the fuel costs which have been defined in fuel_schedule
+type Schedule struct {
+	Ops   map[string]int64 `json:"ops"`
+	Funcs map[string]int64 `json:"funcs"`
+}
+
+// fuelSchedule is used by VM, it is loaded at the beginning of the block
+var fuelSchedule *Schedule
+
+// ParseFuelSchedule parses the value of fuel_schedule parameter
+func ParseFuelSchedule(value string) (*Schedule, error) {
+	schedule := &Schedule{}
+	if len(value) > 0 {
+		if err := json.Unmarshal([]byte(value), schedule); err != nil {
+			log.WithFields(log.Fields{"type": consts.JSONUnmarshallError, "error": err}).Error("unmarshalling fuel schedule")
+			return nil, errFuelSchedule
+		}
+	}
+	for _, costs := range []map[string]int64{schedule.Ops, schedule.Funcs} {
+		for _, cost := range costs {
+			if cost < 0 {
+				return nil, errFuelSchedule
+			}
+		}
+	}
+	return schedule, nil
+}
+
+// LoadFuelSchedule loads the fuel schedule from the database. It must be called at the beginning
+// of the block before any transaction of the block is played, so all nodes use the committed value
+// of the parameter within the block. The default costs are used until fuel_schedule_block.
+func LoadFuelSchedule(transaction *model.DbTransaction, blockID int64) error {
+	schedule := &Schedule{}
+	value, err := getParamValue(transaction, FuelScheduleBlock)
+	if err != nil {
+		return err
+	}
+	if activation := converter.StrToInt64(value); activation > 0 && blockID >= activation {
+		if value, err = getParamValue(transaction, FuelSchedule); err != nil {
+			return err
+		}
+		if schedule, err = ParseFuelSchedule(value); err != nil {
+			schedule = &Schedule{}
+		}
+	}
+	mutex.Lock()
+	fuelSchedule = schedule
+	mutex.Unlock()
+	return nil
+}
+
+func getParamValue(transaction *model.DbTransaction, name string) (string, error) {
+	sp := &model.SystemParameter{}
+	if _, err := sp.GetTransaction(transaction, name); err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err, "name": name}).Error("getting system parameter")
+		return ``, err
+	}
+	return sp.Value, nil
+}
+
+// GetFuelSchedule returns the active fuel schedule
+func GetFuelSchedule() Schedule {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	ret := Schedule{Ops: make(map[string]int64), Funcs: make(map[string]int64)}
+	if fuelSchedule != nil {
+		for key, cost := range fuelSchedule.Ops {
+			ret.Ops[key] = cost
+		}
+		for key, cost := range fuelSchedule.Funcs {
+			ret.Funcs[key] = cost
+		}
+	}
+	return ret
+}
+
+// GetOpCost returns the cost of VM operation if it is defined in the active fuel schedule
+func GetOpCost(name string) (int64, bool) {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	if fuelSchedule == nil {
+		return 0, false
+	}
+	cost, ok := fuelSchedule.Ops[name]
+	return cost, ok
+}
+
+// GetFuncCost returns the cost of the embedded function if it is defined in the active fuel schedule
+func GetFuncCost(name string) (int64, bool) {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	if fuelSchedule == nil {
+		return 0, false
+	}
+	cost, ok := fuelSchedule.Funcs[name]
+	return cost, ok
+}
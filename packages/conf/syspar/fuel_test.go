@@ -0,0 +1,82 @@
+// Apla Software includes an integrated development
+// environment with a multi-level system for the management
+// of access rights to data, interfaces, and Smart contracts. The
+// technical characteristics of the Apla Software are indicated in
+// Apla Technical Paper.
+
+// Apla Users are granted a permission to deal in the Apla
+// Software without restrictions, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of Apla Software, and to permit persons
+// to whom Apla Software is furnished to do so, subject to the
+// following conditions:
+// * the copyright notice of GenesisKernel and EGAAS S.A.
+// and this permission notice shall be included in all copies or
+// substantial portions of the software;
+// * a result of the dealing in Apla Software cannot be
+// implemented outside of the Apla Platform environment.
+
+// THE APLA SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY
+// OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED
+// TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE, ERROR FREE AND NONINFRINGEMENT. IN
+// NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR
+// THE USE OR OTHER DEALINGS IN THE APLA SOFTWARE.
+
+package syspar
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFuelSchedule(t *testing.T) {
+	cases := []struct {
+		value string
+		err   bool
+	}{
+		{value: ``},
+		{value: `{}`},
+		{value: `{"ops": {"call": 10}, "funcs": {"Sha256": 0}}`},
+		{value: `{"ops": {"call": -1}}`, err: true},
+		{value: `{"funcs": {"Len": "5"}}`, err: true},
+		{value: `[]`, err: true},
+	}
+	for _, v := range cases {
+		_, err := ParseFuelSchedule(v.value)
+		if v.err {
+			assert.Error(t, err, v.value)
+		} else {
+			assert.NoError(t, err, v.value)
+		}
+	}
+
+	_, ok := GetOpCost(`call`)
+	assert.False(t, ok)
+
+	schedule, err := ParseFuelSchedule(`{"ops": {"call": 20}, "funcs": {"Len": 1}}`)
+	require.NoError(t, err)
+	mutex.Lock()
+	fuelSchedule = schedule
+	// the cached value of the parameter doesn't change the schedule of the current block
+	cache[FuelSchedule] = `{"ops": {"call": 10}}`
+	mutex.Unlock()
+
+	cost, ok := GetOpCost(`call`)
+	require.True(t, ok)
+	assert.Equal(t, int64(20), cost)
+	cost, ok = GetFuncCost(`Len`)
+	require.True(t, ok)
+	assert.Equal(t, int64(1), cost)
+	assert.Equal(t, map[string]int64{`Len`: 1}, GetFuelSchedule().Funcs)
+
+	mutex.Lock()
+	delete(cache, FuelSchedule)
+	fuelSchedule = nil
+	mutex.Unlock()
+}
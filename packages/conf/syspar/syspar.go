@@ -78,6 +78,8 @@ const (
 	MaxBlockUserTx = `max_tx_block_per_user`
 	// SizeFuel is the fuel cost of 1024 bytes of the transaction data
 	SizeFuel = `price_tx_data`
+	// EventFuel is the fuel cost of 1024 bytes of the event data
+	EventFuel = `price_exec_emit_event`
 	// CommissionWallet is the address for commissions
 	CommissionWallet = `commission_wallet`
 	// RbBlocks1 rollback from queue_bocks
@@ -344,6 +346,11 @@ func GetSizeFuel() int64 {
 	return SysInt64(SizeFuel)
 }
 
+// GetEventFuel returns the fuel cost of 1024 bytes of the event data
+func GetEventFuel() int64 {
+	return SysInt64(EventFuel)
+}
+
 // GetBlockchainURL is retrieving blockchain url
 func GetBlockchainURL() string {
 	return SysString(BlockchainURL)
@@ -33,7 +33,7 @@ import (
 )
 
 // VERSION is current version
-const VERSION = "1.2.4"
+const VERSION = "1.3.6"
 
 const BV_ROLLBACK_HASH = 2
 
@@ -135,12 +135,33 @@ const DefaultConfigFile = "config.toml"
 // DefaultWorkdirName name of working directory
 const DefaultWorkdirName = "apla-data"
 
+// DefaultContractCacheDirName is default name of the directory of the compiled contracts
+const DefaultContractCacheDirName = "contracts-cache"
+
 // DefaultPidFilename is default filename of pid file
 const DefaultPidFilename = "go-apla.pid"
 
 // DefaultLockFilename is default filename of lock file
 const DefaultLockFilename = "go-apla.lock"
 
+// DefaultPeersFilename is default filename of the table of peers
+const DefaultPeersFilename = "peers.json"
+
+// DefaultFinalityThreshold is default fraction of full nodes which must sign the block to finalize it
+const DefaultFinalityThreshold = 0.67
+
+// Default limits for the peers of the tcp server
+const (
+	DefaultTCPMaxConnections = 20
+	DefaultTCPRequestRate    = 20
+	DefaultTCPRequestBurst   = 100
+	DefaultTCPMaxFails       = 10
+	DefaultTCPBanTime        = 600
+)
+
+// DefaultTxPoolTTL is default lifetime of the transaction in the pool (in seconds)
+const DefaultTxPoolTTL = MAX_TX_BACK
+
 // FirstBlockFilename name of first block binary file
 const FirstBlockFilename = "1block"
 
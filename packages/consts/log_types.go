@@ -75,6 +75,7 @@ const (
 	IncorrectCallingContract = "IncorrectCallingContract"
 	WritingFile              = "WritingFile"
 	CentrifugoError          = "CentrifugoError"
+	PublisherError           = "PublisherError"
 	StatsdError              = "StatsdError"
 	MigrationError           = "MigrationError"
 	AutoupdateError          = "AutoupdateError"
@@ -0,0 +1,93 @@
+// Apla Software includes an integrated development
+// environment with a multi-level system for the management
+// of access rights to data, interfaces, and Smart contracts. The
+// technical characteristics of the Apla Software are indicated in
+// Apla Technical Paper.
+
+// Apla Users are granted a permission to deal in the Apla
+// Software without restrictions, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of Apla Software, and to permit persons
+// to whom Apla Software is furnished to do so, subject to the
+// following conditions:
+// * the copyright notice of GenesisKernel and EGAAS S.A.
+// and this permission notice shall be included in all copies or
+// substantial portions of the software;
+// * a result of the dealing in Apla Software cannot be
+// implemented outside of the Apla Platform environment.
+
+// THE APLA SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY
+// OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED
+// TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE, ERROR FREE AND NONINFRINGEMENT. IN
+// NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR
+// THE USE OR OTHER DEALINGS IN THE APLA SOFTWARE.
+
+package crypto
+
+import (
+	"crypto/ed25519"
+	crand "crypto/rand"
+	"fmt"
+
+	"github.com/AplaProject/go-apla/packages/consts"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// ed25519PubkeyLength is the length of Ed25519 public key, the private key is the 32 bytes seed
+const ed25519PubkeyLength = ed25519.PublicKeySize
+
+func genEd25519() ([]byte, []byte, error) {
+	pub, priv, err := ed25519.GenerateKey(crand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	return priv.Seed(), pub, nil
+}
+
+func ed25519Key(seed []byte) (ed25519.PrivateKey, error) {
+	if len(seed) != ed25519.SeedSize {
+		log.WithFields(log.Fields{"size": len(seed), "size_match": ed25519.SeedSize, "type": consts.SizeDoesNotMatch}).Error("invalid private key")
+		return nil, ErrIncorrectPrivKeyLength
+	}
+	return ed25519.NewKeyFromSeed(seed), nil
+}
+
+func ed25519PrivateToPublic(seed []byte) ([]byte, error) {
+	priv, err := ed25519Key(seed)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(priv.Public().(ed25519.PublicKey)), nil
+}
+
+func signEd25519(seed, data []byte) ([]byte, error) {
+	priv, err := ed25519Key(seed)
+	if err != nil {
+		return nil, err
+	}
+	return ed25519.Sign(priv, data), nil
+}
+
+func checkEd25519(public, data, signature []byte) (bool, error) {
+	if len(data) == 0 {
+		log.WithFields(log.Fields{"type": consts.CryptoError}).Error("data is empty")
+		return false, fmt.Errorf("invalid parameters len(data) == 0")
+	}
+	if len(public) != ed25519.PublicKeySize {
+		log.WithFields(log.Fields{"size": len(public), "size_match": ed25519.PublicKeySize, "type": consts.SizeDoesNotMatch}).Error("invalid public key")
+		return false, fmt.Errorf("invalid parameters len(public) = %d", len(public))
+	}
+	if len(signature) != ed25519.SignatureSize {
+		log.WithFields(log.Fields{"size": len(signature), "size_match": ed25519.SignatureSize, "type": consts.SizeDoesNotMatch}).Error("invalid signature")
+		return false, fmt.Errorf("invalid parameters len(signature) = %d", len(signature))
+	}
+	if !ed25519.Verify(ed25519.PublicKey(public), data, signature) {
+		return false, ErrIncorrectSign
+	}
+	return true, nil
+}
@@ -0,0 +1,148 @@
+// Apla Software includes an integrated development
+// environment with a multi-level system for the management
+// of access rights to data, interfaces, and Smart contracts. The
+// technical characteristics of the Apla Software are indicated in
+// Apla Technical Paper.
+
+// Apla Users are granted a permission to deal in the Apla
+// Software without restrictions, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of Apla Software, and to permit persons
+// to whom Apla Software is furnished to do so, subject to the
+// following conditions:
+// * the copyright notice of GenesisKernel and EGAAS S.A.
+// and this permission notice shall be included in all copies or
+// substantial portions of the software;
+// * a result of the dealing in Apla Software cannot be
+// implemented outside of the Apla Platform environment.
+
+// THE APLA SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY
+// OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED
+// TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE, ERROR FREE AND NONINFRINGEMENT. IN
+// NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR
+// THE USE OR OTHER DEALINGS IN THE APLA SOFTWARE.
+
+package crypto
+
+import (
+	"errors"
+
+	"github.com/AplaProject/go-apla/packages/consts"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// KeyType is the signature scheme of the key
+type KeyType int64
+
+const (
+	// KeyTypeP256 is ECDSA on the P-256 curve, it's the default scheme
+	KeyTypeP256 KeyType = iota
+	// KeyTypeEd25519 is Ed25519
+	KeyTypeEd25519
+	// KeyTypeSecp256k1 is ECDSA on the secp256k1 curve
+	KeyTypeSecp256k1
+)
+
+// ErrUnknownKeyType is Unknown key type error
+var ErrUnknownKeyType = errors.New("Unknown key type")
+
+// KeyTypeOf guesses the type of the new public key by its format. It's used only when the key is
+// added and the type isn't specified, the signatures are always checked by the type which is stored with the key.
+// Ed25519 keys have 32 bytes, secp256k1 keys are detected in the compressed form only,
+// the uncompressed secp256k1 keys have the same format as P-256 keys and require the explicit type.
+func KeyTypeOf(public []byte) KeyType {
+	switch {
+	case len(public) == ed25519PubkeyLength:
+		return KeyTypeEd25519
+	case len(public) == secp256k1CompressedLength && (public[0] == 2 || public[0] == 3):
+		return KeyTypeSecp256k1
+	default:
+		return KeyTypeP256
+	}
+}
+
+// IsValid returns true if the key type is supported
+func (kt KeyType) IsValid() bool {
+	return kt >= KeyTypeP256 && kt <= KeyTypeSecp256k1
+}
+
+func (kt KeyType) String() string {
+	switch kt {
+	case KeyTypeP256:
+		return "p256"
+	case KeyTypeEd25519:
+		return "ed25519"
+	case KeyTypeSecp256k1:
+		return "secp256k1"
+	default:
+		return "unknown"
+	}
+}
+
+// GenKeys generates a random pair of private and public binary keys of the type
+func (kt KeyType) GenKeys() ([]byte, []byte, error) {
+	switch kt {
+	case KeyTypeP256:
+		return GenBytesKeys()
+	case KeyTypeEd25519:
+		return genEd25519()
+	case KeyTypeSecp256k1:
+		return genSecp256k1()
+	default:
+		return nil, nil, ErrUnknownKeyType
+	}
+}
+
+// PrivateToPublic returns the public key for the private key of the type
+func (kt KeyType) PrivateToPublic(key []byte) ([]byte, error) {
+	switch kt {
+	case KeyTypeP256:
+		return PrivateToPublic(key)
+	case KeyTypeEd25519:
+		return ed25519PrivateToPublic(key)
+	case KeyTypeSecp256k1:
+		return secp256k1PrivateToPublic(key)
+	default:
+		return nil, ErrUnknownKeyType
+	}
+}
+
+// Sign signs the data with the private key of the type
+func (kt KeyType) Sign(privateKey, data []byte) ([]byte, error) {
+	if len(data) == 0 {
+		log.WithFields(log.Fields{"type": consts.CryptoError}).Debug(ErrSigningEmpty.Error())
+	}
+	switch kt {
+	case KeyTypeP256:
+		return signECDSA(privateKey, data)
+	case KeyTypeEd25519:
+		return signEd25519(privateKey, data)
+	case KeyTypeSecp256k1:
+		return signSecp256k1(privateKey, data)
+	default:
+		return nil, ErrUnknownKeyType
+	}
+}
+
+// CheckSign checks the signature of the data with the public key of the type
+func (kt KeyType) CheckSign(public, data, signature []byte) (bool, error) {
+	if len(public) == 0 {
+		log.WithFields(log.Fields{"type": consts.CryptoError}).Debug(ErrCheckingSignEmpty.Error())
+	}
+	switch kt {
+	case KeyTypeP256:
+		return checkECDSA(public, data, signature)
+	case KeyTypeEd25519:
+		return checkEd25519(public, data, signature)
+	case KeyTypeSecp256k1:
+		return checkSecp256k1(public, data, signature)
+	default:
+		log.WithFields(log.Fields{"type": consts.CryptoError, "key_type": int64(kt)}).Error(ErrUnknownKeyType.Error())
+		return false, ErrUnknownKeyType
+	}
+}
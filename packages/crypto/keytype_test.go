@@ -0,0 +1,111 @@
+// Apla Software includes an integrated development
+// environment with a multi-level system for the management
+// of access rights to data, interfaces, and Smart contracts. The
+// technical characteristics of the Apla Software are indicated in
+// Apla Technical Paper.
+
+// Apla Users are granted a permission to deal in the Apla
+// Software without restrictions, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of Apla Software, and to permit persons
+// to whom Apla Software is furnished to do so, subject to the
+// following conditions:
+// * the copyright notice of GenesisKernel and EGAAS S.A.
+// and this permission notice shall be included in all copies or
+// substantial portions of the software;
+// * a result of the dealing in Apla Software cannot be
+// implemented outside of the Apla Platform environment.
+
+// THE APLA SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY
+// OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED
+// TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE, ERROR FREE AND NONINFRINGEMENT. IN
+// NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR
+// THE USE OR OTHER DEALINGS IN THE APLA SOFTWARE.
+
+package crypto
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/AplaProject/go-apla/packages/converter"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyTypes(t *testing.T) {
+	data := []byte("apla transaction")
+	for _, kt := range []KeyType{KeyTypeP256, KeyTypeEd25519, KeyTypeSecp256k1} {
+		priv, pub, err := kt.GenKeys()
+		require.NoError(t, err, kt.String())
+
+		public, err := kt.PrivateToPublic(priv)
+		require.NoError(t, err, kt.String())
+		assert.Equal(t, pub, public, kt.String())
+		assert.Equal(t, kt, KeyTypeOf(pub), kt.String())
+
+		sign, err := kt.Sign(priv, data)
+		require.NoError(t, err, kt.String())
+		ok, err := kt.CheckSign(pub, data, sign)
+		require.NoError(t, err, kt.String())
+		assert.True(t, ok, kt.String())
+
+		// CheckSign checks only P-256 keys
+		ok, _ = CheckSign(pub, data, sign)
+		assert.Equal(t, kt == KeyTypeP256, ok, kt.String())
+
+		ok, _ = kt.CheckSign(pub, []byte("another transaction"), sign)
+		assert.False(t, ok, kt.String())
+	}
+
+	_, err := KeyType(100).Sign(nil, data)
+	assert.Equal(t, ErrUnknownKeyType, err)
+}
+
+func TestSecp256k1(t *testing.T) {
+	// the key and the signature are generated by openssl
+	priv, _ := hex.DecodeString("a7c3037b01b264b5ced424ca4c46ed7cbd14d21692c06a72b46db0cc3bd34353")
+	compressed, _ := hex.DecodeString("03b458dc756cb9c18a286c249286012ec8ab8231fe8021e27cc3ed7b2f2a4f6906")
+	uncompressed, _ := hex.DecodeString("04b458dc756cb9c18a286c249286012ec8ab8231fe8021e27cc3ed7b2f2a4f690662947301f883ea3c8c2e5d75ac2d23b632d5e7a76a5d20bf0cad56802c63e5e1")
+	der, _ := hex.DecodeString("30440220438ecee9967bd26b7e2f55624c650834d58524523a5b27815a7cb6f24b527b3802200a142755f90cdf4c696dda2f7877e788c33a3630fd64b3d255e1bb647dd6b976")
+	data := []byte("apla transaction")
+
+	pub, err := KeyTypeSecp256k1.PrivateToPublic(priv)
+	require.NoError(t, err)
+	assert.Equal(t, compressed, pub)
+
+	for _, public := range [][]byte{compressed, uncompressed, uncompressed[1:]} {
+		ok, err := KeyTypeSecp256k1.CheckSign(public, data, der)
+		require.NoError(t, err)
+		assert.True(t, ok)
+	}
+
+	// the uncompressed secp256k1 key is P-256 key without the explicit type
+	ok, _ := CheckSign(uncompressed[1:], data, der)
+	assert.False(t, ok)
+
+	sign, err := KeyTypeSecp256k1.Sign(priv, data)
+	require.NoError(t, err)
+	ok, err = KeyTypeSecp256k1.CheckSign(uncompressed, data, sign)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	// the signature is deterministic
+	again, err := KeyTypeSecp256k1.Sign(priv, data)
+	require.NoError(t, err)
+	assert.Equal(t, sign, again)
+
+	// the same signature with s = n - s isn't accepted
+	n, _ := new(big.Int).SetString("fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141", 16)
+	s := new(big.Int).Sub(n, new(big.Int).SetBytes(sign[32:]))
+	highS := append(append([]byte{}, sign[:32]...), converter.FillLeft(s.Bytes())...)
+	ok, err = KeyTypeSecp256k1.CheckSign(compressed, data, highS)
+	assert.Equal(t, ErrIncorrectSign, err)
+	assert.False(t, ok)
+}
@@ -0,0 +1,145 @@
+// Apla Software includes an integrated development
+// environment with a multi-level system for the management
+// of access rights to data, interfaces, and Smart contracts. The
+// technical characteristics of the Apla Software are indicated in
+// Apla Technical Paper.
+
+// Apla Users are granted a permission to deal in the Apla
+// Software without restrictions, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of Apla Software, and to permit persons
+// to whom Apla Software is furnished to do so, subject to the
+// following conditions:
+// * the copyright notice of GenesisKernel and EGAAS S.A.
+// and this permission notice shall be included in all copies or
+// substantial portions of the software;
+// * a result of the dealing in Apla Software cannot be
+// implemented outside of the Apla Platform environment.
+
+// THE APLA SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY
+// OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED
+// TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE, ERROR FREE AND NONINFRINGEMENT. IN
+// NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR
+// THE USE OR OTHER DEALINGS IN THE APLA SOFTWARE.
+
+package crypto
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/AplaProject/go-apla/packages/consts"
+	"github.com/AplaProject/go-apla/packages/converter"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+	log "github.com/sirupsen/logrus"
+)
+
+// secp256k1CompressedLength is the length of the compressed public key, it's used for the new keys
+const secp256k1CompressedLength = 33
+
+// secp256k1ParsePublic accepts the compressed key, the uncompressed key and the uncompressed key without 04 prefix
+func secp256k1ParsePublic(public []byte) (*secp256k1.PublicKey, error) {
+	switch {
+	case len(public) == secp256k1CompressedLength && (public[0] == 2 || public[0] == 3):
+	case len(public) == consts.PubkeySizeLength+1 && public[0] == 4:
+	case len(public) == consts.PubkeySizeLength:
+		public = append([]byte{4}, public...)
+	default:
+		return nil, ErrIncorrectPubKeyLength
+	}
+	key, err := secp256k1.ParsePubKey(public)
+	if err != nil {
+		return nil, fmt.Errorf("Not IsOnCurve")
+	}
+	return key, nil
+}
+
+func secp256k1PrivateKey(key []byte) (*secp256k1.PrivateKey, error) {
+	var d secp256k1.ModNScalar
+	if len(key) != consts.PrivkeyLength || d.SetByteSlice(key) || d.IsZero() {
+		log.WithFields(log.Fields{"size": len(key), "size_match": consts.PrivkeyLength, "type": consts.CryptoError}).Error("invalid private key")
+		return nil, ErrIncorrectPrivKeyLength
+	}
+	return secp256k1.NewPrivateKey(&d), nil
+}
+
+func genSecp256k1() ([]byte, []byte, error) {
+	key, err := secp256k1.GeneratePrivateKey()
+	if err != nil {
+		return nil, nil, err
+	}
+	return key.Serialize(), key.PubKey().SerializeCompressed(), nil
+}
+
+func secp256k1PrivateToPublic(key []byte) ([]byte, error) {
+	d, err := secp256k1PrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	return d.PubKey().SerializeCompressed(), nil
+}
+
+// signSecp256k1 returns r and s of the deterministic signature (RFC 6979) of SHA256 hash,
+// s is always in the lower half of the order
+func signSecp256k1(key, data []byte) ([]byte, error) {
+	d, err := secp256k1PrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	hash, err := Hash(data)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.CryptoError}).Error(ErrHashing.Error())
+		return nil, err
+	}
+	sign := ecdsa.Sign(d, hash)
+	r, s := sign.R(), sign.S()
+	rb, sb := r.Bytes(), s.Bytes()
+	return append(rb[:], sb[:]...), nil
+}
+
+// checkSecp256k1 checks the signature of SHA256 hash, the signature can be either r and s or DER.
+// The signatures with s in the upper half of the order are rejected, so the signature can't be changed
+// by the third party.
+func checkSecp256k1(public, data, signature []byte) (bool, error) {
+	if len(data) == 0 {
+		log.WithFields(log.Fields{"type": consts.CryptoError}).Error("data is empty")
+		return false, fmt.Errorf("invalid parameters len(data) == 0")
+	}
+	if len(signature) == 0 {
+		log.WithFields(log.Fields{"type": consts.CryptoError}).Error("invalid signature")
+		return false, fmt.Errorf("invalid parameters len(signature) == 0")
+	}
+	pub, err := secp256k1ParsePublic(public)
+	if err != nil {
+		log.WithFields(log.Fields{"size": len(public), "type": consts.CryptoError, "error": err}).Error("invalid public key")
+		return false, err
+	}
+	rInt, sInt, err := parseSign(hex.EncodeToString(signature))
+	if err != nil {
+		return false, err
+	}
+	var r, s secp256k1.ModNScalar
+	if r.SetByteSlice(converter.FillLeft(rInt.Bytes())) || s.SetByteSlice(converter.FillLeft(sInt.Bytes())) ||
+		r.IsZero() || s.IsZero() {
+		return false, ErrIncorrectSign
+	}
+	if s.IsOverHalfOrder() {
+		log.WithFields(log.Fields{"type": consts.CryptoError}).Debug("signature with high s")
+		return false, ErrIncorrectSign
+	}
+	hash, err := Hash(data)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.CryptoError}).Error(ErrHashing.Error())
+		return false, err
+	}
+	if !ecdsa.NewSignature(&r, &s).Verify(hash, pub) {
+		return false, ErrIncorrectSign
+	}
+	return true, nil
+}
@@ -72,7 +72,7 @@ func SignString(privateKeyHex, data string) ([]byte, error) {
 	return Sign(privateKey, []byte(data))
 }
 
-// CheckSign is checking sign
+// CheckSign is checking sign. The keys of other types are checked by KeyType.CheckSign
 func CheckSign(public, data, signature []byte) (bool, error) {
 	if len(public) == 0 {
 		log.WithFields(log.Fields{"type": consts.CryptoError}).Debug(ErrCheckingSignEmpty.Error())
@@ -108,6 +108,7 @@ func signECDSA(privateKey, data []byte) (ret []byte, err error) {
 	priv := new(ecdsa.PrivateKey)
 	priv.PublicKey.Curve = pubkeyCurve
 	priv.D = bi
+	priv.PublicKey.X, priv.PublicKey.Y = pubkeyCurve.ScalarBaseMult(privateKey)
 
 	signhash, err := Hash(data)
 	if err != nil {
@@ -37,10 +37,12 @@ import (
 	"github.com/AplaProject/go-apla/packages/conf"
 	"github.com/AplaProject/go-apla/packages/conf/syspar"
 	"github.com/AplaProject/go-apla/packages/consts"
+	"github.com/AplaProject/go-apla/packages/crypto"
 	"github.com/AplaProject/go-apla/packages/model"
 	"github.com/AplaProject/go-apla/packages/notificator"
 	"github.com/AplaProject/go-apla/packages/protocols"
 	"github.com/AplaProject/go-apla/packages/service"
+	"github.com/AplaProject/go-apla/packages/signer"
 	"github.com/AplaProject/go-apla/packages/transaction"
 	"github.com/AplaProject/go-apla/packages/utils"
 
@@ -105,18 +107,16 @@ func BlockGenerator(ctx context.Context, d *daemon) error {
 		return err
 	}
 
-	NodePrivateKey, NodePublicKey, err := utils.GetNodeKeys()
-	if err != nil || len(NodePrivateKey) < 1 {
-		if err == nil {
-			d.logger.WithFields(log.Fields{"type": consts.EmptyObject}).Error("node private key is empty")
-		}
+	nodeSigner := signer.Node()
+	nodePublicKey, err := nodeSigner.PublicKey()
+	if err != nil {
 		return err
 	}
 
 	dtx := DelayedTx{
-		privateKey: NodePrivateKey,
-		publicKey:  NodePublicKey,
-		logger:     d.logger,
+		signer:    nodeSigner,
+		publicKey: crypto.PubToHex(nodePublicKey),
+		logger:    d.logger,
 	}
 
 	dtx.RunForBlockID(prevBlock.BlockID + 1)
@@ -139,7 +139,7 @@ func BlockGenerator(ctx context.Context, d *daemon) error {
 		NodePosition: nodePosition,
 		Version:      consts.BLOCK_VERSION,
 	}
-	blockBin, err := generateNextBlock(header, trs, NodePrivateKey, &utils.BlockData{
+	blockBin, err := generateNextBlock(header, trs, nodeSigner, &utils.BlockData{
 		BlockID:       prevBlock.BlockID,
 		Hash:          prevBlock.Hash,
 		RollbacksHash: prevBlock.RollbacksHash,
@@ -158,14 +158,14 @@ func BlockGenerator(ctx context.Context, d *daemon) error {
 	return nil
 }
 
-func generateNextBlock(blockHeader *utils.BlockData, trs []*model.Transaction, key string,
+func generateNextBlock(blockHeader *utils.BlockData, trs []*model.Transaction, s signer.Signer,
 	prevBlock *utils.BlockData) ([]byte, error) {
 	trData := make([][]byte, 0, len(trs))
 	for _, tr := range trs {
 		trData = append(trData, tr.Data)
 	}
 
-	return block.MarshallBlock(blockHeader, trData, prevBlock, key)
+	return block.MarshallBlock(blockHeader, trData, prevBlock, s)
 }
 
 func processTransactions(logger *log.Entry, done <-chan time.Time) ([]*model.Transaction, error) {
@@ -29,11 +29,11 @@
 package daemons
 
 import (
-	"encoding/hex"
 	"time"
 
 	"github.com/AplaProject/go-apla/packages/consts"
 	"github.com/AplaProject/go-apla/packages/model"
+	"github.com/AplaProject/go-apla/packages/signer"
 	"github.com/AplaProject/go-apla/packages/smart"
 	"github.com/AplaProject/go-apla/packages/utils/tx"
 
@@ -47,9 +47,9 @@ const (
 
 // DelayedTx represents struct which works with delayed contracts
 type DelayedTx struct {
-	logger     *log.Entry
-	privateKey string
-	publicKey  string
+	logger    *log.Entry
+	signer    signer.Signer
+	publicKey string
 }
 
 // RunForBlockID creates the transactions that need to be run for blockID
@@ -86,12 +86,7 @@ func (dtx *DelayedTx) createTx(delayedContactID, keyID int64) error {
 		},
 	}
 
-	privateKey, err := hex.DecodeString(dtx.privateKey)
-	if err != nil {
-		return err
-	}
-
-	txData, txHash, err := tx.NewInternalTransaction(smartTx, privateKey)
+	txData, txHash, err := tx.NewInternalTransaction(smartTx, dtx.signer)
 	if err != nil {
 		return err
 	}
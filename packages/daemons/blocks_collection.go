@@ -36,7 +36,7 @@ import (
 	"time"
 
 	"github.com/AplaProject/go-apla/packages/block"
-	"github.com/AplaProject/go-apla/packages/network"
+	"github.com/AplaProject/go-apla/packages/network/peers"
 	"github.com/AplaProject/go-apla/packages/network/tcpclient"
 
 	"github.com/AplaProject/go-apla/packages/conf"
@@ -123,8 +123,9 @@ func blocksCollection(ctx context.Context, d *daemon) (err error) {
 // UpdateChain load from host all blocks from our last block to maxBlockID
 func UpdateChain(ctx context.Context, d *daemon, host string, maxBlockID int64) error {
 	var (
-		err   error
-		count int
+		err    error
+		count  int
+		forked bool
 	)
 
 	// get current block id from our blockchain
@@ -139,7 +140,7 @@ func UpdateChain(ctx context.Context, d *daemon, host string, maxBlockID int64)
 		return ctx.Err()
 	}
 
-	playRawBlock := func(rb []byte) error {
+	playRawBlock := func(host string, rb []byte) error {
 
 		bl, err := block.ProcessBlockWherePrevFromBlockchainTable(rb, true)
 		defer func() {
@@ -161,6 +162,9 @@ func UpdateChain(ctx context.Context, d *daemon, host string, maxBlockID int64)
 		}
 
 		if !hashMatched {
+			// the block doesn't link to our blockchain or to the previous chunk which could be
+			// downloaded from another host, so the previous blocks are requested from the same host
+			forked = true
 			transaction.CleanCache()
 			//it should be fork, replace our previous blocks to ones from the host
 			err = GetBlocks(ctx, bl.Header.BlockID-1, host)
@@ -189,32 +193,32 @@ func UpdateChain(ctx context.Context, d *daemon, host string, maxBlockID int64)
 	st := time.Now()
 	d.logger.WithFields(log.Fields{"min_block": curBlock.BlockID, "max_block": maxBlockID, "count": maxBlockID - curBlock.BlockID}).Info("starting downloading blocks")
 
-	for blockID := curBlock.BlockID + 1; blockID <= maxBlockID; blockID += int64(network.BlocksPerRequest) {
-		ctxDone, cancel := context.WithCancel(ctx)
+	ctxDownload, cancel := context.WithCancel(ctx)
+	defer cancel()
 
-		if loopErr := func() error {
-			defer func() {
-				cancel()
-				d.logger.WithFields(log.Fields{"count": count, "time": time.Since(st).String()}).Info("blocks downloaded")
-			}()
+	peers := syncPeers(ctxDownload, d.logger, host, maxBlockID, curBlock.BlockID)
+	for chunk := range downloadBlocks(ctxDownload, d.logger, peers, curBlock.BlockID+1, maxBlockID) {
+		if chunk.err != nil {
+			d.logger.WithFields(log.Fields{"error": chunk.err, "type": consts.BlockError}).Error("getting block body")
+			return chunk.err
+		}
 
-			rawBlocksChan, err := tcpclient.GetBlocksBodies(ctxDone, host, blockID, false)
-			if err != nil {
-				d.logger.WithFields(log.Fields{"error": err, "type": consts.BlockError}).Error("getting block body")
+		for _, rawBlock := range chunk.blocks {
+			if err = playRawBlock(chunk.host, rawBlock); err != nil {
+				d.logger.WithFields(log.Fields{"error": err, "type": consts.BlockError}).Error("playing raw block")
+				chunk.cancel()
 				return err
 			}
+			count++
+		}
+		chunk.cancel()
 
-			for rawBlock := range rawBlocksChan {
-				if err = playRawBlock(rawBlock); err != nil {
-					d.logger.WithFields(log.Fields{"error": err, "type": consts.BlockError}).Error("playing raw block")
-					return err
-				}
-				count++
-			}
-
+		d.logger.WithFields(log.Fields{"count": count, "time": time.Since(st).String()}).Info("blocks downloaded")
+		if forked {
+			// the other hosts may have the blocks of the previous fork, so they are checked again
+			// on the next collection
+			d.logger.WithFields(log.Fields{"host": chunk.host}).Info("blockchain has been replaced, restarting downloading")
 			return nil
-		}(); loopErr != nil {
-			return loopErr
 		}
 	}
 	return nil
@@ -281,7 +285,8 @@ func banNode(host string, block *block.Block, err error) {
 
 	n, err := syspar.GetNodeByHost(host)
 	if err != nil {
-		log.WithFields(log.Fields{"error": err}).Error("getting node by host")
+		// the host isn't a full node so it's only lowered in the table of peers
+		peers.GetTable().Fail(host)
 		return
 	}
 
@@ -294,8 +299,7 @@ func banNode(host string, block *block.Block, err error) {
 // GetHostWithMaxID returns host with maxBlockID
 func getHostWithMaxID(ctx context.Context, logger *log.Entry) (host string, maxBlockID int64, err error) {
 
-	nbs := service.GetNodesBanService()
-	hosts, err := nbs.FilterBannedHosts(syspar.GetRemoteHosts())
+	hosts, err := remoteHosts()
 	if err != nil {
 		logger.WithFields(log.Fields{"error": err}).Error("on filtering banned hosts")
 	}
@@ -332,6 +336,11 @@ func GetBlocks(ctx context.Context, blockID int64, host string) error {
 		blockID = blocks[len(blocks)-1].Header.BlockID
 	}
 
+	// the finalized blocks are never replaced with the blocks from other hosts
+	if err = block.CheckFinalizedRollback(blockID); err != nil {
+		return err
+	}
+
 	// we have the slice of blocks for applying
 	// first of all we should rollback old blocks
 	block := &model.Block{}
@@ -487,5 +496,11 @@ func processBlocks(blocks []*block.Block) error {
 		}
 	}
 
-	return dbTransaction.Commit()
+	if err := dbTransaction.Commit(); err != nil {
+		return err
+	}
+	for i := len(blocks) - 1; i >= 0; i-- {
+		blocks[i].PublishEvents()
+	}
+	return nil
 }
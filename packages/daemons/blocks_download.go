@@ -0,0 +1,262 @@
+// Apla Software includes an integrated development
+// environment with a multi-level system for the management
+// of access rights to data, interfaces, and Smart contracts. The
+// technical characteristics of the Apla Software are indicated in
+// Apla Technical Paper.
+
+// Apla Users are granted a permission to deal in the Apla
+// Software without restrictions, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of Apla Software, and to permit persons
+// to whom Apla Software is furnished to do so, subject to the
+// following conditions:
+// * the copyright notice of GenesisKernel and EGAAS S.A.
+// and this permission notice shall be included in all copies or
+// substantial portions of the software;
+// * a result of the dealing in Apla Software cannot be
+// implemented outside of the Apla Platform environment.
+
+// THE APLA SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY
+// OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED
+// TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE, ERROR FREE AND NONINFRINGEMENT. IN
+// NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR
+// THE USE OR OTHER DEALINGS IN THE APLA SOFTWARE.
+
+package daemons
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/AplaProject/go-apla/packages/consts"
+	"github.com/AplaProject/go-apla/packages/network"
+	"github.com/AplaProject/go-apla/packages/network/tcpclient"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// syncPeersCount is the maximum number of hosts which blocks are downloaded from concurrently
+	syncPeersCount = 4
+	// syncHostFails is the number of failures after which the host isn't used during the sync
+	syncHostFails = 2
+	// syncBlockTimeout is the time limit of waiting for the next block from the host
+	syncBlockTimeout = (consts.READ_TIMEOUT + consts.TCPConnTimeout/time.Second) * time.Second
+	// syncBufferSize is the maximum size in bytes of the downloaded blocks waiting for the previous ones
+	syncBufferSize = 64 << 20
+)
+
+var (
+	errChunkIncomplete = errors.New("Host has sent less blocks than requested")
+	errChunkTimeout    = errors.New("Timeout of downloading blocks")
+)
+
+// syncPeer is the host which blocks are downloaded from
+type syncPeer struct {
+	host       string
+	maxBlockID int64
+	fails      int
+}
+
+// blocksChunk contains the blocks downloaded by one request
+type blocksChunk struct {
+	start  int64
+	count  int64
+	host   string
+	blocks [][]byte
+	size   int64
+	cancel context.CancelFunc // releases the buffer of blocks
+	err    error
+}
+
+// syncPeers returns the hosts which the missing blocks can be downloaded from.
+// The host with max block is always the first one.
+func syncPeers(ctx context.Context, logger *log.Entry, host string, maxBlockID, curBlockID int64) []*syncPeer {
+	peers := []*syncPeer{{host: host, maxBlockID: maxBlockID}}
+	if maxBlockID-curBlockID <= int64(network.BlocksPerRequest) {
+		return peers
+	}
+
+	hosts, err := remoteHosts()
+	if err != nil {
+		logger.WithFields(log.Fields{"error": err}).Error("on filtering banned hosts")
+	}
+	blocks, err := tcpclient.HostsMaxBlock(ctx, hosts)
+	if err != nil {
+		logger.WithFields(log.Fields{"error": err}).Warn("on getting max blocks of hosts")
+		return peers
+	}
+
+	others := make([]*syncPeer, 0, len(blocks))
+	for h, blockID := range blocks {
+		if h != host && blockID > curBlockID {
+			others = append(others, &syncPeer{host: h, maxBlockID: blockID})
+		}
+	}
+	sort.Slice(others, func(i, j int) bool {
+		return others[i].maxBlockID > others[j].maxBlockID
+	})
+	if len(others) >= syncPeersCount {
+		others = others[:syncPeersCount-1]
+	}
+	return append(peers, others...)
+}
+
+// fetchBlocksChunk downloads count blocks starting with start block from the host
+func fetchBlocksChunk(ctx context.Context, host string, start, count int64) *blocksChunk {
+	chunk := &blocksChunk{start: start, count: count, host: host}
+	ctxChunk, cancel := context.WithCancel(ctx)
+	chunk.cancel = cancel
+
+	timer := time.NewTimer(syncBlockTimeout)
+	defer timer.Stop()
+
+	blocksCh, err := tcpclient.GetBlocksBodies(ctxChunk, host, start, false)
+	if err == nil && blocksCh == nil {
+		err = errChunkIncomplete
+	}
+	for err == nil && int64(len(chunk.blocks)) < count {
+		select {
+		case rawBlock, ok := <-blocksCh:
+			if !ok {
+				err = errChunkIncomplete
+				break
+			}
+			chunk.blocks = append(chunk.blocks, rawBlock)
+			chunk.size += int64(len(rawBlock))
+			if !timer.Stop() {
+				select {
+				case <-timer.C:
+				default:
+				}
+			}
+			timer.Reset(syncBlockTimeout)
+		case <-timer.C:
+			err = errChunkTimeout
+		case <-ctx.Done():
+			err = ctx.Err()
+		}
+	}
+	if err != nil {
+		cancel()
+		chunk.blocks = nil
+		chunk.err = err
+	}
+	return chunk
+}
+
+// downloadBlocks downloads blocks from several hosts concurrently. The chunks of blocks are sent
+// to the returned channel in order of block ids. If the chunk can't be downloaded from any host
+// then the chunk with the error is sent and the channel is closed. The chunks following the next
+// expected one are requested while the downloaded blocks waiting for it take less than syncBufferSize.
+func downloadBlocks(ctx context.Context, logger *log.Entry, peers []*syncPeer, from, to int64) <-chan *blocksChunk {
+	out := make(chan *blocksChunk)
+
+	go func() {
+		defer close(out)
+
+		var (
+			perRequest = int64(network.BlocksPerRequest)
+			window     = 2 * int64(len(peers)) * perRequest // limits the number of chunks in the reorder buffer
+			pending    []int64
+			results    = make(map[int64]*blocksChunk)
+			buffered   int64 // the size of blocks in results
+			tried      = make(map[int64]map[string]bool)
+			busy       = make(map[string]bool)
+			done       = make(chan *blocksChunk, len(peers))
+			next       = from
+		)
+		defer func() {
+			for _, chunk := range results {
+				chunk.cancel()
+			}
+		}()
+
+		chunkCount := func(start int64) int64 {
+			if start+perRequest-1 > to {
+				return to - start + 1
+			}
+			return perRequest
+		}
+		for start := from; start <= to; start += perRequest {
+			pending = append(pending, start)
+			tried[start] = make(map[string]bool)
+		}
+
+		schedule := func() {
+			for _, peer := range peers {
+				if busy[peer.host] || peer.fails >= syncHostFails {
+					continue
+				}
+				for i, start := range pending {
+					count := chunkCount(start)
+					if start >= next+window || start+count-1 > peer.maxBlockID || tried[start][peer.host] {
+						continue
+					}
+					if start != next && buffered >= syncBufferSize {
+						continue
+					}
+					pending = append(pending[:i], pending[i+1:]...)
+					busy[peer.host] = true
+					go func(host string) {
+						done <- fetchBlocksChunk(ctx, host, start, count)
+					}(peer.host)
+					break
+				}
+			}
+		}
+
+		for next <= to {
+			if chunk, ok := results[next]; ok {
+				delete(results, next)
+				buffered -= chunk.size
+				select {
+				case out <- chunk:
+				case <-ctx.Done():
+					chunk.cancel()
+					return
+				}
+				next += chunk.count
+				continue
+			}
+
+			schedule()
+			if len(busy) == 0 {
+				logger.WithFields(log.Fields{"type": consts.NetworkError, "block_id": next}).Error("no hosts to download blocks")
+				out <- &blocksChunk{start: next, err: ErrNodesUnavailable}
+				return
+			}
+
+			var chunk *blocksChunk
+			select {
+			case chunk = <-done:
+			case <-ctx.Done():
+				return
+			}
+			delete(busy, chunk.host)
+			if chunk.err != nil {
+				logger.WithFields(log.Fields{"type": consts.NetworkError, "error": chunk.err, "host": chunk.host,
+					"block_id": chunk.start}).Warn("downloading blocks, trying another host")
+				for _, peer := range peers {
+					if peer.host == chunk.host {
+						peer.fails++
+					}
+				}
+				tried[chunk.start][chunk.host] = true
+				pending = append(pending, chunk.start)
+				sort.Slice(pending, func(i, j int) bool { return pending[i] < pending[j] })
+				continue
+			}
+			results[chunk.start] = chunk
+			buffered += chunk.size
+		}
+	}()
+
+	return out
+}
@@ -0,0 +1,262 @@
+// Apla Software includes an integrated development
+// environment with a multi-level system for the management
+// of access rights to data, interfaces, and Smart contracts. The
+// technical characteristics of the Apla Software are indicated in
+// Apla Technical Paper.
+
+// Apla Users are granted a permission to deal in the Apla
+// Software without restrictions, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of Apla Software, and to permit persons
+// to whom Apla Software is furnished to do so, subject to the
+// following conditions:
+// * the copyright notice of GenesisKernel and EGAAS S.A.
+// and this permission notice shall be included in all copies or
+// substantial portions of the software;
+// * a result of the dealing in Apla Software cannot be
+// implemented outside of the Apla Platform environment.
+
+// THE APLA SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY
+// OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED
+// TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE, ERROR FREE AND NONINFRINGEMENT. IN
+// NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR
+// THE USE OR OTHER DEALINGS IN THE APLA SOFTWARE.
+
+package daemons
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/AplaProject/go-apla/packages/block"
+	"github.com/AplaProject/go-apla/packages/conf/syspar"
+	"github.com/AplaProject/go-apla/packages/consts"
+	"github.com/AplaProject/go-apla/packages/crypto"
+	"github.com/AplaProject/go-apla/packages/model"
+	"github.com/AplaProject/go-apla/packages/smart"
+	"github.com/AplaProject/go-apla/packages/utils"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// ChecksumFileExt is the extension of the file with checksums of the block file
+	ChecksumFileExt = ".sum"
+
+	exportBatchSize = 1000
+)
+
+var (
+	// ErrBlockChecksum is returned if the checksum of the block from file is wrong
+	ErrBlockChecksum = errors.New("Wrong checksum of block")
+	// ErrBlockSequence is returned if blocks in the file aren't sequential
+	ErrBlockSequence = errors.New("Wrong sequence of blocks")
+)
+
+func blockChecksum(b *blockData) (uint64, error) {
+	return crypto.CalcChecksum(marshallFileBlock(*b))
+}
+
+func readChecksums(fileName string) (map[int64]uint64, error) {
+	file, err := os.Open(fileName + ChecksumFileExt)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	sums := make(map[int64]uint64)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var (
+			id  int64
+			sum uint64
+		)
+		if _, err = fmt.Sscanf(scanner.Text(), "%d %x", &id, &sum); err != nil {
+			return nil, err
+		}
+		sums[id] = sum
+	}
+	return sums, scanner.Err()
+}
+
+// loadBlockchainState loads system parameters and contracts which are required for playing blocks
+func loadBlockchainState(logger *log.Entry) error {
+	if err := syspar.SysUpdate(nil); err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("updating syspar")
+		return err
+	}
+	if data, ok := block.GetDataFromFirstBlock(); ok {
+		syspar.SetFirstBlockData(data)
+	}
+	if err := smart.LoadContracts(); err != nil {
+		logger.WithFields(log.Fields{"type": consts.ContractError, "error": err}).Error("loading contracts")
+		return err
+	}
+	return nil
+}
+
+// ExportBlocks writes blocks from fromID to toID into the block file and
+// their checksums into the checksum file. If the block file exists then export
+// is resumed from its last block. It returns the count of written blocks.
+func ExportBlocks(fileName string, fromID, toID int64, logger *log.Entry) (int64, error) {
+	if fromID < 1 {
+		fromID = 1
+	}
+	if toID <= 0 {
+		infoBlock := &model.InfoBlock{}
+		if _, err := infoBlock.Get(); err != nil {
+			logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting info block")
+			return 0, err
+		}
+		toID = infoBlock.BlockID
+	}
+
+	lastSavedID, err := getLastBlockID(fileName, logger)
+	if err != nil {
+		return 0, err
+	}
+	if lastSavedID > 0 {
+		if lastSavedID+1 < fromID {
+			logger.WithFields(log.Fields{"type": consts.InvalidObject, "last_block_id": lastSavedID, "from": fromID}).Error("block file can't be resumed")
+			return 0, ErrBlockSequence
+		}
+		fromID = lastSavedID + 1
+	}
+
+	file, err := os.OpenFile(fileName, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.IOError, "error": err}).Error("opening file, to write blocks")
+		return 0, err
+	}
+	defer file.Close()
+
+	sumFile, err := os.OpenFile(fileName+ChecksumFileExt, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.IOError, "error": err}).Error("opening checksum file")
+		return 0, err
+	}
+	defer sumFile.Close()
+
+	var count int64
+	for startID := fromID - 1; startID < toID; startID += exportBatchSize {
+		endID := startID + exportBatchSize
+		if endID > toID {
+			endID = toID
+		}
+		blocks, err := model.GetBlockchain(startID, endID, model.OrderASC)
+		if err != nil {
+			logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting blockchain")
+			return count, err
+		}
+
+		for _, b := range blocks {
+			bd := blockData{ID: b.ID, Data: b.Data}
+			sum, err := blockChecksum(&bd)
+			if err != nil {
+				logger.WithFields(log.Fields{"type": consts.CryptoError, "error": err}).Error("calculating block checksum")
+				return count, err
+			}
+			if _, err = file.Write(marshallFileBlock(bd)); err != nil {
+				logger.WithFields(log.Fields{"type": consts.IOError, "error": err}).Error("writing block to file")
+				return count, err
+			}
+			if _, err = fmt.Fprintf(sumFile, "%d %016x\n", b.ID, sum); err != nil {
+				logger.WithFields(log.Fields{"type": consts.IOError, "error": err}).Error("writing block checksum to file")
+				return count, err
+			}
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+// ImportBlocks plays blocks from the block file which are newer than the last block of the node.
+// Thus import is resumed after interruption. It returns the count of played blocks.
+func ImportBlocks(fileName string, logger *log.Entry) (int64, error) {
+	sums, err := readChecksums(fileName)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.IOError, "error": err}).Error("reading checksum file")
+		return 0, err
+	}
+	if sums == nil {
+		logger.WithFields(log.Fields{"file": fileName + ChecksumFileExt}).Warn("checksum file not found, blocks won't be verified")
+	}
+
+	infoBlock := &model.InfoBlock{}
+	if _, err := infoBlock.Get(); err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting info block")
+		return 0, err
+	}
+	curBlockID := infoBlock.BlockID
+	if curBlockID > 0 {
+		if err = loadBlockchainState(logger); err != nil {
+			return 0, err
+		}
+	}
+
+	file, err := os.Open(fileName)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.IOError, "error": err}).Error("opening block file")
+		return 0, err
+	}
+	defer file.Close()
+
+	r := bufio.NewReader(file)
+	var count int64
+	for {
+		bd, err := readBlock(r, logger)
+		if err == io.EOF || (err == nil && bd == nil) {
+			return count, nil
+		}
+		if err != nil {
+			return count, err
+		}
+		if bd.ID <= curBlockID {
+			continue
+		}
+		if bd.ID != curBlockID+1 {
+			logger.WithFields(log.Fields{"type": consts.InvalidObject, "block_id": bd.ID, "cur_block_id": curBlockID}).Error("block file skips blocks")
+			return count, ErrBlockSequence
+		}
+
+		if sums != nil {
+			sum, err := blockChecksum(bd)
+			if err != nil {
+				logger.WithFields(log.Fields{"type": consts.CryptoError, "error": err}).Error("calculating block checksum")
+				return count, err
+			}
+			if want, ok := sums[bd.ID]; !ok || want != sum {
+				logger.WithFields(log.Fields{"type": consts.InvalidObject, "block_id": bd.ID}).Error("wrong block checksum")
+				return count, ErrBlockChecksum
+			}
+		}
+
+		if err = block.InsertBlockWOForks(bd.Data, false, bd.ID == 1); err != nil {
+			logger.WithFields(log.Fields{"type": consts.BlockError, "error": err, "block_id": bd.ID}).Error("inserting block")
+			return count, utils.ErrInfo(err)
+		}
+		if bd.ID == 1 {
+			if err = model.UpdateSchema(); err != nil {
+				logger.WithFields(log.Fields{"type": consts.MigrationError, "error": err}).Error("updating schema")
+				return count, err
+			}
+			if err = loadBlockchainState(logger); err != nil {
+				return count, err
+			}
+		}
+
+		curBlockID = bd.ID
+		count++
+	}
+}
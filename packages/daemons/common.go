@@ -62,6 +62,7 @@ var daemonsList = map[string]func(context.Context, *daemon) error{
 	"QueueParserBlocks": QueueParserBlocks,
 	"Confirmations":     Confirmations,
 	"Scheduler":         Scheduler,
+	"PeerDiscovery":     PeerDiscovery,
 }
 
 var rollbackList = []string{
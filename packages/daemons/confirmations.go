@@ -32,12 +32,16 @@ import (
 	"context"
 	"time"
 
+	"github.com/AplaProject/go-apla/packages/block"
+	"github.com/AplaProject/go-apla/packages/conf"
 	"github.com/AplaProject/go-apla/packages/conf/syspar"
 	"github.com/AplaProject/go-apla/packages/consts"
 	"github.com/AplaProject/go-apla/packages/converter"
 	"github.com/AplaProject/go-apla/packages/model"
+	"github.com/AplaProject/go-apla/packages/network"
 	"github.com/AplaProject/go-apla/packages/network/tcpclient"
 	"github.com/AplaProject/go-apla/packages/service"
+	"github.com/AplaProject/go-apla/packages/signer"
 
 	log "github.com/sirupsen/logrus"
 )
@@ -98,14 +102,14 @@ func confirmationsBlocks(ctx context.Context, d *daemon, lastBlockID, startBlock
 			return err
 		}
 
-		block := model.Block{}
-		_, err := block.Get(blockID)
+		blk := model.Block{}
+		_, err := blk.Get(blockID)
 		if err != nil {
 			d.logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting block by ID")
 			return err
 		}
 
-		hashStr := string(converter.BinToHex(block.Hash))
+		hashStr := string(converter.BinToHex(blk.Hash))
 		d.logger.WithFields(log.Fields{"hash": hashStr}).Debug("checking hash")
 		if len(hashStr) == 0 {
 			d.logger.WithFields(log.Fields{"hash": hashStr, "type": consts.NotFound}).Debug("hash not found")
@@ -117,7 +121,7 @@ func confirmationsBlocks(ctx context.Context, d *daemon, lastBlockID, startBlock
 			return err
 		}
 
-		ch := make(chan string)
+		ch := make(chan *confirmationAnswer)
 		for i := 0; i < len(hosts); i++ {
 			host, err := tcpclient.NormalizeHostAddress(hosts[i], consts.DEFAULT_TCP_PORT)
 			if err != nil {
@@ -127,18 +131,24 @@ func confirmationsBlocks(ctx context.Context, d *daemon, lastBlockID, startBlock
 
 			d.logger.WithFields(log.Fields{"host": host, "block_id": blockID}).Debug("checking block id confirmed at node")
 			go func() {
-				IsReachable(host, blockID, ch, d.logger)
+				getConfirmation(host, blockID, ch, d.logger)
 			}()
 		}
-		var answer string
+		var answer *confirmationAnswer
 		var st0, st1 int64
 		for i := 0; i < len(hosts); i++ {
 			answer = <-ch
-			if answer == hashStr {
+			if answer.hash == hashStr {
 				st1++
 			} else {
 				st0++
 			}
+			if answer.signed != nil {
+				block.SaveConfirmationSignature(blockID, blk.Hash, answer.signed, time.Now().Unix())
+			}
+		}
+		if syspar.GetNode(conf.Config.KeyID) != nil {
+			saveOwnConfirmation(blockID, blk.Hash, d.logger)
 		}
 		confirmation := &model.Confirmation{}
 		confirmation.GetConfirmation(blockID)
@@ -172,3 +182,45 @@ func IsReachable(host string, blockID int64, ch0 chan string, logger *log.Entry)
 		ch0 <- "0"
 	}
 }
+
+// confirmationAnswer is the hash of the block received from the host.
+// signed is nil if the host doesn't sign confirmations.
+type confirmationAnswer struct {
+	hash   string
+	signed *network.SignedConfirmResponse
+}
+
+// getConfirmation gets the signed confirmation of the block from the host
+// or the unsigned one if the host doesn't support it
+func getConfirmation(host string, blockID int64, ch0 chan *confirmationAnswer, logger *log.Entry) {
+	ch := make(chan *confirmationAnswer, 1)
+	go func() {
+		resp, err := tcpclient.GetSignedConfirmation(host, blockID, logger)
+		if err == tcpclient.ErrNotSupported {
+			ch <- &confirmationAnswer{hash: tcpclient.CheckConfirmation(host, blockID, logger)}
+			return
+		}
+		if err != nil || len(resp.Hash) == 0 {
+			ch <- &confirmationAnswer{hash: "0"}
+			return
+		}
+		ch <- &confirmationAnswer{hash: string(converter.BinToHex(resp.Hash)), signed: resp}
+	}()
+	select {
+	case answer := <-ch:
+		ch0 <- answer
+	case <-time.After(consts.WAIT_CONFIRMED_NODES * time.Second):
+		ch0 <- &confirmationAnswer{hash: "0"}
+	}
+}
+
+// saveOwnConfirmation signs the block by the key of the node
+func saveOwnConfirmation(blockID int64, hash []byte, logger *log.Entry) {
+	sign, err := signer.Node().Sign(network.ConfirmationForSign(blockID, hash))
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.CryptoError, "error": err}).Error("signing confirmation")
+		return
+	}
+	block.SaveConfirmationSignature(blockID, hash, &network.SignedConfirmResponse{
+		Hash: hash, KeyID: conf.Config.KeyID, Sign: sign}, time.Now().Unix())
+}
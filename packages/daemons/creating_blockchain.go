@@ -29,6 +29,7 @@
 package daemons
 
 import (
+	"bufio"
 	"io"
 	"os"
 
@@ -113,12 +114,16 @@ func readBlock(r io.Reader, logger *log.Entry) (*blockData, error) {
 	buf := make([]byte, WordSize)
 
 	if _, err = io.ReadFull(r, buf); err != nil {
+		if err == io.EOF {
+			return nil, err
+		}
 		logger.WithFields(log.Fields{"type": consts.IOError, "error": err}).Error("reading block from file")
 		return nil, err
 	}
 
 	size := converter.BinToDec(buf)
-	if size > syspar.GetMaxBlockSize() {
+	// system parameters are empty until the first block has been played
+	if maxSize := syspar.GetMaxBlockSize(); maxSize > 0 && size > maxSize {
 		logger.WithFields(log.Fields{"size": size, "max_size": syspar.GetMaxBlockSize(), "type": consts.ParameterExceeded}).Error("reading block from file")
 		return nil, utils.ErrInfo("size > conts.MAX_BLOCK_SIZE")
 	}
@@ -128,7 +133,7 @@ func readBlock(r io.Reader, logger *log.Entry) (*blockData, error) {
 	}
 
 	dataBinary := make([]byte, size+WordSize)
-	if _, err = r.Read(dataBinary); err != nil {
+	if _, err = io.ReadFull(r, dataBinary); err != nil {
 		logger.WithFields(log.Fields{"type": consts.IOError, "error": err}).Error("reading block from file")
 		return nil, utils.ErrInfo(err)
 	}
@@ -142,60 +147,118 @@ func readBlock(r io.Reader, logger *log.Entry) (*blockData, error) {
 	return &block, nil
 }
 
-// read last block from file
+// getLastBlockID returns the id of the last block in the file. The empty or missing file
+// is resumed from the first block. If the last record is incomplete (the write was interrupted)
+// then the file is truncated to the last complete record.
 func getLastBlockID(fileName string, logger *log.Entry) (int64, error) {
-	file, err := os.Open(fileName)
+	file, err := os.OpenFile(fileName, os.O_RDWR, 0600)
 	if err != nil {
-		logger.WithFields(log.Fields{"error": err, "type": consts.IOError}).Error("opening last block file")
 		// if file doesn't exist create new one
 		if os.IsNotExist(err) {
 			return 0, nil
 		}
+		logger.WithFields(log.Fields{"error": err, "type": consts.IOError}).Error("opening last block file")
 		return 0, utils.ErrInfo(err)
 	}
 	defer file.Close()
 
 	fi, err := file.Stat()
 	if err != nil {
-		logger.WithFields(log.Fields{"error": err, "type": consts.IOError}).Fatal("stat last block file")
+		logger.WithFields(log.Fields{"error": err, "type": consts.IOError}).Error("stat last block file")
+		return 0, utils.ErrInfo(err)
 	}
 	if fi.Size() == 0 {
-		logger.WithFields(log.Fields{"error": err, "type": consts.IOError}).Error("last block file is empty")
-		return 0, utils.ErrInfo("empty blockchain file")
+		return 0, nil
 	}
 
-	// size of a block recorded into the last 5 bytes of blockchain file
-	_, err = file.Seek(-WordSize, os.SEEK_END)
-	if err != nil {
+	if blockID, ok := readLastBlockID(file, fi.Size()); ok {
+		return blockID, nil
+	}
+
+	// the last record is broken, look for the end of the last complete record
+	if _, err = file.Seek(0, io.SeekStart); err != nil {
 		logger.WithFields(log.Fields{"error": err, "type": consts.IOError}).Error("seek last block file")
 		return 0, utils.ErrInfo(err)
 	}
+	blockID, offset := scanBlockFile(bufio.NewReader(file))
+	logger.WithFields(log.Fields{"type": consts.IOError, "size": fi.Size(), "offset": offset, "block_id": blockID}).Warn("truncating incomplete record of block file")
+	if err = file.Truncate(offset); err != nil {
+		logger.WithFields(log.Fields{"error": err, "type": consts.IOError}).Error("truncating block file")
+		return 0, utils.ErrInfo(err)
+	}
+	return blockID, nil
+}
 
+// readLastBlockID reads the last record using its size from the last 5 bytes of the file
+func readLastBlockID(file *os.File, fileSize int64) (int64, bool) {
+	if fileSize < 3*WordSize {
+		return 0, false
+	}
 	buf := make([]byte, WordSize)
-	_, err = file.Read(buf)
-	if err != nil {
-		logger.WithFields(log.Fields{"error": err, "type": consts.IOError}).Error("reading size from last block file")
-		return 0, utils.ErrInfo(err)
+	if _, err := file.ReadAt(buf, fileSize-WordSize); err != nil {
+		return 0, false
 	}
 	size := converter.BinToDec(buf)
-	if size > syspar.GetMaxBlockSize() {
-		logger.WithFields(log.Fields{"size": size, "max_size": syspar.GetMaxBlockSize(), "type": consts.ParameterExceeded}).Error("block size is more than max size")
-		return 0, utils.ErrInfo("size > conts.MAX_BLOCK_SIZE")
+	if size <= WordSize || size > fileSize-WordSize {
+		return 0, false
 	}
 
-	// read the block
-	_, err = file.Seek(-(size + WordSize), os.SEEK_END)
-	if err != nil {
-		logger.WithFields(log.Fields{"type": consts.IOError, "error": err}).Error("seeking the block from last block file")
-		return 0, utils.ErrInfo(err)
+	record := make([]byte, size)
+	if _, err := file.ReadAt(record, fileSize-WordSize-size); err != nil {
+		return 0, false
 	}
+	bd, ok := parseFileRecord(record)
+	if !ok {
+		return 0, false
+	}
+	return bd.ID, true
+}
 
-	block, err := readBlock(file, logger)
-	if err != nil {
-		return 0, utils.ErrInfo(err)
+// parseFileRecord parses the record without the trailing size
+func parseFileRecord(record []byte) (blockData, bool) {
+	if len(record) <= 2*WordSize || converter.BinToDec(record[:WordSize]) != int64(len(record)-WordSize) {
+		return blockData{}, false
 	}
+	data := record[WordSize:]
+	blockID := converter.BinToDec(data[:WordSize])
+	data = data[WordSize:]
+	length, err := converter.DecodeLength(&data)
+	if err != nil || length != int64(len(data)) || blockID <= 0 {
+		return blockData{}, false
+	}
+	return blockData{ID: blockID, Data: data}, true
+}
 
-	return block.ID, nil
+// scanBlockFile reads the records from the beginning and returns the id of the last complete block
+// and the offset of the end of its record
+func scanBlockFile(r io.Reader) (blockID int64, offset int64) {
+	buf := make([]byte, WordSize)
+	for {
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return
+		}
+		size := converter.BinToDec(buf)
+		if size <= WordSize {
+			return
+		}
+		if maxSize := syspar.GetMaxBlockSize(); maxSize > 0 && size > maxSize+2*WordSize {
+			return
+		}
+		record := make([]byte, WordSize+size+WordSize)
+		copy(record, buf)
+		if _, err := io.ReadFull(r, record[WordSize:]); err != nil {
+			return
+		}
+		if converter.BinToDec(record[WordSize+size:]) != WordSize+size {
+			return
+		}
+		bd, ok := parseFileRecord(record[:WordSize+size])
+		if !ok {
+			return
+		}
+		blockID = bd.ID
+		offset += int64(len(record))
+	}
 }
 
 func unmarshalBlockData(buff []byte, logger *log.Entry) (blockData, error) {
@@ -37,7 +37,6 @@ import (
 	"github.com/AplaProject/go-apla/packages/conf/syspar"
 	"github.com/AplaProject/go-apla/packages/consts"
 	"github.com/AplaProject/go-apla/packages/model"
-	"github.com/AplaProject/go-apla/packages/service"
 
 	log "github.com/sirupsen/logrus"
 )
@@ -80,7 +79,7 @@ func sendTransactions(ctx context.Context, logger *log.Entry) error {
 		return nil
 	}
 
-	hosts, err := service.GetNodesBanService().FilterBannedHosts(syspar.GetRemoteHosts())
+	hosts, err := remoteHosts()
 	if err != nil {
 		log.WithFields(log.Fields{"error": err}).Error("on getting remotes hosts")
 		return err
@@ -122,7 +121,7 @@ func sendBlockWithTxHashes(ctx context.Context, fullNodeID int64, logger *log.En
 		return nil
 	}
 
-	hosts, err := service.GetNodesBanService().FilterBannedHosts(syspar.GetRemoteHosts())
+	hosts, err := remoteHosts()
 	if err != nil {
 		log.WithFields(log.Fields{"error": err}).Error("on getting remotes hosts")
 		return err
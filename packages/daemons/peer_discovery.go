@@ -0,0 +1,117 @@
+// Apla Software includes an integrated development
+// environment with a multi-level system for the management
+// of access rights to data, interfaces, and Smart contracts. The
+// technical characteristics of the Apla Software are indicated in
+// Apla Technical Paper.
+
+// Apla Users are granted a permission to deal in the Apla
+// Software without restrictions, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of Apla Software, and to permit persons
+// to whom Apla Software is furnished to do so, subject to the
+// following conditions:
+// * the copyright notice of GenesisKernel and EGAAS S.A.
+// and this permission notice shall be included in all copies or
+// substantial portions of the software;
+// * a result of the dealing in Apla Software cannot be
+// implemented outside of the Apla Platform environment.
+
+// THE APLA SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY
+// OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED
+// TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE, ERROR FREE AND NONINFRINGEMENT. IN
+// NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR
+// THE USE OR OTHER DEALINGS IN THE APLA SOFTWARE.
+
+package daemons
+
+import (
+	"context"
+	"time"
+
+	"github.com/AplaProject/go-apla/packages/conf"
+	"github.com/AplaProject/go-apla/packages/conf/syspar"
+	"github.com/AplaProject/go-apla/packages/consts"
+	"github.com/AplaProject/go-apla/packages/network/peers"
+	"github.com/AplaProject/go-apla/packages/network/tcpclient"
+	"github.com/AplaProject/go-apla/packages/service"
+	"github.com/AplaProject/go-apla/packages/utils"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// discoveryHostsCount is the number of hosts which are asked for peers at once
+	discoveryHostsCount = 3
+	// remotePeersCount is the maximum number of peers which are used in addition to full nodes
+	remotePeersCount = 20
+)
+
+// PeerDiscovery asks other nodes for their peers and saves them to the table of peers
+func PeerDiscovery(ctx context.Context, d *daemon) error {
+	d.sleepTime = time.Minute
+
+	if ctx.Err() != nil {
+		d.logger.WithFields(log.Fields{"type": consts.ContextError, "error": ctx.Err()}).Error("context error")
+		return ctx.Err()
+	}
+
+	table := peers.GetTable()
+	// the peers which have never been connected are asked too, so they get the score
+	hosts := appendHosts(syspar.GetRemoteHosts(), conf.GetNodesAddr()...)
+	for _, p := range table.Peers() {
+		hosts = appendHosts(hosts, p.Address)
+	}
+	utils.ShuffleSlice(hosts)
+	if len(hosts) > discoveryHostsCount {
+		hosts = hosts[:discoveryHostsCount]
+	}
+
+	var count int
+	for _, host := range hosts {
+		list, err := tcpclient.GetPeers(host, conf.Config.TCPServer.Str())
+		if err != nil {
+			table.Fail(host)
+			continue
+		}
+		table.Success(host)
+		for _, address := range list {
+			if table.Add(address) {
+				count++
+			}
+		}
+	}
+	if count > 0 {
+		d.logger.WithFields(log.Fields{"count": count}).Info("new peers found")
+	}
+
+	return table.Save()
+}
+
+// remoteHosts returns not banned full nodes and the best peers. The blocks of peers are safe
+// because they are signed by full nodes and checked, the bad peers lose the score.
+func remoteHosts() ([]string, error) {
+	hosts, err := service.GetNodesBanService().FilterBannedHosts(syspar.GetRemoteHosts())
+	if err != nil {
+		return nil, err
+	}
+	return appendHosts(hosts, peers.GetTable().Hosts(remotePeersCount)...), nil
+}
+
+// appendHosts appends the hosts which aren't in the list yet
+func appendHosts(list []string, hosts ...string) []string {
+	exist := make(map[string]bool, len(list))
+	for _, h := range list {
+		exist[h] = true
+	}
+	for _, h := range hosts {
+		if !exist[h] {
+			exist[h] = true
+			list = append(list, h)
+		}
+	}
+	return list
+}
@@ -45,12 +45,14 @@ import (
 	"github.com/AplaProject/go-apla/packages/converter"
 	"github.com/AplaProject/go-apla/packages/daemons"
 	"github.com/AplaProject/go-apla/packages/daylight/daemonsctl"
+	"github.com/AplaProject/go-apla/packages/keystore"
 	logtools "github.com/AplaProject/go-apla/packages/log"
 	"github.com/AplaProject/go-apla/packages/model"
 	"github.com/AplaProject/go-apla/packages/modes"
 	"github.com/AplaProject/go-apla/packages/network/httpserver"
 	"github.com/AplaProject/go-apla/packages/obsmanager"
 	"github.com/AplaProject/go-apla/packages/publisher"
+	"github.com/AplaProject/go-apla/packages/signer"
 	"github.com/AplaProject/go-apla/packages/smart"
 	"github.com/AplaProject/go-apla/packages/statsd"
 	"github.com/AplaProject/go-apla/packages/utils"
@@ -58,6 +60,17 @@ import (
 	log "github.com/sirupsen/logrus"
 )
 
+func unlockKeys() error {
+	passphrase, err := keystore.LoadPassphrase(conf.Config.KeysPassphraseFile)
+	if err != nil {
+		return err
+	}
+	return keystore.Unlock(passphrase,
+		filepath.Join(conf.Config.KeysDir, consts.NodePrivateKeyFilename),
+		filepath.Join(conf.Config.KeysDir, consts.PrivateKeyFilename),
+	)
+}
+
 func initStatsd() {
 	cfg := conf.Config.StatsD
 	if err := statsd.Init(cfg.Host, cfg.Port, cfg.Name); err != nil {
@@ -237,9 +250,22 @@ func Start() {
 
 	killOld()
 
-	publisher.InitCentrifugo(conf.Config.Centrifugo)
+	if err := publisher.Init(conf.Config.Publisher, conf.Config.Centrifugo); err != nil {
+		log.WithFields(log.Fields{"type": consts.ConfigError, "error": err}).Error("can't init publisher")
+		Exit(1)
+	}
 	initStatsd()
 
+	if err := unlockKeys(); err != nil {
+		log.WithFields(log.Fields{"type": consts.CryptoError, "error": err}).Error("can't unlock keys")
+		Exit(1)
+	}
+
+	if err := signer.Init(conf.Config.Signer); err != nil {
+		log.WithFields(log.Fields{"type": consts.ConfigError, "error": err}).Error("can't init signer")
+		Exit(1)
+	}
+
 	err = initLogs()
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "logs init failed: %v\n", utils.ErrInfo(err))
@@ -0,0 +1,144 @@
+// Apla Software includes an integrated development
+// environment with a multi-level system for the management
+// of access rights to data, interfaces, and Smart contracts. The
+// technical characteristics of the Apla Software are indicated in
+// Apla Technical Paper.
+
+// Apla Users are granted a permission to deal in the Apla
+// Software without restrictions, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of Apla Software, and to permit persons
+// to whom Apla Software is furnished to do so, subject to the
+// following conditions:
+// * the copyright notice of GenesisKernel and EGAAS S.A.
+// and this permission notice shall be included in all copies or
+// substantial portions of the software;
+// * a result of the dealing in Apla Software cannot be
+// implemented outside of the Apla Platform environment.
+
+// THE APLA SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY
+// OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED
+// TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE, ERROR FREE AND NONINFRINGEMENT. IN
+// NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR
+// THE USE OR OTHER DEALINGS IN THE APLA SOFTWARE.
+
+package keystore
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"io/ioutil"
+	"os"
+	"sync"
+
+	"github.com/AplaProject/go-apla/packages/consts"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// PassphraseEnv is the environment variable with the passphrase of the encrypted keys
+const PassphraseEnv = "APLA_KEYS_PASSPHRASE"
+
+// ErrLocked is returned if the key file is encrypted and the passphrase hasn't been set
+var ErrLocked = errors.New("Key file is encrypted, passphrase is required")
+
+type cachedKey struct {
+	data       []byte
+	privateKey []byte
+}
+
+var (
+	mutex      sync.RWMutex
+	passphrase []byte
+	cache      = make(map[string]cachedKey)
+)
+
+// LoadPassphrase returns the passphrase from the file or from the environment variable if the file isn't specified
+func LoadPassphrase(path string) ([]byte, error) {
+	if len(path) == 0 {
+		return []byte(os.Getenv(PassphraseEnv)), nil
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.IOError, "error": err, "path": path}).Error("reading passphrase file")
+		return nil, err
+	}
+	return bytes.TrimRight(data, "\r\n"), nil
+}
+
+// Unlock sets the passphrase of the encrypted key files and checks it on the files of the paths which exist
+func Unlock(pass []byte, paths ...string) error {
+	mutex.Lock()
+	passphrase = pass
+	cache = make(map[string]cachedKey)
+	mutex.Unlock()
+
+	for _, path := range paths {
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			continue
+		}
+		if _, err := ReadFile(path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadFile returns the private key from the key file, the encrypted key is decrypted by the passphrase from Unlock
+func ReadFile(path string) ([]byte, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.IOError, "error": err, "path": path}).Error("reading private key from file")
+		return nil, err
+	}
+	if !IsEncrypted(data) {
+		return Decode(data, nil)
+	}
+
+	mutex.RLock()
+	c, ok := cache[path]
+	pass := passphrase
+	mutex.RUnlock()
+	if ok && bytes.Equal(c.data, data) {
+		return c.privateKey, nil
+	}
+	if len(pass) == 0 {
+		log.WithFields(log.Fields{"type": consts.CryptoError, "path": path}).Error("key file is encrypted, but passphrase isn't set")
+		return nil, ErrLocked
+	}
+
+	privateKey, err := Decrypt(data, pass)
+	if err != nil {
+		return nil, err
+	}
+	mutex.Lock()
+	cache[path] = cachedKey{data: data, privateKey: privateKey}
+	mutex.Unlock()
+	return privateKey, nil
+}
+
+// WriteFile writes the private key to the key file, the key is encrypted if the passphrase isn't empty
+func WriteFile(path string, privateKey, pass []byte) error {
+	var (
+		data []byte
+		err  error
+	)
+	if len(pass) > 0 {
+		if data, err = Encrypt(privateKey, pass); err != nil {
+			return err
+		}
+	} else {
+		data = []byte(hex.EncodeToString(privateKey))
+	}
+
+	if err = ioutil.WriteFile(path, data, 0600); err != nil {
+		log.WithFields(log.Fields{"type": consts.IOError, "error": err, "path": path}).Error("writing private key to file")
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,224 @@
+// Apla Software includes an integrated development
+// environment with a multi-level system for the management
+// of access rights to data, interfaces, and Smart contracts. The
+// technical characteristics of the Apla Software are indicated in
+// Apla Technical Paper.
+
+// Apla Users are granted a permission to deal in the Apla
+// Software without restrictions, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of Apla Software, and to permit persons
+// to whom Apla Software is furnished to do so, subject to the
+// following conditions:
+// * the copyright notice of GenesisKernel and EGAAS S.A.
+// and this permission notice shall be included in all copies or
+// substantial portions of the software;
+// * a result of the dealing in Apla Software cannot be
+// implemented outside of the Apla Platform environment.
+
+// THE APLA SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY
+// OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED
+// TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE, ERROR FREE AND NONINFRINGEMENT. IN
+// NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR
+// THE USE OR OTHER DEALINGS IN THE APLA SOFTWARE.
+
+package keystore
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/AplaProject/go-apla/packages/consts"
+	"github.com/AplaProject/go-apla/packages/crypto"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/crypto/pbkdf2"
+)
+
+const (
+	// Version is the version of the keystore format
+	Version = 1
+
+	// KDFPBKDF2 is PBKDF2 with HMAC-SHA256
+	KDFPBKDF2 = "pbkdf2-sha256"
+	// CipherAESGCM is AES-256 in GCM mode
+	CipherAESGCM = "aes-256-gcm"
+
+	// DefaultIterations is the count of PBKDF2 iterations for the new keystores
+	DefaultIterations = 262144
+
+	saltSize = 32
+	keySize  = 32
+)
+
+var (
+	// ErrVersion is returned if the keystore has the unsupported version or algorithms
+	ErrVersion = errors.New("Unsupported keystore format")
+	// ErrPassphrase is returned if the keystore can't be decrypted with the passphrase
+	ErrPassphrase = errors.New("Wrong passphrase or corrupted keystore")
+	// ErrEmptyPassphrase is returned if the passphrase is empty
+	ErrEmptyPassphrase = errors.New("Passphrase is empty")
+)
+
+// iterations can be lowered in tests
+var iterations = DefaultIterations
+
+// KDFParams are the parameters of the key derivation
+type KDFParams struct {
+	Iterations int    `json:"iterations"`
+	Salt       string `json:"salt"`
+}
+
+// Key is the private key which is encrypted by the passphrase
+type Key struct {
+	Version    int       `json:"version"`
+	PublicKey  string    `json:"public_key"`
+	KDF        string    `json:"kdf"`
+	KDFParams  KDFParams `json:"kdfparams"`
+	Cipher     string    `json:"cipher"`
+	Nonce      string    `json:"nonce"`
+	CipherText string    `json:"ciphertext"`
+}
+
+// IsEncrypted returns true if the data of the key file is the keystore and not the hex key
+func IsEncrypted(data []byte) bool {
+	data = bytes.TrimSpace(data)
+	return len(data) > 0 && data[0] == '{'
+}
+
+func newAEAD(passphrase, salt []byte, iter int) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(pbkdf2.Key(passphrase, salt, iter, keySize, sha256.New))
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.CryptoError, "error": err}).Error("creating cipher")
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.CryptoError, "error": err}).Error("creating GCM")
+		return nil, err
+	}
+	return aead, nil
+}
+
+// Encrypt returns the keystore of the private key which is encrypted by the passphrase
+func Encrypt(privateKey, passphrase []byte) ([]byte, error) {
+	if len(passphrase) == 0 {
+		return nil, ErrEmptyPassphrase
+	}
+	publicKey, err := crypto.PrivateToPublic(privateKey)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.CryptoError, "error": err}).Error("converting private key to public")
+		return nil, err
+	}
+
+	salt := make([]byte, saltSize)
+	if _, err = rand.Read(salt); err != nil {
+		log.WithFields(log.Fields{"type": consts.CryptoError, "error": err}).Error("generating salt")
+		return nil, err
+	}
+	aead, err := newAEAD(passphrase, salt, iterations)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err = rand.Read(nonce); err != nil {
+		log.WithFields(log.Fields{"type": consts.CryptoError, "error": err}).Error("generating nonce")
+		return nil, err
+	}
+
+	key := Key{
+		Version:   Version,
+		PublicKey: crypto.PubToHex(publicKey),
+		KDF:       KDFPBKDF2,
+		KDFParams: KDFParams{
+			Iterations: iterations,
+			Salt:       hex.EncodeToString(salt),
+		},
+		Cipher: CipherAESGCM,
+		Nonce:  hex.EncodeToString(nonce),
+	}
+	key.CipherText = hex.EncodeToString(aead.Seal(nil, nonce, privateKey, key.additionalData()))
+
+	data, err := json.MarshalIndent(key, "", "  ")
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.JSONMarshallError, "error": err}).Error("marshalling keystore")
+		return nil, err
+	}
+	return data, nil
+}
+
+// Decrypt returns the private key from the keystore
+func Decrypt(data, passphrase []byte) ([]byte, error) {
+	var key Key
+	if err := json.Unmarshal(data, &key); err != nil {
+		log.WithFields(log.Fields{"type": consts.JSONUnmarshallError, "error": err}).Error("unmarshalling keystore")
+		return nil, err
+	}
+	if key.Version != Version || key.KDF != KDFPBKDF2 || key.Cipher != CipherAESGCM || key.KDFParams.Iterations < 1 {
+		log.WithFields(log.Fields{"type": consts.ParameterExceeded, "version": key.Version, "kdf": key.KDF, "cipher": key.Cipher}).Error("unsupported keystore")
+		return nil, ErrVersion
+	}
+	if len(passphrase) == 0 {
+		return nil, ErrEmptyPassphrase
+	}
+
+	salt, err := hex.DecodeString(key.KDFParams.Salt)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.ConversionError, "error": err}).Error("decoding salt from hex")
+		return nil, err
+	}
+	nonce, err := hex.DecodeString(key.Nonce)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.ConversionError, "error": err}).Error("decoding nonce from hex")
+		return nil, err
+	}
+	cipherText, err := hex.DecodeString(key.CipherText)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.ConversionError, "error": err}).Error("decoding ciphertext from hex")
+		return nil, err
+	}
+
+	aead, err := newAEAD(passphrase, salt, key.KDFParams.Iterations)
+	if err != nil {
+		return nil, err
+	}
+	if len(nonce) != aead.NonceSize() {
+		log.WithFields(log.Fields{"type": consts.CryptoError, "size": len(nonce)}).Error("wrong nonce size")
+		return nil, ErrPassphrase
+	}
+	privateKey, err := aead.Open(nil, nonce, cipherText, key.additionalData())
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.CryptoError}).Error("decrypting keystore")
+		return nil, ErrPassphrase
+	}
+	return privateKey, nil
+}
+
+// Decode returns the private key from the data of the key file which is either the hex key or the keystore
+func Decode(data, passphrase []byte) ([]byte, error) {
+	if IsEncrypted(data) {
+		return Decrypt(data, passphrase)
+	}
+	privateKey, err := hex.DecodeString(string(bytes.TrimSpace(data)))
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.ConversionError, "error": err}).Error("decoding private key from hex")
+		return nil, err
+	}
+	return privateKey, nil
+}
+
+// additionalData binds the header of the keystore to the ciphertext
+func (k *Key) additionalData() []byte {
+	return []byte(fmt.Sprintf("%d:%s:%s:%s", k.Version, k.KDF, k.Cipher, k.PublicKey))
+}
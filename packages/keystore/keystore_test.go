@@ -0,0 +1,151 @@
+// Apla Software includes an integrated development
+// environment with a multi-level system for the management
+// of access rights to data, interfaces, and Smart contracts. The
+// technical characteristics of the Apla Software are indicated in
+// Apla Technical Paper.
+
+// Apla Users are granted a permission to deal in the Apla
+// Software without restrictions, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of Apla Software, and to permit persons
+// to whom Apla Software is furnished to do so, subject to the
+// following conditions:
+// * the copyright notice of GenesisKernel and EGAAS S.A.
+// and this permission notice shall be included in all copies or
+// substantial portions of the software;
+// * a result of the dealing in Apla Software cannot be
+// implemented outside of the Apla Platform environment.
+
+// THE APLA SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY
+// OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED
+// TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE, ERROR FREE AND NONINFRINGEMENT. IN
+// NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR
+// THE USE OR OTHER DEALINGS IN THE APLA SOFTWARE.
+
+package keystore
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/AplaProject/go-apla/packages/crypto"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/pbkdf2"
+)
+
+func init() {
+	iterations = 16
+}
+
+func TestPBKDF2(t *testing.T) {
+	// test vectors of PBKDF2-HMAC-SHA256 from RFC 7914, the keystore format depends on them
+	assert.Equal(t,
+		"55ac046e56e3089fec1691c22544b605f94185216dde0465e68b9d57c20dacbc49ca9cccf179b645991664b39d77ef317c71b845b1e30bd509112041d3a19783",
+		hex.EncodeToString(pbkdf2.Key([]byte("passwd"), []byte("salt"), 1, 64, sha256.New)))
+	assert.Equal(t,
+		"4ddcd8f60b98be21830cee5ef22701f9641a4418d04c0414aeff08876b34ab56a1d425a1225833549adb841b51c9b3176a272bdebba1d078478f62b397f33c8d",
+		hex.EncodeToString(pbkdf2.Key([]byte("Password"), []byte("NaCl"), 80000, 64, sha256.New)))
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	priv, pub, err := crypto.GenBytesKeys()
+	require.NoError(t, err)
+
+	data, err := Encrypt(priv, []byte("secret"))
+	require.NoError(t, err)
+	assert.True(t, IsEncrypted(data))
+	assert.NotContains(t, string(data), hex.EncodeToString(priv))
+
+	var key Key
+	require.NoError(t, json.Unmarshal(data, &key))
+	assert.Equal(t, Version, key.Version)
+	assert.Equal(t, crypto.PubToHex(pub), key.PublicKey)
+
+	decrypted, err := Decrypt(data, []byte("secret"))
+	require.NoError(t, err)
+	assert.Equal(t, priv, decrypted)
+
+	_, err = Decrypt(data, []byte("wrong"))
+	assert.Equal(t, ErrPassphrase, err)
+	_, err = Decrypt(data, nil)
+	assert.Equal(t, ErrEmptyPassphrase, err)
+
+	key.PublicKey = crypto.PubToHex(priv)
+	tampered, err := json.Marshal(key)
+	require.NoError(t, err)
+	_, err = Decrypt(tampered, []byte("secret"))
+	assert.Equal(t, ErrPassphrase, err)
+
+	key.Version = Version + 1
+	tampered, err = json.Marshal(key)
+	require.NoError(t, err)
+	_, err = Decrypt(tampered, []byte("secret"))
+	assert.Equal(t, ErrVersion, err)
+
+	_, err = Encrypt(priv, nil)
+	assert.Equal(t, ErrEmptyPassphrase, err)
+}
+
+func TestReadFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "keystore")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	priv, _, err := crypto.GenBytesKeys()
+	require.NoError(t, err)
+
+	plain := filepath.Join(dir, "PrivateKey")
+	encrypted := filepath.Join(dir, "NodePrivateKey")
+	require.NoError(t, WriteFile(plain, priv, nil))
+	require.NoError(t, WriteFile(encrypted, priv, []byte("secret")))
+
+	require.NoError(t, Unlock(nil, plain))
+	key, err := ReadFile(plain)
+	require.NoError(t, err)
+	assert.Equal(t, priv, key)
+	_, err = ReadFile(encrypted)
+	assert.Equal(t, ErrLocked, err)
+
+	assert.Equal(t, ErrPassphrase, Unlock([]byte("wrong"), encrypted))
+	require.NoError(t, Unlock([]byte("secret"), plain, encrypted, filepath.Join(dir, "missing")))
+	key, err = ReadFile(encrypted)
+	require.NoError(t, err)
+	assert.Equal(t, priv, key)
+
+	// the key file is reread after it has been changed
+	newPriv, _, err := crypto.GenBytesKeys()
+	require.NoError(t, err)
+	require.NoError(t, WriteFile(encrypted, newPriv, []byte("secret")))
+	key, err = ReadFile(encrypted)
+	require.NoError(t, err)
+	assert.Equal(t, newPriv, key)
+}
+
+func TestLoadPassphrase(t *testing.T) {
+	f, err := ioutil.TempFile("", "passphrase")
+	require.NoError(t, err)
+	defer os.Remove(f.Name())
+	f.WriteString("secret\n")
+	f.Close()
+
+	pass, err := LoadPassphrase(f.Name())
+	require.NoError(t, err)
+	assert.Equal(t, []byte("secret"), pass)
+
+	os.Setenv(PassphraseEnv, "env secret")
+	defer os.Unsetenv(PassphraseEnv)
+	pass, err = LoadPassphrase("")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("env secret"), pass)
+}
@@ -600,6 +600,24 @@ VALUES
         }
     }
 }
+', 'ContractConditions("MainCondition")', '1', '1'),
+	(next_id('1_contracts'), 'MultiwalletCreate', 'contract MultiwalletCreate {
+	data {
+		Threshold int
+		PublicKeys array
+	}
+	conditions {
+		if Len($PublicKeys) == 0 {
+			error "Public keys are empty"
+		}
+		if $Threshold < 1 || $Threshold > Len($PublicKeys) {
+			error "Threshold must be from 1 to the count of public keys"
+		}
+	}
+	action {
+		$result = CreateMultisig($Threshold, $PublicKeys)
+	}
+}
 ', 'ContractConditions("MainCondition")', '1', '1'),
 	(next_id('1_contracts'), 'NewAppParam', 'contract NewAppParam {
     data {
@@ -865,6 +883,7 @@ VALUES
 	(next_id('1_contracts'), 'NewUser', 'contract NewUser {
 	data {
 		NewPubkey string
+		KeyType int "optional"
 	}
 	conditions {
 		$newId = PubToID($NewPubkey)
@@ -879,7 +898,7 @@ VALUES
 	}
 	action {
         NewMoney($newId, Str($amount), "New user deposit")
-        SetPubKey($newId, StringToBytes($NewPubkey))
+        SetPubKey($newId, StringToBytes($NewPubkey), $KeyType)
 	}
 }
 ', 'ContractConditions("NodeOwnerCondition")', '1', '1'),
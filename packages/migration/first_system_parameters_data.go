@@ -95,5 +95,11 @@ INSERT INTO "1_system_parameters" ("id","name", "value", "conditions") VALUES
 	('63','price_tx_data', '0', 'ContractAccess("@1UpdateSysParam")'),
 	('64', 'price_exec_contract_by_name', '0', 'ContractAccess("@1UpdateSysParam")'),
 	('65', 'price_exec_contract_by_id', '0', 'ContractAccess("@1UpdateSysParam")'),
-	('66','private_blockchain', '1', 'false');
+	('66','private_blockchain', '1', 'false'),
+	('67','fuel_schedule', '{}', 'ContractAccess("@1UpdateSysParam")'),
+	('68','key_types_block', '1', 'ContractAccess("@1UpdateSysParam")'),
+	('69','fuel_schedule_block', '1', 'ContractAccess("@1UpdateSysParam")'),
+	('70','price_exec_emit_event', '50', 'ContractAccess("@1UpdateSysParam")'),
+	('71','tx_slot_block', '1', 'ContractAccess("@1UpdateSysParam")'),
+	('72','loops_block', '1', 'ContractAccess("@1UpdateSysParam")');
 `
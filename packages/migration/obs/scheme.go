@@ -60,6 +60,7 @@ var schemaOBS = `DROP TABLE IF EXISTS "1_keys"; CREATE TABLE "1_keys" (
 		"multi" bigint NOT NULL DEFAULT '0',
 		"deleted" bigint NOT NULL DEFAULT '0',
 		"blocked" bigint NOT NULL DEFAULT '0',
+		"key_type" smallint NOT NULL DEFAULT '0',
 		"ecosystem" bigint NOT NULL DEFAULT '1'
 		);
 		ALTER TABLE ONLY "1_keys" ADD CONSTRAINT "%[1]d_keys_pkey" PRIMARY KEY (id,ecosystem);
@@ -93,5 +93,11 @@ INSERT INTO "1_system_parameters" ("id","name", "value", "conditions") VALUES
 	('67','max_forsign_size','1000000','true'),
 	('68','price_tx_data','0','true'),
 	('69','price_exec_contract_by_name', '0', 'true'),
-	('70','price_exec_contract_by_id', '0', 'true');
+	('70','price_exec_contract_by_id', '0', 'true'),
+	('71','fuel_schedule', '{}', 'true'),
+	('72','key_types_block', '1', 'true'),
+	('73','fuel_schedule_block', '1', 'true'),
+	('74','price_exec_emit_event', '50', 'true'),
+	('75','tx_slot_block', '1', 'true'),
+	('76','loops_block', '1', 'true');
 `
@@ -59,6 +59,7 @@ var tablesDataSQL = `INSERT INTO "1_tables" ("id", "name", "permissions","column
             "deleted": "ContractConditions(\"@1AdminCondition\")",
             "blocked": "ContractAccess(\"@1TokensLockoutMember\")",
             "multi": "ContractAccess(\"@1MultiwalletCreate\")",
+            "key_type": "false",
             "ecosystem": "false"
         }',
         'ContractConditions("@1AdminCondition")', '%[1]d'
@@ -0,0 +1,36 @@
+// Apla Software includes an integrated development
+// environment with a multi-level system for the management
+// of access rights to data, interfaces, and Smart contracts. The
+// technical characteristics of the Apla Software are indicated in
+// Apla Technical Paper.
+
+// Apla Users are granted a permission to deal in the Apla
+// Software without restrictions, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of Apla Software, and to permit persons
+// to whom Apla Software is furnished to do so, subject to the
+// following conditions:
+// * the copyright notice of GenesisKernel and EGAAS S.A.
+// and this permission notice shall be included in all copies or
+// substantial portions of the software;
+// * a result of the dealing in Apla Software cannot be
+// implemented outside of the Apla Platform environment.
+
+// THE APLA SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY
+// OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED
+// TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE, ERROR FREE AND NONINFRINGEMENT. IN
+// NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR
+// THE USE OR OTHER DEALINGS IN THE APLA SOFTWARE.
+
+package updates
+
+var M125 = `ALTER TABLE "transactions" ADD COLUMN IF NOT EXISTS "fee" bigint NOT NULL DEFAULT '0';
+	ALTER TABLE "transactions" ADD COLUMN IF NOT EXISTS "tx_time" bigint NOT NULL DEFAULT '0';
+	ALTER TABLE "transactions" ADD COLUMN IF NOT EXISTS "contract_id" bigint NOT NULL DEFAULT '0';
+	CREATE INDEX IF NOT EXISTS "transactions_index_pool" ON "transactions" (used, high_rate DESC, fee DESC, tx_time);
+	CREATE INDEX IF NOT EXISTS "transactions_index_slot" ON "transactions" (key_id, tx_time, contract_id);
+`
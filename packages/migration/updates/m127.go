@@ -0,0 +1,55 @@
+// Apla Software includes an integrated development
+// environment with a multi-level system for the management
+// of access rights to data, interfaces, and Smart contracts. The
+// technical characteristics of the Apla Software are indicated in
+// Apla Technical Paper.
+
+// Apla Users are granted a permission to deal in the Apla
+// Software without restrictions, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of Apla Software, and to permit persons
+// to whom Apla Software is furnished to do so, subject to the
+// following conditions:
+// * the copyright notice of GenesisKernel and EGAAS S.A.
+// and this permission notice shall be included in all copies or
+// substantial portions of the software;
+// * a result of the dealing in Apla Software cannot be
+// implemented outside of the Apla Platform environment.
+
+// THE APLA SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY
+// OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED
+// TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE, ERROR FREE AND NONINFRINGEMENT. IN
+// NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR
+// THE USE OR OTHER DEALINGS IN THE APLA SOFTWARE.
+
+package updates
+
+var M127 = `CREATE TABLE IF NOT EXISTS "1_multisig_signers" (
+		"id" bigint NOT NULL DEFAULT '0',
+		"account_id" bigint NOT NULL DEFAULT '0',
+		"key_id" bigint NOT NULL DEFAULT '0',
+		"pub" bytea NOT NULL DEFAULT '',
+		"key_type" smallint NOT NULL DEFAULT '0',
+		"ecosystem" bigint NOT NULL DEFAULT '1',
+		PRIMARY KEY (id)
+	);
+	CREATE INDEX IF NOT EXISTS "1_multisig_signers_index_account" ON "1_multisig_signers" (ecosystem, account_id);
+	CREATE INDEX IF NOT EXISTS "1_multisig_signers_index_key" ON "1_multisig_signers" (ecosystem, key_id);
+
+	CREATE TABLE IF NOT EXISTS "multisig_pending" (
+		"hash" bytea NOT NULL DEFAULT '',
+		"data" bytea NOT NULL DEFAULT '',
+		"key_id" bigint NOT NULL DEFAULT '0',
+		"ecosystem" bigint NOT NULL DEFAULT '1',
+		"threshold" bigint NOT NULL DEFAULT '0',
+		"signatures" bytea NOT NULL DEFAULT '',
+		"signers" text NOT NULL DEFAULT '',
+		"time" bigint NOT NULL DEFAULT '0',
+		PRIMARY KEY (hash)
+	);
+	CREATE INDEX IF NOT EXISTS "multisig_pending_index_key" ON "multisig_pending" (ecosystem, key_id);
+`
@@ -0,0 +1,70 @@
+// Apla Software includes an integrated development
+// environment with a multi-level system for the management
+// of access rights to data, interfaces, and Smart contracts. The
+// technical characteristics of the Apla Software are indicated in
+// Apla Technical Paper.
+
+// Apla Users are granted a permission to deal in the Apla
+// Software without restrictions, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of Apla Software, and to permit persons
+// to whom Apla Software is furnished to do so, subject to the
+// following conditions:
+// * the copyright notice of GenesisKernel and EGAAS S.A.
+// and this permission notice shall be included in all copies or
+// substantial portions of the software;
+// * a result of the dealing in Apla Software cannot be
+// implemented outside of the Apla Platform environment.
+
+// THE APLA SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY
+// OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED
+// TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE, ERROR FREE AND NONINFRINGEMENT. IN
+// NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR
+// THE USE OR OTHER DEALINGS IN THE APLA SOFTWARE.
+
+package model
+
+// ConfirmationSignature is the confirmation of the block signed by the full node
+type ConfirmationSignature struct {
+	BlockID   int64  `gorm:"primary_key"`
+	KeyID     int64  `gorm:"primary_key"`
+	Hash      []byte `gorm:"not null"`
+	Signature []byte `gorm:"not null"`
+	Time      int64  `gorm:"not null"`
+}
+
+// Save is saving model
+func (cs *ConfirmationSignature) Save() error {
+	return DBConn.Save(cs).Error
+}
+
+// GetConfirmationSignatures returns the signatures of the block with the specified hash
+func GetConfirmationSignatures(blockID int64, hash []byte) ([]ConfirmationSignature, error) {
+	var list []ConfirmationSignature
+	err := DBConn.Where("block_id = ? AND hash = ?", blockID, hash).Order("key_id").Find(&list).Error
+	return list, err
+}
+
+// GetFinalizedBlockID returns the highest block which has been signed by at least required
+// nodes from keyIDs. Only the signatures of the hash of the block in the blockchain are counted.
+func GetFinalizedBlockID(keyIDs []int64, required int) (int64, error) {
+	if len(keyIDs) == 0 {
+		return 0, nil
+	}
+	var result struct {
+		BlockID int64
+	}
+	err := DBConn.Raw(`SELECT s.block_id FROM "confirmation_signatures" s
+		INNER JOIN "block_chain" b ON b.id = s.block_id AND b.hash = s.hash
+		WHERE s.key_id IN (?)
+		GROUP BY s.block_id HAVING count(*) >= ?
+		ORDER BY s.block_id DESC LIMIT 1`, keyIDs, required).Scan(&result).Error
+	if err != nil && err != ErrRecordNotFound {
+		return 0, err
+	}
+	return result.BlockID, nil
+}
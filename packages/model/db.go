@@ -143,17 +143,32 @@ func (tr *DbTransaction) Connection() *gorm.DB {
 
 // Savepoint creates PostgreSQL savepoint
 func (tr *DbTransaction) Savepoint(idTx int) error {
-	return tr.Connection().Exec(fmt.Sprintf("SAVEPOINT \"tx-%d\";", idTx)).Error
+	return tr.NamedSavepoint(fmt.Sprintf("tx-%d", idTx))
 }
 
 // RollbackSavepoint rollbacks PostgreSQL savepoint
 func (tr *DbTransaction) RollbackSavepoint(idTx int) error {
-	return tr.Connection().Exec(fmt.Sprintf("ROLLBACK TO SAVEPOINT \"tx-%d\";", idTx)).Error
+	return tr.RollbackNamedSavepoint(fmt.Sprintf("tx-%d", idTx))
 }
 
 // ReleaseSavepoint releases PostgreSQL savepoint
 func (tr *DbTransaction) ReleaseSavepoint(idTx int) error {
-	return tr.Connection().Exec(fmt.Sprintf("RELEASE SAVEPOINT \"tx-%d\";", idTx)).Error
+	return tr.ReleaseNamedSavepoint(fmt.Sprintf("tx-%d", idTx))
+}
+
+// NamedSavepoint creates PostgreSQL savepoint with the specified name
+func (tr *DbTransaction) NamedSavepoint(name string) error {
+	return tr.Connection().Exec(fmt.Sprintf("SAVEPOINT \"%s\";", name)).Error
+}
+
+// RollbackNamedSavepoint rollbacks PostgreSQL savepoint with the specified name
+func (tr *DbTransaction) RollbackNamedSavepoint(name string) error {
+	return tr.Connection().Exec(fmt.Sprintf("ROLLBACK TO SAVEPOINT \"%s\";", name)).Error
+}
+
+// ReleaseNamedSavepoint releases PostgreSQL savepoint with the specified name
+func (tr *DbTransaction) ReleaseNamedSavepoint(name string) error {
+	return tr.Connection().Exec(fmt.Sprintf("RELEASE SAVEPOINT \"%s\";", name)).Error
 }
 
 // GetDB is returning gorm.DB
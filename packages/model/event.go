@@ -0,0 +1,93 @@
+// Apla Software includes an integrated development
+// environment with a multi-level system for the management
+// of access rights to data, interfaces, and Smart contracts. The
+// technical characteristics of the Apla Software are indicated in
+// Apla Technical Paper.
+
+// Apla Users are granted a permission to deal in the Apla
+// Software without restrictions, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of Apla Software, and to permit persons
+// to whom Apla Software is furnished to do so, subject to the
+// following conditions:
+// * the copyright notice of GenesisKernel and EGAAS S.A.
+// and this permission notice shall be included in all copies or
+// substantial portions of the software;
+// * a result of the dealing in Apla Software cannot be
+// implemented outside of the Apla Platform environment.
+
+// THE APLA SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY
+// OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED
+// TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE, ERROR FREE AND NONINFRINGEMENT. IN
+// NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR
+// THE USE OR OTHER DEALINGS IN THE APLA SOFTWARE.
+
+package model
+
+// Event is the event which has been emitted by the contract in the transaction
+type Event struct {
+	TxHash    []byte `gorm:"primary_key;not null"`
+	Idx       int64  `gorm:"primary_key;not null"`
+	BlockID   int64  `gorm:"not null"`
+	TxIdx     int64  `gorm:"not null"`
+	Ecosystem int64  `gorm:"not null"`
+	Contract  string `gorm:"not null"`
+	Name      string `gorm:"not null"`
+	Data      string `gorm:"type:jsonb;not null"`
+	Time      int64  `gorm:"not null"`
+}
+
+// EventFilter contains the conditions of the event query
+type EventFilter struct {
+	Ecosystem int64
+	Contract  string
+	Name      string
+	FromBlock int64
+	ToBlock   int64
+}
+
+// TableName returns name of table
+func (Event) TableName() string {
+	return `events`
+}
+
+// Create is creating record of model
+func (e *Event) Create(transaction *DbTransaction) error {
+	return GetDB(transaction).Create(e).Error
+}
+
+// DeleteEventsByHash deletes the events of the transaction
+func DeleteEventsByHash(transaction *DbTransaction, hash []byte) (int64, error) {
+	query := GetDB(transaction).Where("tx_hash = ?", hash).Delete(&Event{})
+	return query.RowsAffected, query.Error
+}
+
+// GetEvents returns the events which match the filter in the order of emitting
+func GetEvents(filter *EventFilter, limit, offset int64) ([]Event, int64, error) {
+	var (
+		list  []Event
+		count int64
+	)
+	query := DBConn.Model(&Event{}).Where("ecosystem = ?", filter.Ecosystem)
+	if len(filter.Contract) > 0 {
+		query = query.Where("contract = ?", filter.Contract)
+	}
+	if len(filter.Name) > 0 {
+		query = query.Where("name = ?", filter.Name)
+	}
+	if filter.FromBlock > 0 {
+		query = query.Where("block_id >= ?", filter.FromBlock)
+	}
+	if filter.ToBlock > 0 {
+		query = query.Where("block_id <= ?", filter.ToBlock)
+	}
+	if err := query.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+	err := query.Order("block_id, tx_idx, tx_hash, idx").Limit(limit).Offset(offset).Find(&list).Error
+	return list, count, err
+}
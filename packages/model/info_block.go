@@ -34,15 +34,16 @@ import (
 
 // InfoBlock is model
 type InfoBlock struct {
-	Hash           []byte `gorm:"not null"`
-	EcosystemID    int64  `gorm:"not null default 0"`
-	KeyID          int64  `gorm:"not null default 0"`
-	NodePosition   string `gorm:"not null default 0"`
-	BlockID        int64  `gorm:"not null"`
-	Time           int64  `gorm:"not null"`
-	CurrentVersion string `gorm:"not null"`
-	Sent           int8   `gorm:"not null"`
-	RollbacksHash  []byte `gorm:"not null"`
+	Hash            []byte `gorm:"not null"`
+	EcosystemID     int64  `gorm:"not null default 0"`
+	KeyID           int64  `gorm:"not null default 0"`
+	NodePosition    string `gorm:"not null default 0"`
+	BlockID         int64  `gorm:"not null"`
+	Time            int64  `gorm:"not null"`
+	CurrentVersion  string `gorm:"not null"`
+	Sent            int8   `gorm:"not null"`
+	RollbacksHash   []byte `gorm:"not null"`
+	SnapshotBlockID int64  `gorm:"not null"`
 }
 
 // TableName returns name of table
@@ -80,6 +81,23 @@ func UpdRollbackHash(transaction *DbTransaction, hash []byte) error {
 	return GetDB(transaction).Model(&InfoBlock{}).Update("rollbacks_hash", hash).Error
 }
 
+// SetSnapshotBlockID marks the state as restored from the snapshot at the block
+func SetSnapshotBlockID(transaction *DbTransaction, blockID int64) error {
+	return GetDB(transaction).Model(&InfoBlock{}).Update("snapshot_block_id", blockID).Error
+}
+
+// GetSnapshotBlockID returns the block which the state has been restored from the snapshot at.
+// The blocks before it are missing, so the blocks up to it can't be rolled back.
+// It returns 0 if the state hasn't been restored from the snapshot.
+func GetSnapshotBlockID() (int64, error) {
+	ib := &InfoBlock{}
+	found, err := ib.Get()
+	if err != nil || !found {
+		return 0, err
+	}
+	return ib.SnapshotBlockID, nil
+}
+
 // BlockGetUnsent returns InfoBlock
 func BlockGetUnsent() (*InfoBlock, error) {
 	ib := &InfoBlock{}
@@ -39,6 +39,8 @@ type Key struct {
 	Maxpay    string `gorm:"not null"`
 	Deleted   int64  `gorm:"not null"`
 	Blocked   int64  `gorm:"not null"`
+	Multi     int64  `gorm:"not null"`
+	KeyType   int64  `gorm:"column:key_type;not null"`
 }
 
 // SetTablePrefix is setting table prefix
@@ -30,8 +30,11 @@ package model
 
 // LogTransaction is model
 type LogTransaction struct {
-	Hash  []byte `gorm:"primary_key;not null"`
-	Block int64  `gorm:"not null"`
+	Hash       []byte `gorm:"primary_key;not null"`
+	Block      int64  `gorm:"not null"`
+	KeyID      int64  `gorm:"not null"`
+	TxTime     int64  `gorm:"not null"`
+	ContractID int64  `gorm:"not null"`
 }
 
 // GetByHash returns LogTransactions existence by hash
@@ -39,6 +42,12 @@ func (lt *LogTransaction) GetByHash(hash []byte) (bool, error) {
 	return isFound(DBConn.Where("hash = ?", hash).First(lt))
 }
 
+// IsUsedSlot returns true if the blockchain contains the transaction of the sender with the same time and contract
+func IsUsedSlot(transaction *DbTransaction, keyID, txTime, contractID int64) (bool, error) {
+	return isFound(GetDB(transaction).Where("key_id = ? AND tx_time = ? AND contract_id = ?",
+		keyID, txTime, contractID).First(&LogTransaction{}))
+}
+
 // Create is creating record of model
 func (lt *LogTransaction) Create(transaction *DbTransaction) error {
 	return GetDB(transaction).Create(lt).Error
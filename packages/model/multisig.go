@@ -0,0 +1,125 @@
+// Apla Software includes an integrated development
+// environment with a multi-level system for the management
+// of access rights to data, interfaces, and Smart contracts. The
+// technical characteristics of the Apla Software are indicated in
+// Apla Technical Paper.
+
+// Apla Users are granted a permission to deal in the Apla
+// Software without restrictions, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of Apla Software, and to permit persons
+// to whom Apla Software is furnished to do so, subject to the
+// following conditions:
+// * the copyright notice of GenesisKernel and EGAAS S.A.
+// and this permission notice shall be included in all copies or
+// substantial portions of the software;
+// * a result of the dealing in Apla Software cannot be
+// implemented outside of the Apla Platform environment.
+
+// THE APLA SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY
+// OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED
+// TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE, ERROR FREE AND NONINFRINGEMENT. IN
+// NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR
+// THE USE OR OTHER DEALINGS IN THE APLA SOFTWARE.
+
+package model
+
+// MultisigSigner is the public key of the multi-signature account
+type MultisigSigner struct {
+	ID        int64  `gorm:"primary_key;not null"`
+	AccountID int64  `gorm:"not null"`
+	KeyID     int64  `gorm:"not null"`
+	PublicKey []byte `gorm:"column:pub;not null"`
+	KeyType   int64  `gorm:"not null"`
+	Ecosystem int64  `gorm:"not null"`
+}
+
+// TableName returns name of table
+func (MultisigSigner) TableName() string {
+	return `1_multisig_signers`
+}
+
+// GetMultisigSigners returns the public keys of the account
+func GetMultisigSigners(transaction *DbTransaction, ecosystem, accountID int64) ([]MultisigSigner, error) {
+	var signers []MultisigSigner
+	err := GetDB(transaction).Where("ecosystem = ? AND account_id = ?", ecosystem, accountID).
+		Order("id").Find(&signers).Error
+	return signers, err
+}
+
+// GetMultisigAccounts returns the accounts which have the key as the signer
+func GetMultisigAccounts(ecosystem, keyID int64) ([]int64, error) {
+	var accounts []int64
+	err := DBConn.Model(&MultisigSigner{}).Where("ecosystem = ? AND key_id = ?", ecosystem, keyID).
+		Pluck("account_id", &accounts).Error
+	return accounts, err
+}
+
+// MultisigPending is the transaction of the multi-signature account which is waiting for signatures
+type MultisigPending struct {
+	Hash       []byte `gorm:"primary_key;not null"`
+	Data       []byte `gorm:"not null"`
+	KeyID      int64  `gorm:"not null"`
+	Ecosystem  int64  `gorm:"not null"`
+	Threshold  int64  `gorm:"not null"`
+	Signatures []byte `gorm:"not null"`
+	Signers    string `gorm:"not null"` // key ids of the signers separated by commas in the order of signatures
+	Time       int64  `gorm:"not null"`
+}
+
+// TableName returns name of table
+func (MultisigPending) TableName() string {
+	return `multisig_pending`
+}
+
+// Get is retrieving model from database
+func (mp *MultisigPending) Get(hash []byte) (bool, error) {
+	return isFound(DBConn.Where("hash = ?", hash).First(mp))
+}
+
+// GetForUpdate is retrieving model from database and locking the row until the end of the transaction
+func (mp *MultisigPending) GetForUpdate(transaction *DbTransaction, hash []byte) (bool, error) {
+	return isFound(GetDB(transaction).Set("gorm:query_option", "FOR UPDATE").Where("hash = ?", hash).First(mp))
+}
+
+// Create is creating record of model, it fails if the transaction is already pending
+func (mp *MultisigPending) Create(transaction *DbTransaction) error {
+	return GetDB(transaction).Create(mp).Error
+}
+
+// Save is saving model
+func (mp *MultisigPending) Save(transaction *DbTransaction) error {
+	return GetDB(transaction).Save(mp).Error
+}
+
+// Delete is deleting model
+func (mp *MultisigPending) Delete(transaction *DbTransaction) error {
+	return GetDB(transaction).Where("hash = ?", mp.Hash).Delete(&MultisigPending{}).Error
+}
+
+// GetMultisigPending returns the pending transactions of the accounts
+func GetMultisigPending(ecosystem int64, accounts []int64, limit, offset int64) ([]MultisigPending, int64, error) {
+	var (
+		list  []MultisigPending
+		count int64
+	)
+	if len(accounts) == 0 {
+		return list, 0, nil
+	}
+	query := DBConn.Model(&MultisigPending{}).Where("ecosystem = ? AND key_id IN (?)", ecosystem, accounts)
+	if err := query.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+	err := query.Order("time").Limit(limit).Offset(offset).Find(&list).Error
+	return list, count, err
+}
+
+// DeleteExpiredMultisigPending deletes the pending transactions which have been created before the time
+func DeleteExpiredMultisigPending(transaction *DbTransaction, before int64) (int64, error) {
+	query := GetDB(transaction).Where("time < ?", before).Delete(&MultisigPending{})
+	return query.RowsAffected, query.Error
+}
@@ -52,8 +52,15 @@ type Transaction struct {
 	Sent     int8            `gorm:"not null"`
 	Attempt  int8            `gorm:"not null"`
 	Verified int8            `gorm:"not null;default:1"`
+	// Fee is the fuel which is offered by the sender, transactions are taken into the block by it
+	Fee        int64 `gorm:"not null"`
+	TxTime     int64 `gorm:"not null"`
+	ContractID int64 `gorm:"not null"`
 }
 
+// txPoolOrder is the order of the pending transactions in the pool
+const txPoolOrder = "high_rate DESC, fee DESC, tx_time ASC"
+
 // GetAllTransactions is retrieving all transactions with limit
 func GetAllTransactions(limit int) (*[]Transaction, error) {
 	transactions := new([]Transaction)
@@ -67,7 +74,7 @@ func GetAllTransactions(limit int) (*[]Transaction, error) {
 func GetAllUnusedTransactions(limit int) ([]*Transaction, error) {
 	var transactions []*Transaction
 
-	query := DBConn.Where("used = ?", "0").Order("high_rate DESC")
+	query := DBConn.Where("used = ?", "0").Order(txPoolOrder)
 	if limit > 0 {
 		query = query.Limit(limit)
 	}
@@ -78,6 +85,34 @@ func GetAllUnusedTransactions(limit int) ([]*Transaction, error) {
 	return transactions, nil
 }
 
+// GetPendingTransactions returns the verified and unused transactions in the order of the pool
+func GetPendingTransactions(keyID, limit, offset int64) ([]Transaction, int64, error) {
+	var (
+		transactions []Transaction
+		count        int64
+	)
+
+	query := DBConn.Table("transactions").Where("used = 0 AND verified = 1")
+	if keyID != 0 {
+		query = query.Where("key_id = ?", keyID)
+	}
+	if err := query.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := query.Order(txPoolOrder).Offset(offset).Limit(limit).Find(&transactions).Error; err != nil {
+		return nil, 0, err
+	}
+	return transactions, count, nil
+}
+
+// GetExpiredTransactions returns hashes of the unused transactions which were created before the time
+func GetExpiredTransactions(transaction *DbTransaction, before int64) ([][]byte, error) {
+	var hashes [][]byte
+	err := GetDB(transaction).Table("transactions").
+		Where("used = 0 AND tx_time > 0 AND tx_time < ?", before).Pluck("hash", &hashes).Error
+	return hashes, err
+}
+
 // GetAllUnsentTransactions is retrieving all unset transactions
 func GetAllUnsentTransactions() (*[]Transaction, error) {
 	transactions := new([]Transaction)
@@ -163,6 +198,13 @@ func (t *Transaction) Get(transactionHash []byte) (bool, error) {
 	return isFound(DBConn.Where("hash = ?", transactionHash).First(t))
 }
 
+// GetPending is retrieving the pending transaction which has the same sender, time and contract.
+// Transactions of this slot can replace each other
+func (t *Transaction) GetPending(transaction *DbTransaction, keyID, txTime, contractID int64) (bool, error) {
+	return isFound(GetDB(transaction).Where("key_id = ? AND tx_time = ? AND contract_id = ? AND used = 0 AND verified = 1",
+		keyID, txTime, contractID).Order("fee DESC").First(t))
+}
+
 // GetVerified is checking transaction verification by hash
 func (t *Transaction) GetVerified(transactionHash []byte) (bool, error) {
 	return isFound(DBConn.Where("hash = ? AND verified = 1", transactionHash).First(t))
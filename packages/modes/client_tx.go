@@ -34,7 +34,18 @@ func (p blockchainTxPreprocessor) ProcessClientTranstaction(txData []byte, key i
 	}
 
 	if smartTx.Header.KeyID != key {
-		return "", ErrDiffKey
+		wallet, err := transaction.GetMultisigAccount(smartTx.Header.EcosystemID, smartTx.Header.KeyID)
+		if err != nil {
+			return "", err
+		}
+		if wallet == nil {
+			return "", ErrDiffKey
+		}
+		if err = transaction.ProcessMultisig(rtx, wallet, smartTx.Header.EcosystemID, key); err != nil {
+			le.WithFields(log.Fields{"type": consts.InvalidObject, "error": err}).Error("processing multi-signature tx")
+			return "", err
+		}
+		return string(converter.BinToHex(rtx.Hash())), nil
 	}
 
 	if err := model.SendTx(rtx, key); err != nil {
@@ -2,6 +2,7 @@ package modes
 
 import (
 	"context"
+	"path/filepath"
 	"time"
 
 	"github.com/AplaProject/go-apla/packages/api"
@@ -11,6 +12,7 @@ import (
 	"github.com/AplaProject/go-apla/packages/consts"
 	"github.com/AplaProject/go-apla/packages/daemons"
 	"github.com/AplaProject/go-apla/packages/model"
+	"github.com/AplaProject/go-apla/packages/network/peers"
 	"github.com/AplaProject/go-apla/packages/network/tcpserver"
 	"github.com/AplaProject/go-apla/packages/service"
 	"github.com/AplaProject/go-apla/packages/smart"
@@ -141,6 +143,11 @@ func (l BCDaemonLoader) Load(ctx context.Context) error {
 		return err
 	}
 
+	if err := peers.InitTable(filepath.Join(conf.Config.DataDir, consts.DefaultPeersFilename),
+		conf.Config.TCPServer.Str()); err != nil {
+		l.logger.WithError(err).Error("Can't load table of peers")
+	}
+
 	l.logger.Info("start daemons")
 	daemons.StartDaemons(ctx, l.DaemonListFactory.GetDaemonsList())
 
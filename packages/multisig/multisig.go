@@ -0,0 +1,142 @@
+// Apla Software includes an integrated development
+// environment with a multi-level system for the management
+// of access rights to data, interfaces, and Smart contracts. The
+// technical characteristics of the Apla Software are indicated in
+// Apla Technical Paper.
+
+// Apla Users are granted a permission to deal in the Apla
+// Software without restrictions, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of Apla Software, and to permit persons
+// to whom Apla Software is furnished to do so, subject to the
+// following conditions:
+// * the copyright notice of GenesisKernel and EGAAS S.A.
+// and this permission notice shall be included in all copies or
+// substantial portions of the software;
+// * a result of the dealing in Apla Software cannot be
+// implemented outside of the Apla Platform environment.
+
+// THE APLA SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY
+// OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED
+// TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE, ERROR FREE AND NONINFRINGEMENT. IN
+// NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR
+// THE USE OR OTHER DEALINGS IN THE APLA SOFTWARE.
+
+package multisig
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"sort"
+
+	"github.com/AplaProject/go-apla/packages/consts"
+	"github.com/AplaProject/go-apla/packages/converter"
+	"github.com/AplaProject/go-apla/packages/crypto"
+	"github.com/AplaProject/go-apla/packages/model"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// MaxSigners is the max count of public keys of the account
+const MaxSigners = 20
+
+var (
+	// ErrThreshold is returned if the threshold isn't in the range from 1 to the count of keys
+	ErrThreshold = errors.New("Threshold must be from 1 to the count of public keys")
+	// ErrSigners is returned if the count of keys is more than MaxSigners
+	ErrSigners = errors.New("Too many public keys")
+	// ErrDuplicateKey is returned if the public keys of the account are repeated
+	ErrDuplicateKey = errors.New("Duplicate public key")
+	// ErrSignature is returned if the signature doesn't belong to the signers of the account
+	ErrSignature = errors.New("Signature doesn't match any signer of account")
+)
+
+// AccountID returns the key id of the account with the threshold and the public keys, it doesn't depend on the order of keys
+func AccountID(threshold int64, publicKeys [][]byte) int64 {
+	keys := make([][]byte, len(publicKeys))
+	copy(keys, publicKeys)
+	sort.Slice(keys, func(i, j int) bool {
+		return bytes.Compare(keys[i], keys[j]) < 0
+	})
+
+	data := make([]byte, 8)
+	binary.BigEndian.PutUint64(data, uint64(threshold))
+	for _, key := range keys {
+		data = append(data, converter.EncodeLengthPlusData(key)...)
+	}
+	return crypto.Address(data)
+}
+
+// CheckKeys checks the threshold and the public keys of the new account
+func CheckKeys(threshold int64, publicKeys [][]byte) error {
+	if len(publicKeys) > MaxSigners {
+		return ErrSigners
+	}
+	if threshold < 1 || threshold > int64(len(publicKeys)) {
+		return ErrThreshold
+	}
+	keys := make(map[string]bool)
+	for _, key := range publicKeys {
+		if keys[string(key)] {
+			return ErrDuplicateKey
+		}
+		keys[string(key)] = true
+	}
+	return nil
+}
+
+// SplitSignatures returns the signatures of the transaction, each signature is encoded as length and data
+func SplitSignatures(data []byte) ([][]byte, error) {
+	var signs [][]byte
+	for len(data) > 0 {
+		length, err := converter.DecodeLength(&data)
+		if err != nil {
+			log.WithFields(log.Fields{"type": consts.UnmarshallingError, "error": err}).Error("decoding signature length")
+			return nil, err
+		}
+		if length <= 0 || length > int64(len(data)) {
+			log.WithFields(log.Fields{"type": consts.UnmarshallingError, "length": length, "size": len(data)}).Error("wrong signature length")
+			return nil, ErrSignature
+		}
+		signs = append(signs, converter.BytesShift(&data, length))
+	}
+	return signs, nil
+}
+
+// JoinSignatures encodes the signatures for the transaction
+func JoinSignatures(signs [][]byte) []byte {
+	var data []byte
+	for _, sign := range signs {
+		data = append(data, converter.EncodeLengthPlusData(sign)...)
+	}
+	return data
+}
+
+// Verify returns the signers which have signed the hash in the order of signatures.
+// Every signer is counted once, the signature which doesn't belong to the signers is an error.
+func Verify(signers []model.MultisigSigner, hash []byte, signs [][]byte) ([]model.MultisigSigner, error) {
+	used := make([]bool, len(signers))
+	signed := make([]model.MultisigSigner, 0, len(signs))
+	for _, sign := range signs {
+		found := false
+		for i, signer := range signers {
+			if used[i] {
+				continue
+			}
+			if ok, _ := crypto.KeyType(signer.KeyType).CheckSign(signer.PublicKey, hash, sign); ok {
+				used[i], found = true, true
+				signed = append(signed, signer)
+				break
+			}
+		}
+		if !found {
+			return nil, ErrSignature
+		}
+	}
+	return signed, nil
+}
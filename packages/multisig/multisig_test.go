@@ -0,0 +1,99 @@
+// Apla Software includes an integrated development
+// environment with a multi-level system for the management
+// of access rights to data, interfaces, and Smart contracts. The
+// technical characteristics of the Apla Software are indicated in
+// Apla Technical Paper.
+
+// Apla Users are granted a permission to deal in the Apla
+// Software without restrictions, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of Apla Software, and to permit persons
+// to whom Apla Software is furnished to do so, subject to the
+// following conditions:
+// * the copyright notice of GenesisKernel and EGAAS S.A.
+// and this permission notice shall be included in all copies or
+// substantial portions of the software;
+// * a result of the dealing in Apla Software cannot be
+// implemented outside of the Apla Platform environment.
+
+// THE APLA SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY
+// OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED
+// TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE, ERROR FREE AND NONINFRINGEMENT. IN
+// NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR
+// THE USE OR OTHER DEALINGS IN THE APLA SOFTWARE.
+
+package multisig
+
+import (
+	"testing"
+
+	"github.com/AplaProject/go-apla/packages/crypto"
+	"github.com/AplaProject/go-apla/packages/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccountID(t *testing.T) {
+	keys := [][]byte{[]byte("key1"), []byte("key2"), []byte("key3")}
+	id := AccountID(2, keys)
+	assert.Equal(t, id, AccountID(2, [][]byte{keys[2], keys[0], keys[1]}))
+	assert.NotEqual(t, id, AccountID(3, keys))
+	assert.NotEqual(t, id, AccountID(2, keys[:2]))
+
+	assert.NoError(t, CheckKeys(2, keys))
+	assert.Equal(t, ErrThreshold, CheckKeys(0, keys))
+	assert.Equal(t, ErrThreshold, CheckKeys(4, keys))
+	assert.Equal(t, ErrDuplicateKey, CheckKeys(2, append(keys, keys[0])))
+	assert.Equal(t, ErrSigners, CheckKeys(1, make([][]byte, MaxSigners+1)))
+}
+
+func TestSignatures(t *testing.T) {
+	signs := [][]byte{[]byte("first"), make([]byte, 200), []byte("third")}
+	out, err := SplitSignatures(JoinSignatures(signs))
+	require.NoError(t, err)
+	assert.Equal(t, signs, out)
+
+	_, err = SplitSignatures(JoinSignatures(signs)[:10])
+	assert.Error(t, err)
+}
+
+func TestVerify(t *testing.T) {
+	var (
+		signers []model.MultisigSigner
+		privs   [][]byte
+	)
+	hash := []byte("transaction hash")
+	for i, kt := range []crypto.KeyType{crypto.KeyTypeP256, crypto.KeyTypeEd25519, crypto.KeyTypeSecp256k1} {
+		priv, pub, err := kt.GenKeys()
+		require.NoError(t, err)
+		privs = append(privs, priv)
+		signers = append(signers, model.MultisigSigner{
+			ID:        int64(i + 1),
+			KeyID:     crypto.Address(pub),
+			PublicKey: pub,
+			KeyType:   int64(kt),
+		})
+	}
+	sign := func(i int) []byte {
+		s, err := crypto.KeyType(signers[i].KeyType).Sign(privs[i], hash)
+		require.NoError(t, err)
+		return s
+	}
+
+	signed, err := Verify(signers, hash, [][]byte{sign(2), sign(0)})
+	require.NoError(t, err)
+	require.Len(t, signed, 2)
+	assert.Equal(t, signers[2].KeyID, signed[0].KeyID)
+	assert.Equal(t, signers[0].KeyID, signed[1].KeyID)
+
+	_, err = Verify(signers, hash, [][]byte{sign(1), sign(1)})
+	assert.Equal(t, ErrSignature, err)
+
+	_, err = Verify(signers[:2], hash, [][]byte{sign(2)})
+	assert.Equal(t, ErrSignature, err)
+}
@@ -0,0 +1,83 @@
+// Apla Software includes an integrated development
+// environment with a multi-level system for the management
+// of access rights to data, interfaces, and Smart contracts. The
+// technical characteristics of the Apla Software are indicated in
+// Apla Technical Paper.
+
+// Apla Users are granted a permission to deal in the Apla
+// Software without restrictions, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of Apla Software, and to permit persons
+// to whom Apla Software is furnished to do so, subject to the
+// following conditions:
+// * the copyright notice of GenesisKernel and EGAAS S.A.
+// and this permission notice shall be included in all copies or
+// substantial portions of the software;
+// * a result of the dealing in Apla Software cannot be
+// implemented outside of the Apla Platform environment.
+
+// THE APLA SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY
+// OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED
+// TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE, ERROR FREE AND NONINFRINGEMENT. IN
+// NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR
+// THE USE OR OTHER DEALINGS IN THE APLA SOFTWARE.
+
+package network
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/AplaProject/go-apla/packages/consts"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// RequestTypeSignedConfirmation requests the hash of the block signed by the node key
+	RequestTypeSignedConfirmation = 14
+
+	maxConfirmationSign = 256
+)
+
+// ConfirmationForSign returns the data which are signed by the node to confirm the block
+func ConfirmationForSign(blockID int64, hash []byte) []byte {
+	return []byte(fmt.Sprintf("confirm,%d,%x", blockID, hash))
+}
+
+// SignedConfirmResponse contains the hash of the block and the signature of the node
+type SignedConfirmResponse struct {
+	Hash  []byte
+	KeyID int64
+	Sign  []byte
+}
+
+func (resp *SignedConfirmResponse) Read(r io.Reader) (err error) {
+	if resp.Hash, err = ReadSliceWithMaxSize(r, consts.HashSize); err != nil {
+		log.WithFields(log.Fields{"type": consts.IOError, "error": err}).Error("on reading signed confirmation hash")
+		return err
+	}
+	if err = binary.Read(r, binary.LittleEndian, &resp.KeyID); err != nil {
+		log.WithFields(log.Fields{"type": consts.IOError, "error": err}).Error("on reading signed confirmation key id")
+		return err
+	}
+	resp.Sign, err = ReadSliceWithMaxSize(r, maxConfirmationSign)
+	return err
+}
+
+func (resp *SignedConfirmResponse) Write(w io.Writer) error {
+	if err := writeSlice(w, resp.Hash); err != nil {
+		log.WithFields(log.Fields{"type": consts.IOError, "error": err}).Error("on sending signed confirmation hash")
+		return err
+	}
+	if err := binary.Write(w, binary.LittleEndian, resp.KeyID); err != nil {
+		log.WithFields(log.Fields{"type": consts.IOError, "error": err}).Error("on sending signed confirmation key id")
+		return err
+	}
+	return writeSlice(w, resp.Sign)
+}
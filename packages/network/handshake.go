@@ -0,0 +1,234 @@
+// Apla Software includes an integrated development
+// environment with a multi-level system for the management
+// of access rights to data, interfaces, and Smart contracts. The
+// technical characteristics of the Apla Software are indicated in
+// Apla Technical Paper.
+
+// Apla Users are granted a permission to deal in the Apla
+// Software without restrictions, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of Apla Software, and to permit persons
+// to whom Apla Software is furnished to do so, subject to the
+// following conditions:
+// * the copyright notice of GenesisKernel and EGAAS S.A.
+// and this permission notice shall be included in all copies or
+// substantial portions of the software;
+// * a result of the dealing in Apla Software cannot be
+// implemented outside of the Apla Platform environment.
+
+// THE APLA SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY
+// OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED
+// TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE, ERROR FREE AND NONINFRINGEMENT. IN
+// NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR
+// THE USE OR OTHER DEALINGS IN THE APLA SOFTWARE.
+
+package network
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/AplaProject/go-apla/packages/consts"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// RequestTypeHandshake is the request which negotiates the protocol version.
+	// It is sent as the first request of the connection.
+	RequestTypeHandshake = 11
+
+	// LegacyProtocolVersion is the version of nodes which don't send the handshake
+	LegacyProtocolVersion uint16 = 1
+	// ProtocolVersion is the current version of the node protocol
+	ProtocolVersion uint16 = 5
+	// MinProtocolVersion is the lowest version of the protocol which is accepted from peers
+	MinProtocolVersion = LegacyProtocolVersion
+
+	maxHandshakeTypes  = 256
+	maxHandshakeReason = 1024
+)
+
+// ErrHandshakeRefused is returned when the remote node refuses the handshake
+var ErrHandshakeRefused = errors.New("Handshake refused")
+
+// requestTypes contains the request types with the protocol version they have been introduced in
+var requestTypes = []struct {
+	Type    uint16
+	Version uint16
+}{
+	{RequestTypeFullNode, LegacyProtocolVersion},
+	{RequestTypeNotFullNode, LegacyProtocolVersion},
+	{RequestTypeStopNetwork, LegacyProtocolVersion},
+	{RequestTypeConfirmation, LegacyProtocolVersion},
+	{RequestTypeBlockCollection, LegacyProtocolVersion},
+	{RequestTypeMaxBlock, LegacyProtocolVersion},
+	{RequestTypeSecure, 3},
+	{RequestTypePeers, 4},
+	{RequestTypeSignedConfirmation, 5},
+}
+
+// SupportedRequestTypes returns the request types which are available in the protocol version
+func SupportedRequestTypes(version uint16) []uint16 {
+	types := make([]uint16, 0, len(requestTypes))
+	for _, item := range requestTypes {
+		if item.Version <= version {
+			types = append(types, item.Type)
+		}
+	}
+	return types
+}
+
+// HandshakeRequest contains the protocol version and the identity of the node which connects
+type HandshakeRequest struct {
+	Version   uint16
+	KeyID     int64
+	NetworkID int64
+	Types     []uint16
+}
+
+func (req *HandshakeRequest) Read(r io.Reader) error {
+	for _, v := range []interface{}{&req.Version, &req.KeyID, &req.NetworkID} {
+		if err := binary.Read(r, binary.LittleEndian, v); err != nil {
+			log.WithFields(log.Fields{"type": consts.IOError, "error": err}).Error("on reading handshake request")
+			return err
+		}
+	}
+	types, err := readTypes(r)
+	if err != nil {
+		return err
+	}
+	req.Types = types
+	return nil
+}
+
+func (req *HandshakeRequest) Write(w io.Writer) error {
+	for _, v := range []interface{}{req.Version, req.KeyID, req.NetworkID} {
+		if err := binary.Write(w, binary.LittleEndian, v); err != nil {
+			log.WithFields(log.Fields{"type": consts.IOError, "error": err}).Error("on sending handshake request")
+			return err
+		}
+	}
+	return writeTypes(w, req.Types)
+}
+
+// HandshakeResponse contains the negotiated version and request types.
+// If the handshake is refused then Reason contains the description.
+type HandshakeResponse struct {
+	Accepted bool
+	Version  uint16
+	KeyID    int64
+	Types    []uint16
+	Reason   string
+}
+
+func (resp *HandshakeResponse) Read(r io.Reader) error {
+	accepted, err := readBool(r)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.IOError, "error": err}).Debug("on reading handshake response")
+		return err
+	}
+	resp.Accepted = accepted
+	for _, v := range []interface{}{&resp.Version, &resp.KeyID} {
+		if err := binary.Read(r, binary.LittleEndian, v); err != nil {
+			log.WithFields(log.Fields{"type": consts.IOError, "error": err}).Error("on reading handshake response")
+			return err
+		}
+	}
+	if resp.Types, err = readTypes(r); err != nil {
+		return err
+	}
+	reason, err := ReadSliceWithMaxSize(r, maxHandshakeReason)
+	if err != nil {
+		return err
+	}
+	resp.Reason = string(reason)
+	return nil
+}
+
+func (resp *HandshakeResponse) Write(w io.Writer) error {
+	if err := writeBool(w, resp.Accepted); err != nil {
+		log.WithFields(log.Fields{"type": consts.IOError, "error": err}).Error("on sending handshake response")
+		return err
+	}
+	for _, v := range []interface{}{resp.Version, resp.KeyID} {
+		if err := binary.Write(w, binary.LittleEndian, v); err != nil {
+			log.WithFields(log.Fields{"type": consts.IOError, "error": err}).Error("on sending handshake response")
+			return err
+		}
+	}
+	if err := writeTypes(w, resp.Types); err != nil {
+		return err
+	}
+	return writeSlice(w, []byte(resp.Reason))
+}
+
+// Supports returns true if the request type has been negotiated
+func (resp *HandshakeResponse) Supports(reqType uint16) bool {
+	for _, item := range resp.Types {
+		if item == reqType {
+			return true
+		}
+	}
+	return false
+}
+
+// Negotiate checks the handshake request of the peer against the node with keyID and networkID.
+// The version of the response is the lowest of the versions of both nodes and the types are
+// the request types which are supported by both nodes at this version.
+func Negotiate(req *HandshakeRequest, keyID, networkID int64) *HandshakeResponse {
+	resp := &HandshakeResponse{KeyID: keyID, Version: ProtocolVersion}
+	if req.NetworkID != networkID {
+		resp.Reason = fmt.Sprintf("network id %d doesn't match %d", req.NetworkID, networkID)
+		return resp
+	}
+	if req.Version < MinProtocolVersion {
+		resp.Reason = fmt.Sprintf("protocol version %d is lower than %d", req.Version, MinProtocolVersion)
+		return resp
+	}
+	if req.Version < resp.Version {
+		resp.Version = req.Version
+	}
+	resp.Types = make([]uint16, 0)
+	for _, item := range SupportedRequestTypes(resp.Version) {
+		for _, reqType := range req.Types {
+			if item == reqType {
+				resp.Types = append(resp.Types, item)
+				break
+			}
+		}
+	}
+	resp.Accepted = true
+	return resp
+}
+
+func readTypes(r io.Reader) ([]uint16, error) {
+	var count uint16
+	if err := binary.Read(r, binary.LittleEndian, &count); err != nil {
+		log.WithFields(log.Fields{"type": consts.IOError, "error": err}).Error("on reading count of request types")
+		return nil, err
+	}
+	if count > maxHandshakeTypes {
+		return nil, ErrMaxSize
+	}
+	types := make([]uint16, count)
+	if err := binary.Read(r, binary.LittleEndian, types); err != nil {
+		log.WithFields(log.Fields{"type": consts.IOError, "error": err}).Error("on reading request types")
+		return nil, err
+	}
+	return types, nil
+}
+
+func writeTypes(w io.Writer, types []uint16) error {
+	if err := binary.Write(w, binary.LittleEndian, uint16(len(types))); err != nil {
+		log.WithFields(log.Fields{"type": consts.IOError, "error": err}).Error("on sending count of request types")
+		return err
+	}
+	return binary.Write(w, binary.LittleEndian, types)
+}
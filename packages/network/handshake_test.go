@@ -0,0 +1,86 @@
+// Apla Software includes an integrated development
+// environment with a multi-level system for the management
+// of access rights to data, interfaces, and Smart contracts. The
+// technical characteristics of the Apla Software are indicated in
+// Apla Technical Paper.
+
+// Apla Users are granted a permission to deal in the Apla
+// Software without restrictions, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of Apla Software, and to permit persons
+// to whom Apla Software is furnished to do so, subject to the
+// following conditions:
+// * the copyright notice of GenesisKernel and EGAAS S.A.
+// and this permission notice shall be included in all copies or
+// substantial portions of the software;
+// * a result of the dealing in Apla Software cannot be
+// implemented outside of the Apla Platform environment.
+
+// THE APLA SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY
+// OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED
+// TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE, ERROR FREE AND NONINFRINGEMENT. IN
+// NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR
+// THE USE OR OTHER DEALINGS IN THE APLA SOFTWARE.
+
+package network
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandshakeRequest(t *testing.T) {
+	req := &HandshakeRequest{Version: ProtocolVersion, KeyID: -1234, NetworkID: 5,
+		Types: SupportedRequestTypes(ProtocolVersion)}
+	b := &bytes.Buffer{}
+	require.NoError(t, req.Write(b))
+
+	result := &HandshakeRequest{}
+	require.NoError(t, result.Read(b))
+	assert.Equal(t, req, result)
+}
+
+func TestHandshakeResponse(t *testing.T) {
+	for _, resp := range []*HandshakeResponse{
+		{Accepted: true, Version: 2, KeyID: 10, Types: []uint16{1, 7}},
+		{Version: 2, KeyID: 10, Types: []uint16{}, Reason: "refused"},
+	} {
+		b := &bytes.Buffer{}
+		require.NoError(t, resp.Write(b))
+
+		result := &HandshakeResponse{}
+		require.NoError(t, result.Read(b))
+		assert.Equal(t, resp, result)
+	}
+}
+
+func TestNegotiate(t *testing.T) {
+	req := &HandshakeRequest{Version: ProtocolVersion + 1, NetworkID: 1,
+		Types: []uint16{RequestTypeMaxBlock, RequestTypeBlockCollection, 1000}}
+	resp := Negotiate(req, 10, 1)
+	assert.True(t, resp.Accepted)
+	assert.Equal(t, ProtocolVersion, resp.Version)
+	assert.Equal(t, int64(10), resp.KeyID)
+	assert.Equal(t, []uint16{RequestTypeBlockCollection, RequestTypeMaxBlock}, resp.Types)
+	assert.True(t, resp.Supports(RequestTypeMaxBlock))
+	assert.False(t, resp.Supports(RequestTypeConfirmation))
+
+	req.Version = LegacyProtocolVersion
+	resp = Negotiate(req, 10, 1)
+	assert.True(t, resp.Accepted)
+	assert.Equal(t, LegacyProtocolVersion, resp.Version)
+
+	resp = Negotiate(req, 10, 2)
+	assert.False(t, resp.Accepted)
+	assert.NotEmpty(t, resp.Reason)
+
+	req.Version = 0
+	assert.False(t, Negotiate(req, 10, 1).Accepted)
+}
@@ -0,0 +1,219 @@
+// Apla Software includes an integrated development
+// environment with a multi-level system for the management
+// of access rights to data, interfaces, and Smart contracts. The
+// technical characteristics of the Apla Software are indicated in
+// Apla Technical Paper.
+
+// Apla Users are granted a permission to deal in the Apla
+// Software without restrictions, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of Apla Software, and to permit persons
+// to whom Apla Software is furnished to do so, subject to the
+// following conditions:
+// * the copyright notice of GenesisKernel and EGAAS S.A.
+// and this permission notice shall be included in all copies or
+// substantial portions of the software;
+// * a result of the dealing in Apla Software cannot be
+// implemented outside of the Apla Platform environment.
+
+// THE APLA SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY
+// OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED
+// TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE, ERROR FREE AND NONINFRINGEMENT. IN
+// NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR
+// THE USE OR OTHER DEALINGS IN THE APLA SOFTWARE.
+
+package limiter
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+const (
+	pruneInterval = time.Minute
+	idleTimeout   = 10 * time.Minute
+)
+
+var (
+	// ErrBanned is returned if the peer is banned
+	ErrBanned = errors.New("Peer is banned")
+	// ErrConnections is returned if the peer exceeds the number of connections
+	ErrConnections = errors.New("Too many connections")
+	// ErrRate is returned if the peer exceeds the rate of requests
+	ErrRate = errors.New("Request rate is exceeded")
+)
+
+// Config contains the limits for one peer. Zero value of the limit disables it
+type Config struct {
+	MaxConnections int     // concurrent connections
+	RequestRate    float64 // requests per second
+	RequestBurst   int     // requests which can be done at once
+	MaxFails       int     // malformed requests before the ban, MaxFails requests are forgiven during BanTime
+	BanTime        time.Duration
+}
+
+type peer struct {
+	conns      int
+	tokens     float64
+	last       time.Time
+	fails      float64
+	lastFail   time.Time
+	bannedTill time.Time
+}
+
+// Limiter limits connections and requests of the peers, peers are identified by keys
+// such as IP address or key of the node
+type Limiter struct {
+	mu        sync.Mutex
+	cfg       Config
+	peers     map[string]*peer
+	lastPrune time.Time
+	now       func() time.Time
+}
+
+// New returns new limiter
+func New(cfg Config) *Limiter {
+	if cfg.RequestBurst < 1 {
+		cfg.RequestBurst = 1
+	}
+	return &Limiter{
+		cfg:   cfg,
+		peers: make(map[string]*peer),
+		now:   time.Now,
+	}
+}
+
+func (l *Limiter) get(key string, now time.Time) *peer {
+	p, ok := l.peers[key]
+	if !ok {
+		p = &peer{tokens: float64(l.cfg.RequestBurst), last: now}
+		l.peers[key] = p
+	}
+	return p
+}
+
+func (l *Limiter) banned(p *peer, now time.Time) bool {
+	if p.bannedTill.IsZero() {
+		return false
+	}
+	if now.Before(p.bannedTill) {
+		return true
+	}
+	p.bannedTill = time.Time{}
+	p.fails = 0
+	return false
+}
+
+// prune removes the idle peers
+func (l *Limiter) prune(now time.Time) {
+	if now.Sub(l.lastPrune) < pruneInterval {
+		return
+	}
+	l.lastPrune = now
+	for key, p := range l.peers {
+		if p.conns == 0 && !l.banned(p, now) && now.Sub(p.last) > idleTimeout {
+			delete(l.peers, key)
+		}
+	}
+}
+
+// Connect registers new connection of the peer
+func (l *Limiter) Connect(key string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.prune(now)
+
+	p := l.get(key, now)
+	if l.banned(p, now) {
+		return ErrBanned
+	}
+	if l.cfg.MaxConnections > 0 && p.conns >= l.cfg.MaxConnections {
+		return ErrConnections
+	}
+	p.conns++
+	return nil
+}
+
+// Disconnect releases the connection of the peer
+func (l *Limiter) Disconnect(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if p, ok := l.peers[key]; ok && p.conns > 0 {
+		p.conns--
+	}
+}
+
+// Request checks that the peer can make one more request
+func (l *Limiter) Request(key string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	p := l.get(key, now)
+	if l.banned(p, now) {
+		return ErrBanned
+	}
+	if l.cfg.RequestRate <= 0 {
+		p.last = now
+		return nil
+	}
+
+	p.tokens += now.Sub(p.last).Seconds() * l.cfg.RequestRate
+	if burst := float64(l.cfg.RequestBurst); p.tokens > burst {
+		p.tokens = burst
+	}
+	p.last = now
+	if p.tokens < 1 {
+		return ErrRate
+	}
+	p.tokens--
+	return nil
+}
+
+// Fail registers the malformed request of the peer. It returns true if the peer has been banned.
+// The counter of the malformed requests decreases over time, so the peer is banned only
+// if it sends them faster than MaxFails per BanTime.
+func (l *Limiter) Fail(key string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.cfg.MaxFails <= 0 {
+		return false
+	}
+
+	now := l.now()
+	p := l.get(key, now)
+	if l.banned(p, now) {
+		return false
+	}
+	if l.cfg.BanTime > 0 {
+		p.fails -= now.Sub(p.lastFail).Seconds() * float64(l.cfg.MaxFails) / l.cfg.BanTime.Seconds()
+	}
+	if p.fails < 0 || l.cfg.BanTime <= 0 {
+		p.fails = 0
+	}
+	p.fails++
+	p.lastFail = now
+	if p.fails < float64(l.cfg.MaxFails) {
+		return false
+	}
+	p.bannedTill = now.Add(l.cfg.BanTime)
+	return true
+}
+
+// IsBanned returns true if the peer is banned
+func (l *Limiter) IsBanned(key string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	p, ok := l.peers[key]
+	return ok && l.banned(p, l.now())
+}
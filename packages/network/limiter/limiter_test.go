@@ -0,0 +1,126 @@
+// Apla Software includes an integrated development
+// environment with a multi-level system for the management
+// of access rights to data, interfaces, and Smart contracts. The
+// technical characteristics of the Apla Software are indicated in
+// Apla Technical Paper.
+
+// Apla Users are granted a permission to deal in the Apla
+// Software without restrictions, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of Apla Software, and to permit persons
+// to whom Apla Software is furnished to do so, subject to the
+// following conditions:
+// * the copyright notice of GenesisKernel and EGAAS S.A.
+// and this permission notice shall be included in all copies or
+// substantial portions of the software;
+// * a result of the dealing in Apla Software cannot be
+// implemented outside of the Apla Platform environment.
+
+// THE APLA SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY
+// OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED
+// TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE, ERROR FREE AND NONINFRINGEMENT. IN
+// NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR
+// THE USE OR OTHER DEALINGS IN THE APLA SOFTWARE.
+
+package limiter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type clock struct {
+	t time.Time
+}
+
+func (c *clock) now() time.Time {
+	return c.t
+}
+
+func newTestLimiter(cfg Config) (*Limiter, *clock) {
+	c := &clock{t: time.Unix(1500000000, 0)}
+	l := New(cfg)
+	l.now = c.now
+	return l, c
+}
+
+func TestConnections(t *testing.T) {
+	l, _ := newTestLimiter(Config{MaxConnections: 2})
+
+	assert.NoError(t, l.Connect("a"))
+	assert.NoError(t, l.Connect("a"))
+	assert.Equal(t, ErrConnections, l.Connect("a"))
+	assert.NoError(t, l.Connect("b"))
+
+	l.Disconnect("a")
+	assert.NoError(t, l.Connect("a"))
+}
+
+func TestRequestRate(t *testing.T) {
+	l, c := newTestLimiter(Config{RequestRate: 2, RequestBurst: 3})
+
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, l.Request("a"))
+	}
+	assert.Equal(t, ErrRate, l.Request("a"))
+	assert.NoError(t, l.Request("b"))
+
+	c.t = c.t.Add(500 * time.Millisecond)
+	assert.NoError(t, l.Request("a"))
+	assert.Equal(t, ErrRate, l.Request("a"))
+
+	c.t = c.t.Add(time.Hour)
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, l.Request("a"))
+	}
+	assert.Equal(t, ErrRate, l.Request("a"))
+}
+
+func TestBan(t *testing.T) {
+	l, c := newTestLimiter(Config{MaxFails: 2, BanTime: time.Minute})
+
+	assert.False(t, l.Fail("a"))
+	c.t = c.t.Add(2 * time.Minute)
+	assert.False(t, l.Fail("a"))
+	assert.True(t, l.Fail("a"))
+
+	assert.True(t, l.IsBanned("a"))
+	assert.Equal(t, ErrBanned, l.Connect("a"))
+	assert.Equal(t, ErrBanned, l.Request("a"))
+	assert.False(t, l.IsBanned("b"))
+
+	c.t = c.t.Add(time.Minute)
+	assert.False(t, l.IsBanned("a"))
+	assert.NoError(t, l.Connect("a"))
+	assert.False(t, l.Fail("a"))
+}
+
+func TestFailDecay(t *testing.T) {
+	l, c := newTestLimiter(Config{MaxFails: 3, BanTime: time.Minute})
+
+	for i := 0; i < 10; i++ {
+		assert.False(t, l.Fail("a"))
+		c.t = c.t.Add(20 * time.Second)
+	}
+	assert.False(t, l.Fail("a"))
+	c.t = c.t.Add(10 * time.Second)
+	assert.False(t, l.Fail("a"))
+	assert.False(t, l.Fail("a"))
+	assert.True(t, l.Fail("a"))
+}
+
+func TestDisabled(t *testing.T) {
+	l, _ := newTestLimiter(Config{})
+
+	for i := 0; i < 100; i++ {
+		assert.NoError(t, l.Connect("a"))
+		assert.NoError(t, l.Request("a"))
+		assert.False(t, l.Fail("a"))
+	}
+}
@@ -0,0 +1,110 @@
+// Apla Software includes an integrated development
+// environment with a multi-level system for the management
+// of access rights to data, interfaces, and Smart contracts. The
+// technical characteristics of the Apla Software are indicated in
+// Apla Technical Paper.
+
+// Apla Users are granted a permission to deal in the Apla
+// Software without restrictions, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of Apla Software, and to permit persons
+// to whom Apla Software is furnished to do so, subject to the
+// following conditions:
+// * the copyright notice of GenesisKernel and EGAAS S.A.
+// and this permission notice shall be included in all copies or
+// substantial portions of the software;
+// * a result of the dealing in Apla Software cannot be
+// implemented outside of the Apla Platform environment.
+
+// THE APLA SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY
+// OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED
+// TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE, ERROR FREE AND NONINFRINGEMENT. IN
+// NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR
+// THE USE OR OTHER DEALINGS IN THE APLA SOFTWARE.
+
+package network
+
+import (
+	"encoding/binary"
+	"io"
+
+	"github.com/AplaProject/go-apla/packages/consts"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// RequestTypePeers requests the known peers of the node
+	RequestTypePeers = 13
+
+	// MaxPeersResponse is the maximum count of peers in the response
+	MaxPeersResponse = 100
+
+	maxPeerAddressSize = 256
+)
+
+// PeersRequest contains the address where the requesting node accepts connections.
+// The address is empty if the node doesn't accept them.
+type PeersRequest struct {
+	Address string
+}
+
+func (req *PeersRequest) Read(r io.Reader) error {
+	address, err := ReadSliceWithMaxSize(r, maxPeerAddressSize)
+	if err != nil {
+		return err
+	}
+	req.Address = string(address)
+	return nil
+}
+
+func (req *PeersRequest) Write(w io.Writer) error {
+	return writeSlice(w, []byte(req.Address))
+}
+
+// PeersResponse contains the addresses of the peers
+type PeersResponse struct {
+	Peers []string
+}
+
+func (resp *PeersResponse) Read(r io.Reader) error {
+	var count uint16
+	if err := binary.Read(r, binary.LittleEndian, &count); err != nil {
+		log.WithFields(log.Fields{"type": consts.IOError, "error": err}).Error("on reading peers count")
+		return err
+	}
+	if count > MaxPeersResponse {
+		log.WithFields(log.Fields{"type": consts.ParameterExceeded, "count": count}).Error("too many peers")
+		return ErrMaxSize
+	}
+	resp.Peers = make([]string, count)
+	for i := range resp.Peers {
+		address, err := ReadSliceWithMaxSize(r, maxPeerAddressSize)
+		if err != nil {
+			return err
+		}
+		resp.Peers[i] = string(address)
+	}
+	return nil
+}
+
+func (resp *PeersResponse) Write(w io.Writer) error {
+	peers := resp.Peers
+	if len(peers) > MaxPeersResponse {
+		peers = peers[:MaxPeersResponse]
+	}
+	if err := binary.Write(w, binary.LittleEndian, uint16(len(peers))); err != nil {
+		log.WithFields(log.Fields{"type": consts.IOError, "error": err}).Error("on sending peers count")
+		return err
+	}
+	for _, address := range peers {
+		if err := writeSlice(w, []byte(address)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,272 @@
+// Apla Software includes an integrated development
+// environment with a multi-level system for the management
+// of access rights to data, interfaces, and Smart contracts. The
+// technical characteristics of the Apla Software are indicated in
+// Apla Technical Paper.
+
+// Apla Users are granted a permission to deal in the Apla
+// Software without restrictions, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of Apla Software, and to permit persons
+// to whom Apla Software is furnished to do so, subject to the
+// following conditions:
+// * the copyright notice of GenesisKernel and EGAAS S.A.
+// and this permission notice shall be included in all copies or
+// substantial portions of the software;
+// * a result of the dealing in Apla Software cannot be
+// implemented outside of the Apla Platform environment.
+
+// THE APLA SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY
+// OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED
+// TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE, ERROR FREE AND NONINFRINGEMENT. IN
+// NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR
+// THE USE OR OTHER DEALINGS IN THE APLA SOFTWARE.
+
+package peers
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/AplaProject/go-apla/packages/consts"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// MaxPeers is the maximum count of peers in the table
+	MaxPeers = 500
+
+	maxScore     = 100
+	minScore     = -20
+	successScore = 1
+	failScore    = 5
+)
+
+var (
+	// ErrAddress is returned if the address of the peer isn't a public IP address with a port
+	ErrAddress = errors.New("Wrong address of peer")
+)
+
+// Peer is the node which has been found with the peer exchange
+type Peer struct {
+	Address  string    `json:"address"`
+	Score    int       `json:"score"`
+	LastSeen time.Time `json:"last_seen"` // the time of the last successful connection
+}
+
+// Table is the table of the known peers. The score of the peer grows with each successful
+// connection and falls with each failed one. The peer is removed when its score is too low.
+type Table struct {
+	mutex sync.Mutex
+	path  string
+	peers map[string]*Peer
+	self  map[string]bool
+}
+
+var table = NewTable("")
+
+// NewTable returns the table which is saved to the file with the specified path
+func NewTable(path string) *Table {
+	return &Table{
+		path:  path,
+		peers: make(map[string]*Peer),
+		self:  make(map[string]bool),
+	}
+}
+
+// InitTable loads the table of peers from the file
+func InitTable(path string, self ...string) error {
+	t := NewTable(path)
+	for _, address := range self {
+		t.self[address] = true
+	}
+	if err := t.Load(); err != nil {
+		return err
+	}
+	table = t
+	return nil
+}
+
+// GetTable returns the table of peers
+func GetTable() *Table {
+	return table
+}
+
+// Load reads the peers from the file
+func (t *Table) Load() error {
+	data, err := ioutil.ReadFile(t.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		log.WithFields(log.Fields{"type": consts.IOError, "error": err, "path": t.path}).Error("reading peers file")
+		return err
+	}
+	var list []*Peer
+	if err = json.Unmarshal(data, &list); err != nil {
+		log.WithFields(log.Fields{"type": consts.JSONUnmarshallError, "error": err, "path": t.path}).Error("unmarshalling peers")
+		return err
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	for _, p := range list {
+		if len(t.peers) >= MaxPeers {
+			break
+		}
+		if !t.self[p.Address] && CheckAddress(p.Address) == nil {
+			t.peers[p.Address] = p
+		}
+	}
+	return nil
+}
+
+// Save writes the peers to the file
+func (t *Table) Save() error {
+	if len(t.path) == 0 {
+		return nil
+	}
+	data, err := json.Marshal(t.list())
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.JSONMarshallError, "error": err}).Error("marshalling peers")
+		return err
+	}
+	if err = ioutil.WriteFile(t.path, data, 0600); err != nil {
+		log.WithFields(log.Fields{"type": consts.IOError, "error": err, "path": t.path}).Error("writing peers file")
+		return err
+	}
+	return nil
+}
+
+// list returns the peers ordered by score
+func (t *Table) list() []*Peer {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	list := make([]*Peer, 0, len(t.peers))
+	for _, p := range t.peers {
+		peer := *p
+		list = append(list, &peer)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Score == list[j].Score {
+			return list[i].Address < list[j].Address
+		}
+		return list[i].Score > list[j].Score
+	})
+	return list
+}
+
+// privateNets are the private address ranges of RFC 1918 and RFC 4193
+var privateNets = func() []*net.IPNet {
+	var nets []*net.IPNet
+	for _, cidr := range []string{"10.0.0.0/8", "172.16.0.0/12", "192.168.0.0/16", "fc00::/7"} {
+		_, ipnet, _ := net.ParseCIDR(cidr)
+		nets = append(nets, ipnet)
+	}
+	return nets
+}()
+
+func isPrivate(ip net.IP) bool {
+	for _, ipnet := range privateNets {
+		if ipnet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckAddress checks that the address consists of a public IP address and a port.
+// Host names and private, loopback, link-local and multicast addresses aren't accepted.
+func CheckAddress(address string) error {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return ErrAddress
+	}
+	if p, err := strconv.Atoi(port); err != nil || p <= 0 || p > 65535 {
+		return ErrAddress
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || ip.IsUnspecified() || ip.IsLoopback() || isPrivate(ip) || ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() || ip.IsMulticast() || ip.Equal(net.IPv4bcast) {
+		return ErrAddress
+	}
+	return nil
+}
+
+// Add adds the new peer to the table. It returns false if the peer is already known,
+// the address is wrong or the table is full.
+func (t *Table) Add(address string) bool {
+	if CheckAddress(address) != nil {
+		return false
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if _, ok := t.peers[address]; ok || t.self[address] || len(t.peers) >= MaxPeers {
+		return false
+	}
+	t.peers[address] = &Peer{Address: address}
+	return true
+}
+
+// Success raises the score of the peer after the successful connection
+func (t *Table) Success(address string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if p, ok := t.peers[address]; ok {
+		p.LastSeen = time.Now()
+		if p.Score += successScore; p.Score > maxScore {
+			p.Score = maxScore
+		}
+	}
+}
+
+// Fail lowers the score of the peer after the failed connection
+func (t *Table) Fail(address string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if p, ok := t.peers[address]; ok {
+		if p.Score -= failScore; p.Score < minScore {
+			delete(t.peers, address)
+		}
+	}
+}
+
+// Peers returns the known peers ordered by score
+func (t *Table) Peers() []Peer {
+	list := t.list()
+	ret := make([]Peer, len(list))
+	for i, p := range list {
+		ret[i] = *p
+	}
+	return ret
+}
+
+// Hosts returns the addresses of no more than count peers with the best scores.
+// The peers which have never been connected successfully aren't returned.
+func (t *Table) Hosts(count int) []string {
+	hosts := make([]string, 0, count)
+	for _, p := range t.list() {
+		if len(hosts) >= count || p.Score <= 0 {
+			break
+		}
+		hosts = append(hosts, p.Address)
+	}
+	return hosts
+}
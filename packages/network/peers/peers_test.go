@@ -0,0 +1,84 @@
+// Apla Software includes an integrated development
+// environment with a multi-level system for the management
+// of access rights to data, interfaces, and Smart contracts. The
+// technical characteristics of the Apla Software are indicated in
+// Apla Technical Paper.
+
+// Apla Users are granted a permission to deal in the Apla
+// Software without restrictions, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of Apla Software, and to permit persons
+// to whom Apla Software is furnished to do so, subject to the
+// following conditions:
+// * the copyright notice of GenesisKernel and EGAAS S.A.
+// and this permission notice shall be included in all copies or
+// substantial portions of the software;
+// * a result of the dealing in Apla Software cannot be
+// implemented outside of the Apla Platform environment.
+
+// THE APLA SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY
+// OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED
+// TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE, ERROR FREE AND NONINFRINGEMENT. IN
+// NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR
+// THE USE OR OTHER DEALINGS IN THE APLA SOFTWARE.
+
+package peers
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTable(t *testing.T) {
+	dir, err := ioutil.TempDir("", "peers")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "peers.json")
+
+	require.NoError(t, InitTable(path, "127.0.0.1:7078"))
+	table := GetTable()
+	assert.False(t, table.Add("127.0.0.1:7078"))
+	assert.True(t, table.Add("203.0.113.1:7078"))
+	assert.True(t, table.Add("203.0.113.2:7078"))
+	assert.True(t, table.Add("203.0.113.3:7078"))
+	assert.False(t, table.Add("203.0.113.1:7078"))
+	assert.False(t, table.Add("10.0.0.1:7078"))
+	assert.False(t, table.Add("172.31.0.1:7078"))
+	assert.False(t, table.Add("192.168.1.1:7078"))
+	assert.False(t, table.Add("[fd00::1]:7078"))
+	assert.False(t, table.Add("localhost:7078"))
+	assert.False(t, table.Add("203.0.113.4"))
+	assert.False(t, table.Add("203.0.113.4:0"))
+
+	table.Success("203.0.113.1:7078")
+	table.Success("203.0.113.2:7078")
+	table.Success("203.0.113.2:7078")
+	table.Fail("203.0.113.3:7078")
+	assert.Equal(t, []string{"203.0.113.2:7078", "203.0.113.1:7078"}, table.Hosts(10))
+	assert.Equal(t, []string{"203.0.113.2:7078"}, table.Hosts(1))
+
+	for i := 0; i < 4; i++ {
+		table.Fail("203.0.113.3:7078")
+	}
+	assert.Len(t, table.Peers(), 2)
+
+	require.NoError(t, table.Save())
+	require.NoError(t, InitTable(path))
+	assert.Equal(t, table.Hosts(10), GetTable().Hosts(10))
+	assert.Len(t, GetTable().Peers(), 2)
+
+	for i := 0; i < MaxPeers; i++ {
+		GetTable().Add(fmt.Sprintf("198.51.%d.%d:7078", i/256, i%256))
+	}
+	assert.Len(t, GetTable().Peers(), MaxPeers)
+}
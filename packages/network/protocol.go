@@ -195,7 +195,7 @@ type DisRequest struct {
 }
 
 func (req *DisRequest) Read(r io.Reader) error {
-	slice, err := ReadSlice(r)
+	slice, err := ReadSliceWithMaxSize(r, uint64(syspar.GetMaxBlockSize()))
 	if err != nil {
 		log.WithFields(log.Fields{"error": err}).Error("on reading disseminator request")
 		return err
@@ -241,7 +241,7 @@ type StopNetworkRequest struct {
 }
 
 func (req *StopNetworkRequest) Read(r io.Reader) error {
-	slice, err := ReadSlice(r)
+	slice, err := ReadSliceWithMaxSize(r, uint64(syspar.GetMaxTxSize()))
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,324 @@
+// Apla Software includes an integrated development
+// environment with a multi-level system for the management
+// of access rights to data, interfaces, and Smart contracts. The
+// technical characteristics of the Apla Software are indicated in
+// Apla Technical Paper.
+
+// Apla Users are granted a permission to deal in the Apla
+// Software without restrictions, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of Apla Software, and to permit persons
+// to whom Apla Software is furnished to do so, subject to the
+// following conditions:
+// * the copyright notice of GenesisKernel and EGAAS S.A.
+// and this permission notice shall be included in all copies or
+// substantial portions of the software;
+// * a result of the dealing in Apla Software cannot be
+// implemented outside of the Apla Platform environment.
+
+// THE APLA SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY
+// OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED
+// TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE, ERROR FREE AND NONINFRINGEMENT. IN
+// NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR
+// THE USE OR OTHER DEALINGS IN THE APLA SOFTWARE.
+
+package network
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/elliptic"
+	crand "crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math/big"
+	"net"
+
+	"github.com/AplaProject/go-apla/packages/consts"
+	"github.com/AplaProject/go-apla/packages/crypto"
+	"github.com/AplaProject/go-apla/packages/signer"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// RequestTypeSecure switches the connection to the authenticated encrypted transport
+	RequestTypeSecure = 12
+
+	secureNonceSize  = 32
+	secureMaxKeySize = 256
+	secureMaxSign    = 256
+	maxSecureFrame   = 1 << 16
+)
+
+var (
+	// ErrSecureSign is returned if the node hasn't proved the possession of its key
+	ErrSecureSign = errors.New("Wrong signature of the secure session")
+	// ErrSecureKey is returned if the ephemeral key of the node is invalid
+	ErrSecureKey = errors.New("Wrong ephemeral key of the secure session")
+	// ErrSecureFrame is returned if the encrypted frame can't be read
+	ErrSecureFrame = errors.New("Wrong frame of the secure session")
+)
+
+// NodeVerifier checks the key of the remote node. It returns an error if the connection
+// with the node must be refused.
+type NodeVerifier func(keyID int64, publicKey []byte) error
+
+// SecureHello is sent by both nodes when the secure session is being established
+type SecureHello struct {
+	KeyID     int64
+	PublicKey []byte // public key of the node
+	Ephemeral []byte // ephemeral public key for the session key agreement
+	Nonce     []byte
+}
+
+func (h *SecureHello) Read(r io.Reader) (err error) {
+	if err = binary.Read(r, binary.LittleEndian, &h.KeyID); err != nil {
+		log.WithFields(log.Fields{"type": consts.IOError, "error": err}).Error("on reading secure hello")
+		return err
+	}
+	for _, v := range []*[]byte{&h.PublicKey, &h.Ephemeral, &h.Nonce} {
+		if *v, err = ReadSliceWithMaxSize(r, secureMaxKeySize); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (h *SecureHello) Write(w io.Writer) error {
+	if err := binary.Write(w, binary.LittleEndian, h.KeyID); err != nil {
+		log.WithFields(log.Fields{"type": consts.IOError, "error": err}).Error("on sending secure hello")
+		return err
+	}
+	for _, v := range [][]byte{h.PublicKey, h.Ephemeral, h.Nonce} {
+		if err := writeSlice(w, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SecureConn is the connection where all data are encrypted with the session keys
+type SecureConn struct {
+	net.Conn
+	KeyID     int64  // key id of the remote node
+	PublicKey []byte // public key of the remote node
+
+	reader, writer cipher.AEAD
+	readNonce      uint64
+	writeNonce     uint64
+	buf            []byte
+}
+
+func (c *SecureConn) nonce(counter *uint64) []byte {
+	nonce := make([]byte, c.reader.NonceSize())
+	binary.LittleEndian.PutUint64(nonce, *counter)
+	*counter++
+	return nonce
+}
+
+// Read decrypts the data from the connection
+func (c *SecureConn) Read(p []byte) (int, error) {
+	if len(c.buf) == 0 {
+		var size uint32
+		if err := binary.Read(c.Conn, binary.LittleEndian, &size); err != nil {
+			return 0, err
+		}
+		if size > maxSecureFrame+uint32(c.reader.Overhead()) {
+			return 0, ErrSecureFrame
+		}
+		frame := make([]byte, size)
+		if _, err := io.ReadFull(c.Conn, frame); err != nil {
+			return 0, err
+		}
+		data, err := c.reader.Open(frame[:0], c.nonce(&c.readNonce), frame, nil)
+		if err != nil {
+			log.WithFields(log.Fields{"type": consts.CryptoError, "error": err, "key_id": c.KeyID}).Error("decrypting frame")
+			return 0, ErrSecureFrame
+		}
+		c.buf = data
+	}
+	n := copy(p, c.buf)
+	c.buf = c.buf[n:]
+	return n, nil
+}
+
+// Write encrypts the data and sends it to the connection
+func (c *SecureConn) Write(p []byte) (int, error) {
+	var written int
+	for len(p) > 0 || written == 0 {
+		size := len(p)
+		if size > maxSecureFrame {
+			size = maxSecureFrame
+		}
+		frame := c.writer.Seal(nil, c.nonce(&c.writeNonce), p[:size], nil)
+		out := make([]byte, 4, 4+len(frame))
+		binary.LittleEndian.PutUint32(out, uint32(len(frame)))
+		if _, err := c.Conn.Write(append(out, frame...)); err != nil {
+			return written, err
+		}
+		written += size
+		p = p[size:]
+		if size == 0 {
+			break
+		}
+	}
+	return written, nil
+}
+
+type secureSession struct {
+	keyID     int64
+	signer    signer.Signer
+	ephemeral []byte
+	hello     *SecureHello
+}
+
+func newSecureSession(keyID int64, s signer.Signer) (*secureSession, error) {
+	publicKey, err := s.PublicKey()
+	if err != nil {
+		return nil, err
+	}
+	priv, x, y, err := elliptic.GenerateKey(elliptic.P256(), crand.Reader)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.CryptoError, "error": err}).Error("generating ephemeral key")
+		return nil, err
+	}
+	nonce := make([]byte, secureNonceSize)
+	if _, err = io.ReadFull(crand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return &secureSession{keyID: keyID, signer: s, ephemeral: priv,
+		hello: &SecureHello{KeyID: keyID, PublicKey: publicKey,
+			Ephemeral: elliptic.Marshal(elliptic.P256(), x, y), Nonce: nonce}}, nil
+}
+
+// transcript returns the data which are signed by both nodes
+func transcript(client, server *SecureHello, role string) []byte {
+	buf := &bytes.Buffer{}
+	client.Write(buf)
+	server.Write(buf)
+	buf.WriteString(role)
+	return buf.Bytes()
+}
+
+// conn derives the session keys and returns the secure connection
+func (s *secureSession) conn(conn net.Conn, remote, client, server *SecureHello, isClient bool) (*SecureConn, error) {
+	curve := elliptic.P256()
+	x, y := elliptic.Unmarshal(curve, remote.Ephemeral)
+	if x == nil {
+		return nil, ErrSecureKey
+	}
+	sx, _ := curve.ScalarMult(x, y, s.ephemeral)
+	shared := make([]byte, 32)
+	sx.FillBytes(shared)
+	if sx.Cmp(big.NewInt(0)) == 0 {
+		return nil, ErrSecureKey
+	}
+	hash := sha256.Sum256(transcript(client, server, ``))
+	master := sha256.Sum256(append(shared, hash[:]...))
+
+	aead := func(role string) (cipher.AEAD, error) {
+		key := sha256.Sum256(append(master[:], role...))
+		block, err := aes.NewCipher(key[:])
+		if err != nil {
+			return nil, err
+		}
+		return cipher.NewGCM(block)
+	}
+	clientKey, err := aead(`client`)
+	if err != nil {
+		return nil, err
+	}
+	serverKey, err := aead(`server`)
+	if err != nil {
+		return nil, err
+	}
+	ret := &SecureConn{Conn: conn, KeyID: remote.KeyID, PublicKey: remote.PublicKey,
+		reader: serverKey, writer: clientKey}
+	if !isClient {
+		ret.reader, ret.writer = clientKey, serverKey
+	}
+	return ret, nil
+}
+
+func checkSecureSign(hello *SecureHello, data, sign []byte) error {
+	if ok, err := crypto.CheckSign(hello.PublicKey, data, sign); !ok || err != nil {
+		log.WithFields(log.Fields{"type": consts.CryptoError, "error": err, "key_id": hello.KeyID}).Error("checking signature of secure session")
+		return ErrSecureSign
+	}
+	return nil
+}
+
+// SecureClient establishes the secure session on the client side of the connection.
+// The request type RequestTypeSecure must be already sent.
+func SecureClient(conn net.Conn, keyID int64, nodeSigner signer.Signer, verify NodeVerifier) (*SecureConn, error) {
+	s, err := newSecureSession(keyID, nodeSigner)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.hello.Write(conn); err != nil {
+		return nil, err
+	}
+	server := &SecureHello{}
+	if err = server.Read(conn); err != nil {
+		return nil, err
+	}
+	if err = verify(server.KeyID, server.PublicKey); err != nil {
+		return nil, err
+	}
+	serverSign, err := ReadSliceWithMaxSize(conn, secureMaxSign)
+	if err != nil {
+		return nil, err
+	}
+	if err = checkSecureSign(server, transcript(s.hello, server, `server`), serverSign); err != nil {
+		return nil, err
+	}
+	sign, err := s.signer.Sign(transcript(s.hello, server, `client`))
+	if err != nil {
+		return nil, err
+	}
+	if err = writeSlice(conn, sign); err != nil {
+		return nil, err
+	}
+	return s.conn(conn, server, s.hello, server, true)
+}
+
+// SecureServer establishes the secure session on the server side of the connection
+func SecureServer(conn net.Conn, keyID int64, nodeSigner signer.Signer, verify NodeVerifier) (*SecureConn, error) {
+	client := &SecureHello{}
+	if err := client.Read(conn); err != nil {
+		return nil, err
+	}
+	if err := verify(client.KeyID, client.PublicKey); err != nil {
+		return nil, err
+	}
+	s, err := newSecureSession(keyID, nodeSigner)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.hello.Write(conn); err != nil {
+		return nil, err
+	}
+	sign, err := s.signer.Sign(transcript(client, s.hello, `server`))
+	if err != nil {
+		return nil, err
+	}
+	if err = writeSlice(conn, sign); err != nil {
+		return nil, err
+	}
+	clientSign, err := ReadSliceWithMaxSize(conn, secureMaxSign)
+	if err != nil {
+		return nil, err
+	}
+	if err = checkSecureSign(client, transcript(client, s.hello, `client`), clientSign); err != nil {
+		return nil, err
+	}
+	return s.conn(conn, client, client, s.hello, false)
+}
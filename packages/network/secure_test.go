@@ -0,0 +1,135 @@
+// Apla Software includes an integrated development
+// environment with a multi-level system for the management
+// of access rights to data, interfaces, and Smart contracts. The
+// technical characteristics of the Apla Software are indicated in
+// Apla Technical Paper.
+
+// Apla Users are granted a permission to deal in the Apla
+// Software without restrictions, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of Apla Software, and to permit persons
+// to whom Apla Software is furnished to do so, subject to the
+// following conditions:
+// * the copyright notice of GenesisKernel and EGAAS S.A.
+// and this permission notice shall be included in all copies or
+// substantial portions of the software;
+// * a result of the dealing in Apla Software cannot be
+// implemented outside of the Apla Platform environment.
+
+// THE APLA SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY
+// OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED
+// TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE, ERROR FREE AND NONINFRINGEMENT. IN
+// NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR
+// THE USE OR OTHER DEALINGS IN THE APLA SOFTWARE.
+
+package network
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/AplaProject/go-apla/packages/crypto"
+	"github.com/AplaProject/go-apla/packages/signer"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type secureNode struct {
+	keyID           int64
+	private, public []byte
+}
+
+// PublicKey returns the public key of the node, it can differ from the private key of the impostor
+func (n *secureNode) PublicKey() ([]byte, error) {
+	return n.public, nil
+}
+
+func (n *secureNode) Sign(data []byte) ([]byte, error) {
+	return signer.NewKeySigner(n.private).Sign(data)
+}
+
+func newSecureNode(t *testing.T, keyID int64) *secureNode {
+	priv, pub, err := crypto.GenBytesKeys()
+	require.NoError(t, err)
+	return &secureNode{keyID: keyID, private: priv, public: pub}
+}
+
+func (n *secureNode) verifier(keyID int64, public []byte) NodeVerifier {
+	return func(id int64, pub []byte) error {
+		if id != keyID || !bytes.Equal(pub, public) {
+			return errors.New("unknown node")
+		}
+		return nil
+	}
+}
+
+type secureResult struct {
+	conn *SecureConn
+	err  error
+}
+
+func connectSecure(client, server *secureNode, clientVerify, serverVerify NodeVerifier) (c, s secureResult) {
+	cconn, sconn := net.Pipe()
+	ch := make(chan secureResult)
+	go func() {
+		conn, err := SecureServer(sconn, server.keyID, server, serverVerify)
+		if err != nil {
+			sconn.Close()
+		}
+		ch <- secureResult{conn, err}
+	}()
+	conn, err := SecureClient(cconn, client.keyID, client, clientVerify)
+	if err != nil {
+		cconn.Close()
+	}
+	return secureResult{conn, err}, <-ch
+}
+
+func TestSecureConn(t *testing.T) {
+	client, server := newSecureNode(t, 1), newSecureNode(t, 2)
+	c, s := connectSecure(client, server, client.verifier(server.keyID, server.public),
+		server.verifier(client.keyID, client.public))
+	require.NoError(t, c.err)
+	require.NoError(t, s.err)
+	assert.Equal(t, server.keyID, c.conn.KeyID)
+	assert.Equal(t, client.keyID, s.conn.KeyID)
+
+	data := bytes.Repeat([]byte("apla"), maxSecureFrame)
+	go func() {
+		rt := &RequestType{Type: RequestTypeFullNode}
+		rt.Write(c.conn)
+		c.conn.Write(data)
+	}()
+	rt := &RequestType{}
+	require.NoError(t, rt.Read(s.conn))
+	assert.Equal(t, uint16(RequestTypeFullNode), rt.Type)
+	received := make([]byte, len(data))
+	_, err := io.ReadFull(s.conn, received)
+	require.NoError(t, err)
+	assert.Equal(t, data, received)
+}
+
+func TestSecureRefused(t *testing.T) {
+	client, server, other := newSecureNode(t, 1), newSecureNode(t, 2), newSecureNode(t, 3)
+
+	// the server doesn't know the key of the client
+	c, s := connectSecure(client, server, client.verifier(server.keyID, server.public),
+		server.verifier(client.keyID, other.public))
+	assert.Error(t, c.err)
+	assert.Error(t, s.err)
+
+	// the node pretends to be the server without its private key
+	impostor := &secureNode{keyID: server.keyID, private: other.private, public: server.public}
+	c, s = connectSecure(client, impostor, client.verifier(server.keyID, server.public),
+		impostor.verifier(client.keyID, client.public))
+	assert.Equal(t, ErrSecureSign, c.err)
+	assert.Error(t, s.err)
+}
@@ -29,17 +29,59 @@
 package tcpclient
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
+	"io"
 	"net"
+	"os"
 	"strings"
+	"sync"
+	"syscall"
 	"time"
 
+	"github.com/AplaProject/go-apla/packages/conf"
+	"github.com/AplaProject/go-apla/packages/conf/syspar"
 	"github.com/AplaProject/go-apla/packages/consts"
+	"github.com/AplaProject/go-apla/packages/network"
+	"github.com/AplaProject/go-apla/packages/signer"
 	log "github.com/sirupsen/logrus"
 )
 
-var wrongAddressError = errors.New("Wrong address")
+// legacyHostTimeout is the time after which the handshake is tried again with the legacy host
+const legacyHostTimeout = 10 * time.Minute
+
+var (
+	wrongAddressError = errors.New("Wrong address")
+	errNoHandshake    = errors.New("Handshake isn't supported")
+	errWrongNodeKey   = errors.New("Public key doesn't match the full node")
+	errUnknownNode    = errors.New("Host isn't a full node")
+	errInsecureNode   = errors.New("Node doesn't support the secure session")
+)
+
+// legacyHosts contains hosts which close the connection on the handshake request without reply
+var legacyHosts = struct {
+	sync.Mutex
+	hosts map[string]time.Time
+}{hosts: make(map[string]time.Time)}
+
+func isLegacyHost(host string) bool {
+	legacyHosts.Lock()
+	defer legacyHosts.Unlock()
+	if t, ok := legacyHosts.hosts[host]; ok {
+		if time.Since(t) < legacyHostTimeout {
+			return true
+		}
+		delete(legacyHosts.hosts, host)
+	}
+	return false
+}
+
+func setLegacyHost(host string) {
+	legacyHosts.Lock()
+	legacyHosts.hosts[host] = time.Now()
+	legacyHosts.Unlock()
+}
 
 // NormalizeHostAddress get address. if port not defined returns combined string with ip and defaultPort
 func NormalizeHostAddress(address string, defaultPort int64) (string, error) {
@@ -56,7 +98,153 @@ func NormalizeHostAddress(address string, defaultPort int64) (string, error) {
 	return address, nil
 }
 
+// newConnection connects to the node and negotiates the protocol version. If the node
+// doesn't support the handshake then the connection is used without it.
 func newConnection(addr string) (net.Conn, error) {
+	conn, _, err := connect(addr)
+	return conn, err
+}
+
+// connect connects to the node and returns the negotiated handshake. The handshake is nil
+// if the node doesn't support it.
+func connect(addr string) (net.Conn, *network.HandshakeResponse, error) {
+	if conf.Config.SecureNodes {
+		return connectSecure(addr)
+	}
+	conn, err := dial(addr)
+	if err != nil || isLegacyHost(addr) {
+		return conn, nil, err
+	}
+	resp, err := handshake(conn)
+	if err == errNoHandshake {
+		conn.Close()
+		log.WithFields(log.Fields{"type": consts.NetworkError, "host": addr}).Debug("host doesn't support handshake")
+		setLegacyHost(addr)
+		conn, err = dial(addr)
+		return conn, nil, err
+	}
+	if err == nil && !resp.Accepted {
+		log.WithFields(log.Fields{"type": consts.NetworkError, "host": addr, "reason": resp.Reason}).Warning("handshake refused")
+		err = network.ErrHandshakeRefused
+	}
+	if err != nil {
+		conn.Close()
+		return nil, nil, err
+	}
+	return conn, resp, nil
+}
+
+// connectSecure connects to the full node over the encrypted session which is authenticated
+// by the key of the node from full_nodes. The connection is never downgraded to cleartext.
+func connectSecure(addr string) (net.Conn, *network.HandshakeResponse, error) {
+	if _, err := syspar.GetNodeByHost(addr); err != nil {
+		log.WithFields(log.Fields{"type": consts.NetworkError, "host": addr}).Warning("refused connection to the host which is not a full node")
+		return nil, nil, errUnknownNode
+	}
+	conn, err := dial(addr)
+	if err != nil {
+		return nil, nil, err
+	}
+	resp, err := handshake(conn)
+	if err == nil && !resp.Accepted {
+		log.WithFields(log.Fields{"type": consts.NetworkError, "host": addr, "reason": resp.Reason}).Warning("handshake refused")
+		err = network.ErrHandshakeRefused
+	}
+	if err == nil && !resp.Supports(network.RequestTypeSecure) {
+		log.WithFields(log.Fields{"type": consts.NetworkError, "host": addr, "version": resp.Version}).Warning("node doesn't support secure session")
+		err = errInsecureNode
+	}
+	if err == nil {
+		var sconn *network.SecureConn
+		if sconn, err = secure(conn, addr); err == nil {
+			return sconn, resp, nil
+		}
+	}
+	conn.Close()
+	return nil, nil, err
+}
+
+// secure switches the connection to the authenticated encrypted session
+func secure(conn net.Conn, addr string) (*network.SecureConn, error) {
+	rt := &network.RequestType{Type: network.RequestTypeSecure}
+	if err := rt.Write(conn); err != nil {
+		log.WithFields(log.Fields{"type": consts.NetworkError, "error": err}).Error("on sending secure request type")
+		return nil, err
+	}
+	sconn, err := network.SecureClient(conn, conf.Config.KeyID, signer.Node(), func(keyID int64, pub []byte) error {
+		// the node must prove the key of its address from full_nodes
+		node, err := syspar.GetNodeByHost(addr)
+		if err != nil {
+			log.WithFields(log.Fields{"type": consts.NetworkError, "host": addr, "key_id": keyID}).Warning("unknown full node")
+			return errUnknownNode
+		}
+		if node.KeyID != keyID || !bytes.Equal(node.PublicKey, pub) {
+			log.WithFields(log.Fields{"type": consts.NetworkError, "host": addr, "key_id": keyID}).Warning("wrong key of full node")
+			return errWrongNodeKey
+		}
+		return nil
+	})
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.NetworkError, "host": addr, "error": err}).Warning("secure session failed")
+	}
+	return sconn, err
+}
+
+// handshake sends the handshake request with the version and request types of the node
+func handshake(conn net.Conn) (*network.HandshakeResponse, error) {
+	rt := &network.RequestType{Type: network.RequestTypeHandshake}
+	if err := rt.Write(conn); err != nil {
+		log.WithFields(log.Fields{"type": consts.NetworkError, "error": err}).Error("on sending handshake request type")
+		return nil, err
+	}
+	req := &network.HandshakeRequest{
+		Version:   network.ProtocolVersion,
+		KeyID:     conf.Config.KeyID,
+		NetworkID: conf.Config.NetworkID,
+		Types:     network.SupportedRequestTypes(network.ProtocolVersion),
+	}
+	if err := req.Write(conn); err != nil {
+		return nil, err
+	}
+	resp := &network.HandshakeResponse{}
+	cr := &countReader{r: conn}
+	if err := resp.Read(cr); err != nil {
+		if cr.n == 0 && isUnsupportedReply(err) {
+			return nil, errNoHandshake
+		}
+		log.WithFields(log.Fields{"type": consts.NetworkError, "error": err}).Warning("on reading handshake response")
+		return nil, err
+	}
+	return resp, nil
+}
+
+// isUnsupportedReply returns true if the node has closed the connection instead of the response.
+// It is the reply of the node of the legacy version to the unknown request type.
+// Timeouts and broken responses don't mean that the handshake isn't supported.
+func isUnsupportedReply(err error) bool {
+	if err == io.EOF {
+		return true
+	}
+	if opErr, ok := err.(*net.OpError); ok {
+		if sysErr, ok := opErr.Err.(*os.SyscallError); ok {
+			return sysErr.Err == syscall.ECONNRESET
+		}
+	}
+	return false
+}
+
+type countReader struct {
+	r io.Reader
+	n int
+}
+
+func (cr *countReader) Read(p []byte) (int, error) {
+	n, err := cr.r.Read(p)
+	cr.n += n
+	return n, err
+}
+
+func dial(addr string) (net.Conn, error) {
 	if len(addr) == 0 {
 		return nil, wrongAddressError
 	}
@@ -57,131 +57,19 @@ func (bc BufCloser) Close() error {
 	return nil
 }
 
-func BenchmarkGetBlockBodiesWithChanReadAll(t *testing.B) {
-	var bts []byte
-	r := BufCloser{bytes.NewBuffer(bts)}
-
-	// dataLen := 4
-	t.ResetTimer()
-	for j := 0; j < t.N; j++ {
-		t.StopTimer()
-		for i := 0; i < 100; i++ {
-			resp := network.GetBodyResponse{
-				Data: inputs[i],
-			}
-
-			resp.Write(r)
-		}
-
-		ctxDone, cancel := context.WithCancel(context.Background())
-
-		t.StartTimer()
-		blocksC, errC := GetBlockBodiesChanReadAll(ctxDone, r, 100)
-		// blocksC, errC := GetBlockBodiesChan(ctxDone, r, 100)
-		go func() {
-			err := <-errC
-			if err != nil {
-				fmt.Println(err)
-			}
-		}()
-
-		for item := range blocksC {
-			item = item[:0]
-		}
-		cancel()
-	}
-}
-
-//===================================================GetBlockBodiesChanByBlock
-
-// 500	   2264475 ns/op	  109001 B/op	     108 allocs/op with pool size 12832256
-func BenchmarkGetBlockBodiesChanByBlockWithSyncPool(t *testing.B) {
-	var bts []byte
-	r := BufCloser{bytes.NewBuffer(bts)}
-
-	t.ResetTimer()
-	for j := 0; j < t.N; j++ {
-		t.StopTimer()
-		for i := 0; i < 100; i++ {
-			resp := network.GetBodyResponse{
-				Data: inputs[i],
-			}
-
-			// fmt.Println("lenData", len(inputs[i]))
-			resp.Write(r)
-		}
-
-		ctxDone, cancel := context.WithCancel(context.Background())
-
-		t.StartTimer()
-		blocksC, errC := GetBlockBodiesChanByBlock(ctxDone, r, 100)
-
-		go func() {
-			err := <-errC
-			if err != nil {
-				fmt.Println(err)
-			}
-		}()
-
-		for item := range blocksC {
-			item = item[:0]
-		}
-		cancel()
-	}
-
-}
-
-func BenchmarkGetBlockBodiesChanByBlockWithBytePool(t *testing.B) {
+func BenchmarkGetBlockBodiesWithChanReadToStruct(t *testing.B) {
 	var bts []byte
 	r := BufCloser{bytes.NewBuffer(bts)}
 
 	t.ResetTimer()
 	for j := 0; j < t.N; j++ {
 		t.StopTimer()
-
 		var dataSize int64
 		for i := 0; i < 100; i++ {
 			dataSize += int64(len(inputs[i]))
 		}
 		network.WriteInt(dataSize, r)
-		// fmt.Println("sending data size", dataSize)
-
-		for i := 0; i < 100; i++ {
-			resp := network.GetBodyResponse{
-				Data: inputs[i],
-			}
-
-			// fmt.Println("lenData", len(inputs[i]))
-			resp.Write(r)
-		}
-
-		ctxDone, cancel := context.WithCancel(context.Background())
-
-		t.StartTimer()
-		blocksC, errC := GetBlockBodiesChanByBlockWithBytePool(ctxDone, r, 100)
-
-		go func() {
-			err := <-errC
-			if err != nil {
-				fmt.Println(err)
-			}
-		}()
-
-		for item := range blocksC {
-			// fmt.Println(len(item))
-			item = item[:0]
-		}
-		cancel()
-	}
-}
-
-func BenchmarkGetBlockBodiesWithChanReadToStruct(t *testing.B) {
-	var bts []byte
-	r := BufCloser{bytes.NewBuffer(bts)}
 
-	t.ResetTimer()
-	for j := 0; j < t.N; j++ {
-		t.StopTimer()
 		for i := 0; i < 100; i++ {
 			resp := network.GetBodyResponse{
 				Data: inputs[i],
@@ -191,7 +79,7 @@ func BenchmarkGetBlockBodiesWithChanReadToStruct(t *testing.B) {
 			resp.Write(r)
 		}
 
-		ctx := context.Background()
+		ctx, cancel := context.WithCancel(context.Background())
 
 		t.StartTimer()
 		blocksC, errC := GetBlockBodiesChan(ctx, r, 100)
@@ -206,36 +94,6 @@ func BenchmarkGetBlockBodiesWithChanReadToStruct(t *testing.B) {
 		for item := range blocksC {
 			item = item[:0]
 		}
-	}
-}
-
-func BenchmarkGetBlockBodiesAsSlice(t *testing.B) {
-	var bts []byte
-	r := BufCloser{bytes.NewBuffer(bts)}
-
-	// dataLen := 4
-	t.ResetTimer()
-	for j := 0; j < t.N; j++ {
-		t.StopTimer()
-		for i := 0; i < 100; i++ {
-			resp := network.GetBodyResponse{
-				Data: inputs[i],
-			}
-
-			resp.Write(r)
-		}
-
-		ctxDone, cancel := context.WithCancel(context.Background())
-
-		t.StartTimer()
-		blocks, err := GetBlockBodiesReadAll(ctxDone, r, 100)
-		if err != nil {
-			fmt.Println(err)
-		}
-
-		for i := 0; i < len(blocks); i++ {
-			blocks[i] = blocks[i][:0]
-		}
 		cancel()
 	}
 }
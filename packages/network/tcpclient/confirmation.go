@@ -29,12 +29,17 @@
 package tcpclient
 
 import (
+	"errors"
+
 	"github.com/AplaProject/go-apla/packages/consts"
 	"github.com/AplaProject/go-apla/packages/converter"
 	"github.com/AplaProject/go-apla/packages/network"
 	log "github.com/sirupsen/logrus"
 )
 
+// ErrNotSupported is returned if the host doesn't support the request type
+var ErrNotSupported = errors.New("Request type isn't supported by the host")
+
 func CheckConfirmation(host string, blockID int64, logger *log.Entry) (hash string) {
 	conn, err := newConnection(host)
 	if err != nil {
@@ -65,3 +70,39 @@ func CheckConfirmation(host string, blockID int64, logger *log.Entry) (hash stri
 	}
 	return string(converter.BinToHex(resp.Hash))
 }
+
+// GetSignedConfirmation returns the hash of the block signed by the host.
+// ErrNotSupported is returned for the hosts which only send unsigned confirmations.
+func GetSignedConfirmation(host string, blockID int64, logger *log.Entry) (*network.SignedConfirmResponse, error) {
+	conn, hs, err := connect(host)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.ConnectionError, "error": err, "host": host, "block_id": blockID}).Debug("dialing to host")
+		return nil, err
+	}
+	defer conn.Close()
+
+	if hs == nil || !hs.Supports(network.RequestTypeSignedConfirmation) {
+		return nil, ErrNotSupported
+	}
+
+	rt := &network.RequestType{Type: network.RequestTypeSignedConfirmation}
+	if err = rt.Write(conn); err != nil {
+		logger.WithFields(log.Fields{"type": consts.IOError, "error": err, "host": host, "block_id": blockID}).Error("sending request type")
+		return nil, err
+	}
+
+	req := &network.ConfirmRequest{
+		BlockID: uint32(blockID),
+	}
+	if err = req.Write(conn); err != nil {
+		logger.WithFields(log.Fields{"type": consts.IOError, "error": err, "host": host, "block_id": blockID}).Error("sending signed confirmation request")
+		return nil, err
+	}
+
+	resp := &network.SignedConfirmResponse{}
+	if err = resp.Read(conn); err != nil {
+		logger.WithFields(log.Fields{"type": consts.IOError, "error": err, "host": host, "block_id": blockID}).Error("receiving signed confirmation response")
+		return nil, err
+	}
+	return resp, nil
+}
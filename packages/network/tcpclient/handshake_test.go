@@ -0,0 +1,77 @@
+// Apla Software includes an integrated development
+// environment with a multi-level system for the management
+// of access rights to data, interfaces, and Smart contracts. The
+// technical characteristics of the Apla Software are indicated in
+// Apla Technical Paper.
+
+// Apla Users are granted a permission to deal in the Apla
+// Software without restrictions, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of Apla Software, and to permit persons
+// to whom Apla Software is furnished to do so, subject to the
+// following conditions:
+// * the copyright notice of GenesisKernel and EGAAS S.A.
+// and this permission notice shall be included in all copies or
+// substantial portions of the software;
+// * a result of the dealing in Apla Software cannot be
+// implemented outside of the Apla Platform environment.
+
+// THE APLA SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY
+// OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED
+// TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE, ERROR FREE AND NONINFRINGEMENT. IN
+// NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR
+// THE USE OR OTHER DEALINGS IN THE APLA SOFTWARE.
+
+package tcpclient
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/AplaProject/go-apla/packages/network"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func handshakeServer(t *testing.T, reply []byte) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go func() {
+		defer l.Close()
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		rt := &network.RequestType{}
+		rt.Read(conn)
+		if len(reply) > 0 {
+			conn.Write(reply)
+			time.Sleep(100 * time.Millisecond)
+		}
+		conn.Close()
+	}()
+	return l.Addr().String()
+}
+
+func TestHandshakeLegacy(t *testing.T) {
+	// the legacy node closes the connection on the unknown request type
+	conn, err := dial(handshakeServer(t, nil))
+	require.NoError(t, err)
+	_, err = handshake(conn)
+	assert.Equal(t, errNoHandshake, err)
+	conn.Close()
+
+	// the broken response isn't the reply of the legacy node
+	conn, err = dial(handshakeServer(t, []byte{1}))
+	require.NoError(t, err)
+	_, err = handshake(conn)
+	assert.Error(t, err)
+	assert.NotEqual(t, errNoHandshake, err)
+	conn.Close()
+}
@@ -34,6 +34,7 @@ import (
 
 	"github.com/AplaProject/go-apla/packages/consts"
 	"github.com/AplaProject/go-apla/packages/network"
+	"github.com/AplaProject/go-apla/packages/network/peers"
 	"github.com/AplaProject/go-apla/packages/utils"
 	log "github.com/sirupsen/logrus"
 )
@@ -82,6 +83,27 @@ func getMaxBlock(host string) (blockID int64, err error) {
 func hostWithMaxBlock(ctx context.Context, hosts []string) (bestHost string, maxBlockID int64, err error) {
 	maxBlockID = -1
 
+	blocks, err := HostsMaxBlock(ctx, hosts)
+	if err == ErrNodesUnavailable {
+		return "", 0, err
+	}
+	if err != nil {
+		return "", maxBlockID, err
+	}
+
+	for _, h := range hosts {
+		// If blockID is maximal then the current host is the best
+		if blockID, ok := blocks[h]; ok && blockID > maxBlockID {
+			maxBlockID = blockID
+			bestHost = h
+		}
+	}
+
+	return bestHost, maxBlockID, nil
+}
+
+// HostsMaxBlock returns max block id of each available host
+func HostsMaxBlock(ctx context.Context, hosts []string) (map[string]int64, error) {
 	type blockAndHost struct {
 		host    string
 		blockID int64
@@ -99,7 +121,7 @@ func hostWithMaxBlock(ctx context.Context, hosts []string) (bestHost string, max
 	for _, h := range hosts {
 		if ctx.Err() != nil {
 			log.WithFields(log.Fields{"error": ctx.Err(), "type": consts.ContextError}).Error("context error")
-			return "", maxBlockID, ctx.Err()
+			return nil, ctx.Err()
 		}
 
 		wg.Add(1)
@@ -117,25 +139,20 @@ func hostWithMaxBlock(ctx context.Context, hosts []string) (bestHost string, max
 	}
 	wg.Wait()
 
-	var errCount int
+	blocks := make(map[string]int64, len(hosts))
 	for i := 0; i < len(hosts); i++ {
 		bl := <-resultChan
-
 		if bl.err != nil {
-			errCount++
+			peers.GetTable().Fail(bl.host)
 			continue
 		}
-
-		// If blockID is maximal then the current host is the best
-		if bl.blockID > maxBlockID {
-			maxBlockID = bl.blockID
-			bestHost = bl.host
-		}
+		peers.GetTable().Success(bl.host)
+		blocks[bl.host] = bl.blockID
 	}
 
-	if errCount == len(hosts) {
-		return "", 0, ErrNodesUnavailable
+	if len(hosts) > 0 && len(blocks) == 0 {
+		return nil, ErrNodesUnavailable
 	}
 
-	return bestHost, maxBlockID, nil
+	return blocks, nil
 }
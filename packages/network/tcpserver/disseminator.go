@@ -50,7 +50,7 @@ import (
 // download the transactions here, because they are small and definitely will be downloaded in 60 sec
 func Type1(rw io.ReadWriter) error {
 	r := &network.DisRequest{}
-	if err := r.Read(rw); err != nil {
+	if err := readRequest(r, rw); err != nil {
 		return err
 	}
 
@@ -74,6 +74,10 @@ func Type1(rw io.ReadWriter) error {
 	// full_node_id of the sender to know where to take a data when it will be downloaded by another daemon
 	fullNodeID := converter.BinToDec(buf.Next(8))
 	log.Debug("fullNodeID", fullNodeID)
+	if sconn, ok := rw.(*network.SecureConn); ok && sconn.KeyID != fullNodeID {
+		log.WithFields(log.Fields{"type": consts.NetworkError, "key_id": sconn.KeyID, "full_node_id": fullNodeID}).Warning("full node id mismatch")
+		return malformed(errNodeIDMismatch)
+	}
 
 	n := syspar.GetNode(fullNodeID)
 	if n != nil && service.GetNodesBanService().IsBanned(*n) {
@@ -114,7 +118,7 @@ func Type1(rw io.ReadWriter) error {
 	txBodies, err := resieveTxBodies(rw)
 	if err != nil {
 		log.WithFields(log.Fields{"error": err}).Error("on reading needed txes from disseminator")
-		return err
+		return malformed(err)
 	}
 
 	// and save them
@@ -129,6 +133,10 @@ func resieveTxBodies(con io.Reader) ([]byte, error) {
 	}
 
 	size := converter.BinToDec(sizeBuf)
+	if size > syspar.GetMaxBlockSize() {
+		log.WithFields(log.Fields{"type": consts.ParameterExceeded, "max_size": syspar.GetMaxBlockSize(), "size": size}).Error("size of tx bodies exceeds max block size")
+		return nil, network.ErrMaxSize
+	}
 	txBodies := make([]byte, size)
 	if _, err := io.ReadFull(con, txBodies); err != nil {
 		log.WithFields(log.Fields{"type": consts.IOError, "error": err}).Error("on getting tx bodies")
@@ -181,7 +189,7 @@ func getUnknownTransactions(buf *bytes.Buffer) ([]byte, error) {
 	hashes, err := readHashes(buf)
 	if err != nil {
 		log.WithFields(log.Fields{"type": consts.ProtocolError, "error": err}).Error("on reading hashes")
-		return nil, err
+		return nil, malformed(err)
 	}
 
 	var needTx []byte
@@ -248,28 +256,28 @@ func saveNewTransactions(binaryTxs []byte) error {
 		txSize, err := converter.DecodeLength(&binaryTxs)
 		if err != nil {
 			log.WithFields(log.Fields{"type": consts.ProtocolError, "err": err}).Error("decoding binary txs length")
-			return err
+			return malformed(err)
 		}
 		if int64(len(binaryTxs)) < txSize {
 			log.WithFields(log.Fields{"type": consts.ProtocolError, "size": txSize, "len": len(binaryTxs)}).Error("incorrect binary txs len")
-			return utils.ErrInfo(errors.New("bad transactions packet"))
+			return malformed(utils.ErrInfo(errors.New("bad transactions packet")))
 		}
 
 		txBinData := converter.BytesShift(&binaryTxs, txSize)
 		if len(txBinData) == 0 {
 			log.WithFields(log.Fields{"type": consts.EmptyObject}).Error("binaryTxs is empty")
-			return utils.ErrInfo(errors.New("len(txBinData) == 0"))
+			return malformed(utils.ErrInfo(errors.New("len(txBinData) == 0")))
 		}
 
 		if int64(len(txBinData)) > syspar.GetMaxTxSize() {
 			log.WithFields(log.Fields{"type": consts.ParameterExceeded, "len": len(txBinData), "size": syspar.GetMaxTxSize()}).Error("len of tx data exceeds max size")
-			return utils.ErrInfo("len(txBinData) > max_tx_size")
+			return malformed(utils.ErrInfo("len(txBinData) > max_tx_size"))
 		}
 
 		tx := transaction.RawTransaction{}
 		if err = tx.Unmarshall(bytes.NewBuffer(txBinData)); err != nil {
 			log.WithFields(log.Fields{"type": consts.UnmarshallingError, "error": err}).Error("unmarshalling transaction")
-			return err
+			return malformed(err)
 		}
 
 		queue = append(queue, &model.QueueTx{Hash: tx.Hash(), Data: txBinData, FromGate: 1})
@@ -0,0 +1,148 @@
+// Apla Software includes an integrated development
+// environment with a multi-level system for the management
+// of access rights to data, interfaces, and Smart contracts. The
+// technical characteristics of the Apla Software are indicated in
+// Apla Technical Paper.
+
+// Apla Users are granted a permission to deal in the Apla
+// Software without restrictions, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of Apla Software, and to permit persons
+// to whom Apla Software is furnished to do so, subject to the
+// following conditions:
+// * the copyright notice of GenesisKernel and EGAAS S.A.
+// and this permission notice shall be included in all copies or
+// substantial portions of the software;
+// * a result of the dealing in Apla Software cannot be
+// implemented outside of the Apla Platform environment.
+
+// THE APLA SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY
+// OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED
+// TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE, ERROR FREE AND NONINFRINGEMENT. IN
+// NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR
+// THE USE OR OTHER DEALINGS IN THE APLA SOFTWARE.
+
+package tcpserver
+
+import (
+	"io"
+	"net"
+	"time"
+
+	"github.com/AplaProject/go-apla/packages/conf"
+	"github.com/AplaProject/go-apla/packages/consts"
+	"github.com/AplaProject/go-apla/packages/converter"
+	"github.com/AplaProject/go-apla/packages/network"
+	"github.com/AplaProject/go-apla/packages/network/limiter"
+	"github.com/AplaProject/go-apla/packages/statsd"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// limits of the peers, they are disabled until the listener is started
+var limits = limiter.New(limiter.Config{})
+
+// malformedError is the error which is caused by the malformed data of the peer
+type malformedError struct {
+	error
+}
+
+// malformed marks the error as the violation of the protocol. The errors of reading
+// from the connection such as EOF and timeouts aren't counted because honest nodes get them too.
+func malformed(err error) error {
+	if err == nil || isIOError(err) {
+		return err
+	}
+	if _, ok := err.(malformedError); ok {
+		return err
+	}
+	return malformedError{err}
+}
+
+func isIOError(err error) bool {
+	if err == io.EOF || err == io.ErrUnexpectedEOF || err == io.ErrClosedPipe {
+		return true
+	}
+	_, ok := err.(net.Error)
+	return ok
+}
+
+func isMalformed(err error) bool {
+	_, ok := err.(malformedError)
+	return ok
+}
+
+func initLimits() {
+	cfg := conf.Config.TCPLimits
+	limits = limiter.New(limiter.Config{
+		MaxConnections: cfg.MaxConnections,
+		RequestRate:    cfg.RequestRate,
+		RequestBurst:   cfg.RequestBurst,
+		MaxFails:       cfg.MaxFails,
+		BanTime:        time.Duration(cfg.BanTime) * time.Second,
+	})
+}
+
+// ipPeer returns the key of the peer by IP address
+func ipPeer(addr net.Addr) string {
+	host, _, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		host = addr.String()
+	}
+	return "ip:" + host
+}
+
+// nodePeer returns the key of the peer by node key
+func nodePeer(keyID int64) string {
+	return "node:" + converter.Int64ToStr(keyID)
+}
+
+func incCounter(name string) {
+	if statsd.Client != nil {
+		statsd.Client.Inc(statsd.TCPServerCounterName(name)+statsd.Count, 1, 1.0)
+	}
+}
+
+// rejectPeer counts the rejected connection or request
+func rejectPeer(peer string, err error) {
+	log.WithFields(log.Fields{"type": consts.NetworkError, "peer": peer, "error": err}).Debug("peer is rejected")
+	switch err {
+	case limiter.ErrBanned:
+		incCounter("rejected.banned")
+	case limiter.ErrConnections:
+		incCounter("rejected.connections")
+	case limiter.ErrRate:
+		incCounter("rejected.rate")
+	}
+}
+
+// allowRequest checks the request rate of all keys of the peer
+func allowRequest(peers []string) bool {
+	for _, peer := range peers {
+		if err := limits.Request(peer); err != nil {
+			rejectPeer(peer, err)
+			return false
+		}
+	}
+	return true
+}
+
+// failPeer registers the malformed request, the peer is banned if it sends them too often
+func failPeer(peers []string) {
+	incCounter("malformed")
+	for _, peer := range peers {
+		if limits.Fail(peer) {
+			log.WithFields(log.Fields{"type": consts.NetworkError, "peer": peer}).Warning("peer is banned for malformed requests")
+			incCounter("bans")
+		}
+	}
+}
+
+// readRequest reads the request, the errors of decoding are considered as malformed data
+func readRequest(req network.SelfReaderWriter, r io.Reader) error {
+	return malformed(req.Read(r))
+}
@@ -29,10 +29,13 @@
 package tcpserver
 
 import (
+	"errors"
 	"net"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/AplaProject/go-apla/packages/conf"
 	"github.com/AplaProject/go-apla/packages/consts"
 	"github.com/AplaProject/go-apla/packages/network"
 	"github.com/AplaProject/go-apla/packages/service"
@@ -40,16 +43,86 @@ import (
 	log "github.com/sirupsen/logrus"
 )
 
+var errUnknownRequest = errors.New("Unknown request type")
+
 // HandleTCPRequest proceed TCP requests
-func HandleTCPRequest(rw net.Conn) {
+func HandleTCPRequest(conn net.Conn) {
+	var (
+		handshake *network.HandshakeResponse
+		secured   bool
+		rw        = conn
+		peers     = []string{ipPeer(conn.RemoteAddr())}
+	)
 	dType := &network.RequestType{}
-	err := dType.Read(rw)
-	if err != nil {
-		log.Errorf("read request type failed: %s", err)
+	for {
+		if err := dType.Read(rw); err != nil {
+			log.Errorf("read request type failed: %s", err)
+			return
+		}
+		if !allowRequest(peers) {
+			return
+		}
+		if handshake != nil && !handshake.Supports(dType.Type) {
+			log.WithFields(log.Fields{"type": consts.NetworkError, "request_type": dType.Type,
+				"version": handshake.Version}).Warning("request type has not been negotiated")
+			failPeer(peers)
+			return
+		}
+		switch dType.Type {
+		case network.RequestTypeHandshake:
+			req := &network.HandshakeRequest{}
+			if handshake != nil {
+				failPeer(peers)
+				return
+			}
+			if err := readRequest(req, rw); err != nil {
+				if isMalformed(err) {
+					failPeer(peers)
+				}
+				return
+			}
+			resp := Type11(req)
+			if err := resp.Write(rw); err != nil || !resp.Accepted {
+				return
+			}
+			handshake = resp
+			continue
+
+		case network.RequestTypeSecure:
+			if handshake == nil || secured {
+				failPeer(peers)
+				return
+			}
+			sconn, err := Type12(rw)
+			if err != nil {
+				log.WithFields(log.Fields{"type": consts.NetworkError, "error": err}).Warning("secure session failed")
+				if isMalformed(err) {
+					failPeer(peers)
+				}
+				return
+			}
+			node := nodePeer(sconn.KeyID)
+			if err = limits.Connect(node); err != nil {
+				rejectPeer(node, err)
+				return
+			}
+			defer limits.Disconnect(node)
+			rw, secured = sconn, true
+			peers = append(peers, node)
+			continue
+		}
+		break
+	}
+
+	if !secured && conf.Config.RejectUnknownNodes {
+		log.WithFields(log.Fields{"type": consts.NetworkError, "request_type": dType.Type,
+			"host": conn.RemoteAddr()}).Warning("refused unsecured request")
 		return
 	}
 
+	var err error
 	log.WithFields(log.Fields{"request_type": dType.Type}).Debug("tcpserver got request type")
+	incCounter("requests." + strconv.Itoa(int(dType.Type)))
 	var response interface{}
 
 	switch dType.Type {
@@ -67,7 +140,7 @@ func HandleTCPRequest(rw net.Conn) {
 
 	case network.RequestTypeStopNetwork:
 		req := &network.StopNetworkRequest{}
-		if err = req.Read(rw); err == nil {
+		if err = readRequest(req, rw); err == nil {
 			err = Type3(req, rw)
 		}
 
@@ -77,20 +150,43 @@ func HandleTCPRequest(rw net.Conn) {
 		}
 
 		req := &network.ConfirmRequest{}
-		if err = req.Read(rw); err == nil {
+		if err = readRequest(req, rw); err == nil {
 			response, err = Type4(req)
 		}
 
+	case network.RequestTypeSignedConfirmation:
+		if service.IsNodePaused() {
+			return
+		}
+
+		req := &network.ConfirmRequest{}
+		if err = readRequest(req, rw); err == nil {
+			response, err = Type14(req)
+		}
+
 	case network.RequestTypeBlockCollection:
 		req := &network.GetBodiesRequest{}
-		if err = req.Read(rw); err == nil {
+		if err = readRequest(req, rw); err == nil {
 			err = Type7(req, rw)
 		}
 
 	case network.RequestTypeMaxBlock:
 		response, err = Type10()
+
+	case network.RequestTypePeers:
+		req := &network.PeersRequest{}
+		if err = readRequest(req, rw); err == nil {
+			response = Type13(req, conn.RemoteAddr())
+		}
+
+	default:
+		log.WithFields(log.Fields{"type": consts.NetworkError, "request_type": dType.Type}).Warning("unknown request type")
+		err = malformed(errUnknownRequest)
 	}
 
+	if isMalformed(err) {
+		failPeer(peers)
+	}
 	if err != nil || response == nil {
 		return
 	}
@@ -115,6 +211,8 @@ func TcpListener(laddr string) error {
 		return err
 	}
 
+	initLimits()
+
 	go func() {
 		defer l.Close()
 		for {
@@ -122,12 +220,22 @@ func TcpListener(laddr string) error {
 			if err != nil {
 				log.WithFields(log.Fields{"type": consts.ConnectionError, "error": err, "host": laddr}).Error("Error accepting")
 				time.Sleep(time.Second)
-			} else {
-				go func(conn net.Conn) {
-					HandleTCPRequest(conn)
-					conn.Close()
-				}(conn)
+				continue
+			}
+
+			peer := ipPeer(conn.RemoteAddr())
+			if err = limits.Connect(peer); err != nil {
+				rejectPeer(peer, err)
+				conn.Close()
+				continue
 			}
+			incCounter("connections")
+
+			go func(conn net.Conn) {
+				HandleTCPRequest(conn)
+				conn.Close()
+				limits.Disconnect(peer)
+			}(conn)
 		}
 	}()
 
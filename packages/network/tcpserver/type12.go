@@ -0,0 +1,75 @@
+// Apla Software includes an integrated development
+// environment with a multi-level system for the management
+// of access rights to data, interfaces, and Smart contracts. The
+// technical characteristics of the Apla Software are indicated in
+// Apla Technical Paper.
+
+// Apla Users are granted a permission to deal in the Apla
+// Software without restrictions, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of Apla Software, and to permit persons
+// to whom Apla Software is furnished to do so, subject to the
+// following conditions:
+// * the copyright notice of GenesisKernel and EGAAS S.A.
+// and this permission notice shall be included in all copies or
+// substantial portions of the software;
+// * a result of the dealing in Apla Software cannot be
+// implemented outside of the Apla Platform environment.
+
+// THE APLA SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY
+// OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED
+// TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE, ERROR FREE AND NONINFRINGEMENT. IN
+// NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR
+// THE USE OR OTHER DEALINGS IN THE APLA SOFTWARE.
+
+package tcpserver
+
+import (
+	"bytes"
+	"errors"
+	"net"
+
+	"github.com/AplaProject/go-apla/packages/conf"
+	"github.com/AplaProject/go-apla/packages/conf/syspar"
+	"github.com/AplaProject/go-apla/packages/consts"
+	"github.com/AplaProject/go-apla/packages/network"
+	"github.com/AplaProject/go-apla/packages/signer"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	errUnknownNode    = errors.New("Node isn't a full node")
+	errWrongNodeKey   = errors.New("Public key doesn't match the full node")
+	errNodeIDMismatch = errors.New("Full node id doesn't match the secure session")
+)
+
+// verifyNode checks the key of the connected node against the full_nodes parameter
+func verifyNode(keyID int64, publicKey []byte) error {
+	node := syspar.GetNode(keyID)
+	if node == nil {
+		if conf.Config.RejectUnknownNodes {
+			log.WithFields(log.Fields{"type": consts.NetworkError, "key_id": keyID}).Warning("refused unknown node")
+			return errUnknownNode
+		}
+		return nil
+	}
+	if !bytes.Equal(node.PublicKey, publicKey) {
+		log.WithFields(log.Fields{"type": consts.NetworkError, "key_id": keyID}).Warning("refused node with wrong public key")
+		return errWrongNodeKey
+	}
+	return nil
+}
+
+// Type12 establishes the authenticated encrypted session with the connected node
+func Type12(rw net.Conn) (*network.SecureConn, error) {
+	sconn, err := network.SecureServer(rw, conf.Config.KeyID, signer.Node(), verifyNode)
+	if err != nil {
+		return nil, malformed(err)
+	}
+	return sconn, nil
+}
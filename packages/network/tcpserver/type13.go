@@ -0,0 +1,73 @@
+// Apla Software includes an integrated development
+// environment with a multi-level system for the management
+// of access rights to data, interfaces, and Smart contracts. The
+// technical characteristics of the Apla Software are indicated in
+// Apla Technical Paper.
+
+// Apla Users are granted a permission to deal in the Apla
+// Software without restrictions, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of Apla Software, and to permit persons
+// to whom Apla Software is furnished to do so, subject to the
+// following conditions:
+// * the copyright notice of GenesisKernel and EGAAS S.A.
+// and this permission notice shall be included in all copies or
+// substantial portions of the software;
+// * a result of the dealing in Apla Software cannot be
+// implemented outside of the Apla Platform environment.
+
+// THE APLA SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY
+// OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED
+// TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE, ERROR FREE AND NONINFRINGEMENT. IN
+// NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR
+// THE USE OR OTHER DEALINGS IN THE APLA SOFTWARE.
+
+package tcpserver
+
+import (
+	"net"
+
+	"github.com/AplaProject/go-apla/packages/network"
+	"github.com/AplaProject/go-apla/packages/network/peers"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Type13 adds the requesting node to the table of peers and returns the known peers
+func Type13(req *network.PeersRequest, remote net.Addr) *network.PeersResponse {
+	address := peerAddress(req.Address, remote)
+	if len(address) > 0 && peers.GetTable().Add(address) {
+		log.WithFields(log.Fields{"address": address}).Debug("new peer")
+	}
+
+	resp := &network.PeersResponse{Peers: make([]string, 0, network.MaxPeersResponse)}
+	for _, host := range peers.GetTable().Hosts(network.MaxPeersResponse + 1) {
+		if host != address && len(resp.Peers) < network.MaxPeersResponse {
+			resp.Peers = append(resp.Peers, host)
+		}
+	}
+	return resp
+}
+
+// peerAddress returns the address of the node which consists of the remote IP address of
+// the connection and the announced port. The announced host is ignored so the node can't add
+// the address of another host to the table of peers.
+func peerAddress(address string, remote net.Addr) string {
+	_, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(remote.String())
+	if err != nil {
+		return ""
+	}
+	address = net.JoinHostPort(host, port)
+	if peers.CheckAddress(address) != nil {
+		return ""
+	}
+	return address
+}
@@ -51,7 +51,7 @@ import (
 // Type2 serves requests from disseminator
 func Type2(rw io.ReadWriter) (*network.DisTrResponse, error) {
 	r := &network.DisRequest{}
-	if err := r.Read(rw); err != nil {
+	if err := readRequest(r, rw); err != nil {
 		return nil, err
 	}
 
@@ -64,18 +64,18 @@ func Type2(rw io.ReadWriter) (*network.DisTrResponse, error) {
 
 	if int64(len(binaryData)) > syspar.GetMaxTxSize() {
 		log.WithFields(log.Fields{"type": consts.ParameterExceeded, "max_size": syspar.GetMaxTxSize(), "size": len(binaryData)}).Error("transaction size exceeds max size")
-		return nil, utils.ErrInfo("len(txBinData) > max_tx_size")
+		return nil, malformed(utils.ErrInfo("len(txBinData) > max_tx_size"))
 	}
 
 	if len(binaryData) < 5 {
 		log.WithFields(log.Fields{"type": consts.ProtocolError, "len": len(binaryData), "should_be_equal": 5}).Error("binary data slice has incorrect length")
-		return nil, utils.ErrInfo("len(binaryData) < 5")
+		return nil, malformed(utils.ErrInfo("len(binaryData) < 5"))
 	}
 
 	tx := transaction.RawTransaction{}
 	if err = tx.Unmarshall(bytes.NewBuffer(decryptedBinData)); err != nil {
 		log.WithFields(log.Fields{"type": consts.UnmarshallingError, "error": err}).Error("unmarshalling transaction")
-		return nil, err
+		return nil, malformed(err)
 	}
 
 	_, err = model.DeleteQueueTxByHash(nil, tx.Hash())
@@ -107,7 +107,8 @@ func DecryptData(binaryTx *[]byte) ([]byte, []byte, []byte, error) {
 	// remove the encrypted key, and all that stay in $binary_tx will be encrypted keys of the transactions/blocks
 	length, err := converter.DecodeLength(&*binaryTx)
 	if err != nil {
-		log.WithFields(log.Fields{"type": consts.ProtocolError, "error": err}).Fatal("Decoding binary tx length")
+		log.WithFields(log.Fields{"type": consts.ProtocolError, "error": err}).Error("Decoding binary tx length")
+		return nil, nil, nil, utils.ErrInfo(err)
 	}
 	encryptedKey := converter.BytesShift(&*binaryTx, length)
 	iv := converter.BytesShift(&*binaryTx, 16)
@@ -110,7 +110,7 @@ func AddUser(userID, systemID int64) {
 	systemUsers[systemID] = val
 }
 
-// UpdateNotifications send stats about unreaded messages to centrifugo for ecosystem
+// UpdateNotifications send stats about unreaded messages to publisher for ecosystem
 func UpdateNotifications(ecosystemID int64, users []int64) {
 
 	notificationsStats, err := getEcosystemNotificationStats(ecosystemID, users)
@@ -147,7 +147,7 @@ func UpdateNotifications(ecosystemID int64, users []int64) {
 	}
 }
 
-// UpdateRolesNotifications send stats about unreaded messages to centrifugo for ecosystem
+// UpdateRolesNotifications send stats about unreaded messages to publisher for ecosystem
 func UpdateRolesNotifications(ecosystemID int64, roles []int64) {
 	members, _ := model.GetRoleMembers(nil, ecosystemID, roles)
 	UpdateNotifications(ecosystemID, members)
@@ -163,7 +163,7 @@ func getEcosystemNotificationStats(ecosystemID int64, users []int64) (map[int64]
 	return parseRecipientNotification(result, ecosystemID), nil
 }
 
-// SendNotifications send stats about unreaded messages to centrifugo
+// SendNotifications send stats about unreaded messages to publisher
 func SendNotifications() {
 	for ecosystemID, users := range systemUsers {
 		UpdateNotifications(ecosystemID, *users)
@@ -244,11 +244,11 @@ func sendUserStats(user int64, stats []notificationRecord) {
 
 	ok, err := publisher.Write(user, string(rawStats))
 	if err != nil {
-		log.WithFields(log.Fields{"type": consts.IOError, "error": err}).Error("writing to centrifugo")
+		log.WithFields(log.Fields{"type": consts.IOError, "error": err}).Error("writing to publisher")
 	}
 
 	if !ok {
-		log.WithFields(log.Fields{"type": consts.CentrifugoError, "error": err}).Error("writing to centrifugo")
+		log.WithFields(log.Fields{"type": consts.PublisherError, "error": err}).Error("writing to publisher")
 	}
 }
 
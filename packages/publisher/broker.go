@@ -0,0 +1,104 @@
+// Apla Software includes an integrated development
+// environment with a multi-level system for the management
+// of access rights to data, interfaces, and Smart contracts. The
+// technical characteristics of the Apla Software are indicated in
+// Apla Technical Paper.
+
+// Apla Users are granted a permission to deal in the Apla
+// Software without restrictions, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of Apla Software, and to permit persons
+// to whom Apla Software is furnished to do so, subject to the
+// following conditions:
+// * the copyright notice of GenesisKernel and EGAAS S.A.
+// and this permission notice shall be included in all copies or
+// substantial portions of the software;
+// * a result of the dealing in Apla Software cannot be
+// implemented outside of the Apla Platform environment.
+
+// THE APLA SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY
+// OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED
+// TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE, ERROR FREE AND NONINFRINGEMENT. IN
+// NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR
+// THE USE OR OTHER DEALINGS IN THE APLA SOFTWARE.
+
+package publisher
+
+import "sync"
+
+const brokerBufferSize = 100
+
+// Message is a notification for the user
+type Message struct {
+	UserID int64
+	Data   string
+}
+
+// Subscription receives messages of the user from the broker
+type Subscription struct {
+	Messages <-chan Message
+	messages chan Message
+	userID   int64
+}
+
+// Broker is the in-process publisher which delivers messages to the subscribers
+type Broker struct {
+	mu   sync.Mutex
+	subs map[int64]map[*Subscription]struct{}
+}
+
+// NewBroker returns new in-process broker
+func NewBroker() *Broker {
+	return &Broker{subs: make(map[int64]map[*Subscription]struct{})}
+}
+
+// Subscribe adds the subscriber to messages of the user
+func (b *Broker) Subscribe(userID int64) *Subscription {
+	ch := make(chan Message, brokerBufferSize)
+	s := &Subscription{Messages: ch, messages: ch, userID: userID}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.subs[userID] == nil {
+		b.subs[userID] = make(map[*Subscription]struct{})
+	}
+	b.subs[userID][s] = struct{}{}
+	return s
+}
+
+// Unsubscribe removes the subscriber
+func (b *Broker) Unsubscribe(s *Subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	subs, ok := b.subs[s.userID]
+	if !ok {
+		return
+	}
+	if _, ok = subs[s]; !ok {
+		return
+	}
+	delete(subs, s)
+	if len(subs) == 0 {
+		delete(b.subs, s.userID)
+	}
+	close(s.messages)
+}
+
+// Write sends data to the subscribers of the user, messages are dropped for slow subscribers
+func (b *Broker) Write(userID int64, data string) (bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for s := range b.subs[userID] {
+		select {
+		case s.messages <- Message{UserID: userID, Data: data}:
+		default:
+		}
+	}
+	return true, nil
+}
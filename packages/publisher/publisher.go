@@ -30,7 +30,7 @@ package publisher
 
 import (
 	"encoding/hex"
-	"fmt"
+	"errors"
 	"strconv"
 	"sync"
 	"time"
@@ -42,6 +42,28 @@ import (
 	log "github.com/sirupsen/logrus"
 )
 
+// Types of publishers
+const (
+	TypeCentrifugo = "centrifugo"
+	TypeLocal      = "local"
+	TypeWebhook    = "webhook"
+)
+
+var (
+	// ErrNotInitialized is returned if the publisher has not been initialized
+	ErrNotInitialized = errors.New("publisher not initialized")
+	// ErrNotCentrifugo is returned if centrifugo isn't the current publisher
+	ErrNotCentrifugo = errors.New("publisher is not centrifugo")
+	// ErrUnknownType is returned if the type of publisher is unknown
+	ErrUnknownType = errors.New("unknown type of publisher")
+)
+
+// Publisher delivers notifications to the channels of clients
+type Publisher interface {
+	// Write sends data to the channel of the user
+	Write(userID int64, data string) (bool, error)
+}
+
 type ClientsChannels struct {
 	storage map[int64]string
 	sync.RWMutex
@@ -62,14 +84,37 @@ func (cn *ClientsChannels) Get(id int64) string {
 var (
 	clientsChannels   = ClientsChannels{storage: make(map[int64]string)}
 	centrifugoTimeout = time.Second * 5
-	publisher         *gocent.Client
+	publisher         Publisher
 	config            conf.CentrifugoConfig
+	// broker always gets a copy of notifications for the in-process subscribers
+	broker = NewBroker()
 )
 
-// InitCentrifugo client
-func InitCentrifugo(cfg conf.CentrifugoConfig) {
-	config = cfg
-	publisher = gocent.NewClient(cfg.URL, cfg.Secret, centrifugoTimeout)
+// Init creates the publisher of the type from config
+func Init(cfg conf.PublisherConfig, centrifugoCfg conf.CentrifugoConfig) error {
+	config = centrifugoCfg
+
+	switch cfg.Type {
+	case TypeCentrifugo, "":
+		publisher = NewCentrifugo(centrifugoCfg)
+	case TypeLocal:
+		publisher = broker
+	case TypeWebhook:
+		if err := CheckWebhookURL(cfg.WebhookURL); err != nil {
+			log.WithFields(log.Fields{"type": consts.ConfigError, "url": cfg.WebhookURL}).Error("invalid webhook url")
+			return err
+		}
+		publisher = NewWebhook(cfg.WebhookURL, cfg.WebhookSecret, time.Duration(cfg.Timeout)*time.Second)
+	default:
+		log.WithFields(log.Fields{"type": consts.ConfigError, "publisher": cfg.Type}).Error("unknown type of publisher")
+		return ErrUnknownType
+	}
+	return nil
+}
+
+// GetBroker returns the in-process broker
+func GetBroker() *Broker {
+	return broker
 }
 
 func GetHMACSign(userID int64) (string, string, error) {
@@ -88,14 +133,28 @@ func GetHMACSign(userID int64) (string, string, error) {
 
 // Write is publishing data to server
 func Write(userID int64, data string) (bool, error) {
-	return publisher.Publish("client"+strconv.FormatInt(userID, 10), []byte(data))
+	if publisher == nil {
+		return false, ErrNotInitialized
+	}
+	if publisher != broker {
+		broker.Write(userID, data)
+	}
+	return publisher.Write(userID, data)
 }
 
 // GetStats returns Stats
 func GetStats() (gocent.Stats, error) {
 	if publisher == nil {
-		return gocent.Stats{}, fmt.Errorf("publisher not initialized")
+		return gocent.Stats{}, ErrNotInitialized
+	}
+
+	cent, ok := publisher.(*Centrifugo)
+	if !ok {
+		return gocent.Stats{}, ErrNotCentrifugo
 	}
+	return cent.Stats()
+}
 
-	return publisher.Stats()
+func clientChannel(userID int64) string {
+	return "client" + strconv.FormatInt(userID, 10)
 }
@@ -0,0 +1,114 @@
+// Apla Software includes an integrated development
+// environment with a multi-level system for the management
+// of access rights to data, interfaces, and Smart contracts. The
+// technical characteristics of the Apla Software are indicated in
+// Apla Technical Paper.
+
+// Apla Users are granted a permission to deal in the Apla
+// Software without restrictions, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of Apla Software, and to permit persons
+// to whom Apla Software is furnished to do so, subject to the
+// following conditions:
+// * the copyright notice of GenesisKernel and EGAAS S.A.
+// and this permission notice shall be included in all copies or
+// substantial portions of the software;
+// * a result of the dealing in Apla Software cannot be
+// implemented outside of the Apla Platform environment.
+
+// THE APLA SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY
+// OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED
+// TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE, ERROR FREE AND NONINFRINGEMENT. IN
+// NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR
+// THE USE OR OTHER DEALINGS IN THE APLA SOFTWARE.
+
+package publisher
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/AplaProject/go-apla/packages/conf"
+	"github.com/AplaProject/go-apla/packages/crypto"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBroker(t *testing.T) {
+	b := NewBroker()
+	s1 := b.Subscribe(1)
+	s2 := b.Subscribe(2)
+
+	ok, err := b.Write(1, "data")
+	assert.True(t, ok)
+	assert.NoError(t, err)
+
+	assert.Equal(t, Message{UserID: 1, Data: "data"}, <-s1.Messages)
+	assert.Len(t, s2.Messages, 0)
+
+	b.Unsubscribe(s1)
+	_, ok = <-s1.Messages
+	assert.False(t, ok)
+	b.Unsubscribe(s1)
+}
+
+func TestWebhook(t *testing.T) {
+	var (
+		msg  webhookMessage
+		sign string
+	)
+	done := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		json.Unmarshal(body, &msg)
+		mac, _ := crypto.GetHMAC("secret", string(body))
+		sign = hex.EncodeToString(mac)
+		assert.Equal(t, sign, r.Header.Get(webhookSignHeader))
+		close(done)
+	}))
+	defer srv.Close()
+
+	wh := NewWebhook(srv.URL, "secret", time.Second)
+	defer wh.Close()
+	ok, err := wh.Write(5, `[{"ecosystem":1,"role_id":1,"count":2}]`)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("webhook hasn't been called")
+	}
+	assert.Equal(t, "client5", msg.Channel)
+	assert.Equal(t, int64(5), msg.UserID)
+	assert.JSONEq(t, `[{"ecosystem":1,"role_id":1,"count":2}]`, string(msg.Data))
+	assert.NotEmpty(t, sign)
+}
+
+func TestInit(t *testing.T) {
+	require.NoError(t, Init(conf.PublisherConfig{Type: TypeLocal}, conf.CentrifugoConfig{}))
+	s := GetBroker().Subscribe(3)
+	defer GetBroker().Unsubscribe(s)
+
+	ok, err := Write(3, "{}")
+	assert.True(t, ok)
+	assert.NoError(t, err)
+	assert.Equal(t, "{}", (<-s.Messages).Data)
+
+	_, err = GetStats()
+	assert.Equal(t, ErrNotCentrifugo, err)
+
+	assert.Equal(t, ErrUnknownType, Init(conf.PublisherConfig{Type: "unknown"}, conf.CentrifugoConfig{}))
+	assert.Equal(t, ErrWebhookURL, Init(conf.PublisherConfig{Type: TypeWebhook}, conf.CentrifugoConfig{}))
+	assert.Equal(t, ErrWebhookURL, Init(conf.PublisherConfig{Type: TypeWebhook, WebhookURL: "localhost:8080"}, conf.CentrifugoConfig{}))
+}
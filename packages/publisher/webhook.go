@@ -0,0 +1,166 @@
+// Apla Software includes an integrated development
+// environment with a multi-level system for the management
+// of access rights to data, interfaces, and Smart contracts. The
+// technical characteristics of the Apla Software are indicated in
+// Apla Technical Paper.
+
+// Apla Users are granted a permission to deal in the Apla
+// Software without restrictions, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of Apla Software, and to permit persons
+// to whom Apla Software is furnished to do so, subject to the
+// following conditions:
+// * the copyright notice of GenesisKernel and EGAAS S.A.
+// and this permission notice shall be included in all copies or
+// substantial portions of the software;
+// * a result of the dealing in Apla Software cannot be
+// implemented outside of the Apla Platform environment.
+
+// THE APLA SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY
+// OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED
+// TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE, ERROR FREE AND NONINFRINGEMENT. IN
+// NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR
+// THE USE OR OTHER DEALINGS IN THE APLA SOFTWARE.
+
+package publisher
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/AplaProject/go-apla/packages/consts"
+	"github.com/AplaProject/go-apla/packages/crypto"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	webhookSignHeader     = "X-Apla-Signature"
+	defaultWebhookTimeout = 5 * time.Second
+	webhookQueueSize      = 1000
+)
+
+var (
+	// ErrWebhookURL is returned if the url of the webhook is empty or invalid
+	ErrWebhookURL = errors.New("invalid webhook url")
+	// ErrWebhookQueueFull is returned if the webhook can't keep up with notifications
+	ErrWebhookQueueFull = errors.New("webhook queue is full")
+)
+
+type webhookMessage struct {
+	Channel string          `json:"channel"`
+	UserID  int64           `json:"user_id"`
+	Data    json.RawMessage `json:"data"`
+}
+
+// Webhook posts notifications to the http endpoint.
+// Notifications are queued and posted by the background worker
+// so that the slow endpoint doesn't delay the caller.
+type Webhook struct {
+	url    string
+	secret string
+	client *http.Client
+	queue  chan []byte
+}
+
+// CheckWebhookURL returns an error if the url isn't an absolute http(s) url
+func CheckWebhookURL(webhookURL string) error {
+	u, err := url.Parse(webhookURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || len(u.Host) == 0 {
+		return ErrWebhookURL
+	}
+	return nil
+}
+
+// NewWebhook returns new webhook publisher. If secret is specified
+// then the body is signed by HMAC and the sign is sent in X-Apla-Signature header
+func NewWebhook(url, secret string, timeout time.Duration) *Webhook {
+	if timeout <= 0 {
+		timeout = defaultWebhookTimeout
+	}
+	wh := &Webhook{
+		url:    url,
+		secret: secret,
+		client: &http.Client{Timeout: timeout},
+		queue:  make(chan []byte, webhookQueueSize),
+	}
+	go wh.run()
+	return wh
+}
+
+// Write queues data of the user for posting to the webhook
+func (wh *Webhook) Write(userID int64, data string) (bool, error) {
+	msg := webhookMessage{
+		Channel: clientChannel(userID),
+		UserID:  userID,
+		Data:    json.RawMessage(data),
+	}
+	if !json.Valid(msg.Data) {
+		raw, err := json.Marshal(data)
+		if err != nil {
+			return false, err
+		}
+		msg.Data = raw
+	}
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return false, err
+	}
+
+	select {
+	case wh.queue <- body:
+		return true, nil
+	default:
+		log.WithFields(log.Fields{"type": consts.NetworkError, "user_id": userID}).Warn("webhook queue is full, dropping notification")
+		return false, ErrWebhookQueueFull
+	}
+}
+
+// Close stops the worker after the queued notifications have been posted
+func (wh *Webhook) Close() {
+	close(wh.queue)
+}
+
+func (wh *Webhook) run() {
+	for body := range wh.queue {
+		if err := wh.post(body); err != nil {
+			log.WithFields(log.Fields{"type": consts.NetworkError, "error": err, "url": wh.url}).Error("posting notification to webhook")
+		}
+	}
+}
+
+func (wh *Webhook) post(body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, wh.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if len(wh.secret) > 0 {
+		sign, err := crypto.GetHMAC(wh.secret, string(body))
+		if err != nil {
+			return err
+		}
+		req.Header.Set(webhookSignHeader, hex.EncodeToString(sign))
+	}
+
+	resp, err := wh.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -77,8 +77,12 @@ func RollbackBlock(data []byte, deleteBlock bool) error {
 		}
 	}
 
-	err = dbTransaction.Commit()
-	return err
+	if err = dbTransaction.Commit(); err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("committing rollback of block")
+		return err
+	}
+	block.PublishRollback()
+	return nil
 }
 
 func rollbackBlock(dbTransaction *model.DbTransaction, block *block.Block) error {
@@ -98,6 +102,11 @@ func rollbackBlock(dbTransaction *model.DbTransaction, block *block.Block) error
 			logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("deleting log transactions by hash")
 			return err
 		}
+		_, err = model.DeleteEventsByHash(dbTransaction, t.TxHash)
+		if err != nil {
+			logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("deleting events by hash")
+			return err
+		}
 
 		ts := &model.TransactionStatus{}
 		err = ts.UpdateBlockID(dbTransaction, 0, t.TxHash)
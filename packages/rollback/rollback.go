@@ -32,6 +32,7 @@ import (
 	"bytes"
 	"strconv"
 
+	"github.com/AplaProject/go-apla/packages/block"
 	"github.com/AplaProject/go-apla/packages/consts"
 	"github.com/AplaProject/go-apla/packages/converter"
 	"github.com/AplaProject/go-apla/packages/model"
@@ -42,6 +43,9 @@ import (
 
 // ToBlockID rollbacks blocks till blockID
 func ToBlockID(blockID int64, dbTransaction *model.DbTransaction, logger *log.Entry) error {
+	if err := block.CheckSnapshotRollback(blockID + 1); err != nil {
+		return err
+	}
 	_, err := model.MarkVerifiedAndNotUsedTransactionsUnverified()
 	if err != nil {
 		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("marking verified and not used transactions unverified")
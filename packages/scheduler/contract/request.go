@@ -31,7 +31,6 @@ package contract
 import (
 	"encoding/hex"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -43,7 +42,7 @@ import (
 	"github.com/AplaProject/go-apla/packages/consts"
 	"github.com/AplaProject/go-apla/packages/converter"
 	"github.com/AplaProject/go-apla/packages/crypto"
-	"github.com/AplaProject/go-apla/packages/utils"
+	"github.com/AplaProject/go-apla/packages/signer"
 
 	log "github.com/sirupsen/logrus"
 )
@@ -71,9 +70,9 @@ type contractResult struct {
 // The transaction is signed with a node key.
 func NodeContract(Name string) (result contractResult, err error) {
 	var (
-		sign                          []byte
-		ret                           authResult
-		NodePrivateKey, NodePublicKey string
+		sign          []byte
+		ret           authResult
+		NodePublicKey []byte
 	)
 	err = sendAPIRequest(`GET`, `getuid`, nil, &ret, ``)
 	if err != nil {
@@ -84,20 +83,17 @@ func NodeContract(Name string) (result contractResult, err error) {
 		err = fmt.Errorf(`getuid has returned empty uid`)
 		return
 	}
-	NodePrivateKey, NodePublicKey, err = utils.GetNodeKeys()
-	if err != nil || len(NodePrivateKey) == 0 {
-		if err == nil {
-			log.WithFields(log.Fields{"type": consts.EmptyObject}).Error("node private key is empty")
-			err = errors.New(`empty node private key`)
-		}
+	nodeSigner := signer.Node()
+	NodePublicKey, err = nodeSigner.PublicKey()
+	if err != nil {
 		return
 	}
-	sign, err = crypto.SignString(NodePrivateKey, ret.UID)
+	sign, err = nodeSigner.Sign([]byte(ret.UID))
 	if err != nil {
 		log.WithFields(log.Fields{"type": consts.CryptoError, "error": err}).Error("signing node uid")
 		return
 	}
-	form := url.Values{"pubkey": {NodePublicKey}, "signature": {hex.EncodeToString(sign)},
+	form := url.Values{"pubkey": {crypto.PubToHex(NodePublicKey)}, "signature": {hex.EncodeToString(sign)},
 		`ecosystem`: {converter.Int64ToStr(1)}}
 	var logret authResult
 	err = sendAPIRequest(`POST`, `login`, &form, &logret, auth)
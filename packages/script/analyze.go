@@ -0,0 +1,436 @@
+// Apla Software includes an integrated development
+// environment with a multi-level system for the management
+// of access rights to data, interfaces, and Smart contracts. The
+// technical characteristics of the Apla Software are indicated in
+// Apla Technical Paper.
+
+// Apla Users are granted a permission to deal in the Apla
+// Software without restrictions, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of Apla Software, and to permit persons
+// to whom Apla Software is furnished to do so, subject to the
+// following conditions:
+// * the copyright notice of GenesisKernel and EGAAS S.A.
+// and this permission notice shall be included in all copies or
+// substantial portions of the software;
+// * a result of the dealing in Apla Software cannot be
+// implemented outside of the Apla Platform environment.
+
+// THE APLA SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY
+// OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED
+// TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE, ERROR FREE AND NONINFRINGEMENT. IN
+// NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR
+// THE USE OR OTHER DEALINGS IN THE APLA SOFTWARE.
+
+package script
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Codes of the warnings which are reported by Analyze
+const (
+	WarnUnusedVar   = `unused-var`
+	WarnUnreachable = `unreachable`
+	WarnShadowed    = `shadowed`
+	WarnCondWrite   = `cond-write`
+	WarnLoopDB      = `loop-db`
+)
+
+const (
+	frameBlock = iota
+	frameContract
+	frameFunc
+	frameData
+	frameSettings
+	frameWhile
+	frameLiteral
+)
+
+// Warning is a problem found by the static analysis of the contract source
+type Warning struct {
+	Line   uint32 `json:"line"`
+	Column uint32 `json:"column"`
+	Code   string `json:"code"`
+	Text   string `json:"text"`
+}
+
+type anVar struct {
+	lex   *Lexem
+	used  bool
+	param bool
+}
+
+type anFrame struct {
+	kind   int
+	name   string
+	block  *Block
+	vars   map[string]*anVar
+	fields map[string]bool
+}
+
+type analyzer struct {
+	vm       *VM
+	root     *Block
+	state    uint32
+	lexems   Lexems
+	frames   []*anFrame
+	pending  *anFrame // the frame which will be opened by the next curly bracket
+	header   bool     // parameters of the function are being parsed
+	inVar    bool     // variables are being declared
+	term     int      // 1 - inside return, break etc., 2 - after that statement
+	termDeep int
+	termPar  int
+	calls    map[*Block]bool
+	warnings []Warning
+}
+
+// Analyze checks the contract source and returns the list of warnings about the code
+// which is compiled but can fail or work incorrectly at runtime. The error is returned
+// if the source cannot be compiled. Warnings are collected in this case too.
+func (vm *VM) Analyze(source string, owner *OwnerInfo) ([]Warning, error) {
+	lexems, err := lexParser([]rune(source))
+	if err != nil {
+		return nil, err
+	}
+	root, err := vm.CompileBlock([]rune(source), owner)
+	if err != nil {
+		root = nil
+	}
+	a := &analyzer{vm: vm, root: root, state: owner.StateID, lexems: lexems,
+		calls: make(map[*Block]bool)}
+	for i := 0; i < len(lexems); i++ {
+		a.lexem(i)
+	}
+	for len(a.frames) > 0 {
+		a.pop()
+	}
+	sort.SliceStable(a.warnings, func(i, j int) bool {
+		if a.warnings[i].Line == a.warnings[j].Line {
+			return a.warnings[i].Column < a.warnings[j].Column
+		}
+		return a.warnings[i].Line < a.warnings[j].Line
+	})
+	return a.warnings, err
+}
+
+func (a *analyzer) warn(lex *Lexem, code, text string, params ...interface{}) {
+	a.warnings = append(a.warnings, Warning{Line: lex.Line, Column: lex.Column, Code: code,
+		Text: fmt.Sprintf(text, params...)})
+}
+
+func (a *analyzer) lexAt(i int) *Lexem {
+	if i < 0 || i >= len(a.lexems) {
+		return nil
+	}
+	return a.lexems[i]
+}
+
+func (a *analyzer) top() *anFrame {
+	if len(a.frames) == 0 {
+		return nil
+	}
+	return a.frames[len(a.frames)-1]
+}
+
+func (a *analyzer) find(kind int) *anFrame {
+	for i := len(a.frames) - 1; i >= 0; i-- {
+		if a.frames[i].kind == kind {
+			return a.frames[i]
+		}
+	}
+	return nil
+}
+
+// inLoop returns true if the current statement is inside while or for of the current function
+func (a *analyzer) inLoop() bool {
+	for i := len(a.frames) - 1; i >= 0; i-- {
+		switch a.frames[i].kind {
+		case frameWhile:
+			return true
+		case frameFunc:
+			return false
+		}
+	}
+	return false
+}
+
+func (a *analyzer) lookupVar(name string) *anVar {
+	for i := len(a.frames) - 1; i >= 0; i-- {
+		if v, ok := a.frames[i].vars[name]; ok {
+			return v
+		}
+		if a.frames[i].kind == frameFunc {
+			break
+		}
+	}
+	return nil
+}
+
+func (a *analyzer) pop() {
+	frame := a.top()
+	a.frames = a.frames[:len(a.frames)-1]
+	for name, v := range frame.vars {
+		if !v.used && !v.param {
+			a.warn(v.lex, WarnUnusedVar, `variable %s is declared but not used`, name)
+		}
+	}
+}
+
+// unreachable tracks statements which break the execution of the current block
+func (a *analyzer) unreachable(lex *Lexem) {
+	switch a.term {
+	case 1:
+		switch lex.Type {
+		case isLPar, isLBrack:
+			a.termPar++
+		case isRPar, isRBrack:
+			a.termPar--
+		case lexNewLine:
+			if a.termPar == 0 && len(a.frames) == a.termDeep {
+				a.term = 2
+			}
+		case isRCurly:
+			if len(a.frames) == a.termDeep {
+				a.term = 0
+			}
+		}
+	case 2:
+		switch lex.Type {
+		case lexNewLine:
+		case isRCurly:
+			a.term = 0
+		default:
+			a.warn(lex, WarnUnreachable, `unreachable code`)
+			a.term = 0
+		}
+	}
+}
+
+// isAssign returns true if the identifier at i is on the left side of the assignment
+func (a *analyzer) isAssign(i int) bool {
+	for j := i + 1; j < len(a.lexems); j += 2 {
+		switch a.lexems[j].Type {
+		case isEq:
+			return true
+		case isComma:
+			if next := a.lexAt(j + 1); next != nil && (next.Type == lexIdent || next.Type == lexExtend) {
+				continue
+			}
+		}
+		return false
+	}
+	return false
+}
+
+func (a *analyzer) lexem(i int) {
+	lex := a.lexems[i]
+	a.unreachable(lex)
+	switch lex.Type {
+	case isLCurly:
+		frame := a.pending
+		if frame == nil {
+			frame = &anFrame{kind: frameLiteral}
+		}
+		a.pending = nil
+		a.header = false
+		a.frames = append(a.frames, frame)
+	case isRCurly:
+		if len(a.frames) > 0 {
+			a.pop()
+		}
+	case lexNewLine:
+		a.inVar = false
+	case lexType:
+		a.inVar = false
+	case lexKeyword | (keyContract << 8):
+		a.pending = &anFrame{kind: frameContract, fields: make(map[string]bool)}
+		if next := a.lexAt(i + 1); next != nil && next.Type == lexIdent {
+			a.pending.name = next.Value.(string)
+			a.pending.block = a.contractBlock(a.pending.name)
+		}
+	case lexKeyword | (keyFunc << 8):
+		a.pending = &anFrame{kind: frameFunc, vars: make(map[string]*anVar)}
+		if next := a.lexAt(i + 1); next != nil && next.Type == lexIdent {
+			a.pending.name = next.Value.(string)
+		}
+		a.header = true
+	case lexKeyword | (keyTX << 8):
+		a.pending = &anFrame{kind: frameData}
+	case lexKeyword | (keySettings << 8):
+		a.pending = &anFrame{kind: frameSettings}
+	case lexKeyword | (keyWhile << 8), lexKeyword | (keyFor << 8):
+		a.pending = &anFrame{kind: frameWhile, vars: make(map[string]*anVar)}
+	case lexKeyword | (keyIf << 8), lexKeyword | (keyElif << 8), lexKeyword | (keyElse << 8),
+		lexKeyword | (keyTry << 8), lexKeyword | (keyCatch << 8):
+		a.pending = &anFrame{kind: frameBlock, vars: make(map[string]*anVar)}
+	case lexKeyword | (keyVar << 8):
+		a.inVar = true
+	case lexKeyword | (keyReturn << 8), lexKeyword | (keyBreak << 8), lexKeyword | (keyContinue << 8),
+		lexKeyword | (keyError << 8), lexKeyword | (keyWarning << 8), lexKeyword | (keyInfo << 8):
+		if a.find(frameFunc) != nil {
+			a.term = 1
+			a.termDeep = len(a.frames)
+			a.termPar = 0
+		}
+	case lexIdent:
+		a.ident(i)
+	case lexExtend:
+		a.extend(i)
+	}
+}
+
+func (a *analyzer) ident(i int) {
+	lex := a.lexems[i]
+	name := lex.Value.(string)
+	prev := a.lexAt(i - 1)
+	if a.header {
+		if a.pending != nil && prev != nil && prev.Type != isDot && prev.Type != lexKeyword|(keyFunc<<8) {
+			a.pending.vars[name] = &anVar{lex: lex, param: true}
+		}
+		return
+	}
+	top := a.top()
+	if top == nil {
+		return
+	}
+	if top.kind == frameData {
+		if contract := a.find(frameContract); contract != nil {
+			contract.fields[name] = true
+		}
+		return
+	}
+	if top.kind == frameSettings || a.find(frameFunc) == nil {
+		return
+	}
+	if a.inVar {
+		if contract := a.find(frameContract); contract != nil && contract.fields[name] {
+			a.warn(lex, WarnShadowed, `variable %s shadows the contract parameter $%[1]s`, name)
+		}
+		if top.vars == nil {
+			top.vars = make(map[string]*anVar)
+		}
+		top.vars[name] = &anVar{lex: lex}
+		return
+	}
+	if next := a.lexAt(i + 1); next != nil && next.Type == isLPar {
+		a.call(lex, name)
+		return
+	}
+	if prev != nil && prev.Type == isDot {
+		return
+	}
+	if v := a.lookupVar(name); v != nil && !a.isAssign(i) {
+		v.used = true
+	}
+}
+
+func (a *analyzer) extend(i int) {
+	lex := a.lexems[i]
+	if a.find(frameFunc) == nil || !a.isAssign(i) {
+		return
+	}
+	name := lex.Value.(string)
+	if contract := a.find(frameContract); contract != nil && contract.fields[name] {
+		a.warn(lex, WarnShadowed, `assignment to $%s overwrites the contract parameter`, name)
+	}
+}
+
+func (a *analyzer) call(lex *Lexem, name string) {
+	obj := a.findObj(name)
+	if obj == nil {
+		return
+	}
+	if fn := a.find(frameFunc); fn != nil && fn.name == `conditions` && a.find(frameContract) != nil &&
+		(name == `CallContract` || a.canWrite(obj)) {
+		a.warn(lex, WarnCondWrite, `%s can modify the database and cannot be called in conditions`, name)
+	}
+	if a.inLoop() && a.callsDB(obj) {
+		a.warn(lex, WarnLoopDB, `%s queries the database inside the loop`, name)
+	}
+}
+
+func (a *analyzer) contractBlock(name string) *Block {
+	if a.root == nil {
+		return nil
+	}
+	if obj, ok := a.root.Objects[StateName(a.state, name)]; ok && obj.Type == ObjContract {
+		return obj.Value.(*Block)
+	}
+	return nil
+}
+
+func (a *analyzer) findObj(name string) *ObjInfo {
+	if contract := a.find(frameContract); contract != nil && contract.block != nil {
+		if obj, ok := contract.block.Objects[name]; ok {
+			return obj
+		}
+	}
+	if a.root != nil {
+		if obj, ok := a.root.Objects[name]; ok {
+			return obj
+		}
+		if obj, ok := a.root.Objects[StateName(a.state, name)]; ok {
+			return obj
+		}
+	}
+	return a.vm.getObjByNameExt(name, a.state)
+}
+
+func (a *analyzer) canWrite(obj *ObjInfo) bool {
+	switch obj.Type {
+	case ObjExtFunc:
+		return obj.Value.(ExtFuncInfo).CanWrite
+	case ObjFunc:
+		return obj.Value.(*Block).Info.(*FuncInfo).CanWrite
+	case ObjContract:
+		if obj.Value == nil {
+			return true
+		}
+		return obj.Value.(*Block).Info.(*ContractInfo).CanWrite
+	}
+	return false
+}
+
+func (a *analyzer) callsDB(obj *ObjInfo) bool {
+	switch obj.Type {
+	case ObjExtFunc:
+		_, ok := a.vm.FuncCallsDB[obj.Value.(ExtFuncInfo).Name]
+		return ok
+	case ObjFunc:
+		return a.blockCallsDB(obj.Value.(*Block))
+	}
+	return false
+}
+
+// blockCallsDB returns true if the function calls database functions directly or indirectly
+func (a *analyzer) blockCallsDB(block *Block) bool {
+	if ret, ok := a.calls[block]; ok {
+		return ret
+	}
+	a.calls[block] = false
+	ret := false
+	for _, cmd := range block.Code {
+		if cmd.Cmd == cmdCall || cmd.Cmd == cmdCallVari {
+			if obj, ok := cmd.Value.(*ObjInfo); ok && obj.Type != ObjContract && a.callsDB(obj) {
+				ret = true
+				break
+			}
+		}
+	}
+	if !ret {
+		for _, child := range block.Children {
+			if a.blockCallsDB(child) {
+				ret = true
+				break
+			}
+		}
+	}
+	a.calls[block] = ret
+	return ret
+}
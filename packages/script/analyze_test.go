@@ -0,0 +1,116 @@
+// Apla Software includes an integrated development
+// environment with a multi-level system for the management
+// of access rights to data, interfaces, and Smart contracts. The
+// technical characteristics of the Apla Software are indicated in
+// Apla Technical Paper.
+
+// Apla Users are granted a permission to deal in the Apla
+// Software without restrictions, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of Apla Software, and to permit persons
+// to whom Apla Software is furnished to do so, subject to the
+// following conditions:
+// * the copyright notice of GenesisKernel and EGAAS S.A.
+// and this permission notice shall be included in all copies or
+// substantial portions of the software;
+// * a result of the dealing in Apla Software cannot be
+// implemented outside of the Apla Platform environment.
+
+// THE APLA SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY
+// OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED
+// TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE, ERROR FREE AND NONINFRINGEMENT. IN
+// NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR
+// THE USE OR OTHER DEALINGS IN THE APLA SOFTWARE.
+
+package script
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newAnalyzeVM() *VM {
+	vm := NewVM()
+	vm.Extend(&ExtendData{Objects: map[string]interface{}{
+		"DBInsert": func(table string, id int64) int64 { return id },
+		"DBFind":   func(table string) string { return table },
+	}, WriteFuncs: map[string]struct{}{"DBInsert": {}}})
+	vm.FuncCallsDB = map[string]struct{}{"DBFind": {}}
+	return vm
+}
+
+func TestAnalyze(t *testing.T) {
+	vm := newAnalyzeVM()
+	warnings, err := vm.Analyze(`func find(name string) string {
+	return DBFind(name)
+}
+contract Test {
+	data {
+		Name string
+		Amount int
+	}
+	conditions {
+		var Name string
+		var unused int
+		Name = $Name
+		if Name == "" {
+			return
+			Name = "test"
+		}
+	}
+	action {
+		var i int
+		$Amount = 0
+		while i < 3 {
+			DBFind("keys")
+			find("keys")
+			i = i + 1
+		}
+		DBInsert("keys", 1)
+	}
+}`, &OwnerInfo{StateID: 1})
+	require.NoError(t, err)
+	assert.Equal(t, []Warning{
+		{Line: 10, Column: 8, Code: WarnShadowed, Text: `variable Name shadows the contract parameter $Name`},
+		{Line: 11, Column: 8, Code: WarnUnusedVar, Text: `variable unused is declared but not used`},
+		{Line: 15, Column: 5, Code: WarnUnreachable, Text: `unreachable code`},
+		{Line: 20, Column: 4, Code: WarnShadowed, Text: `assignment to $Amount overwrites the contract parameter`},
+		{Line: 22, Column: 5, Code: WarnLoopDB, Text: `DBFind queries the database inside the loop`},
+		{Line: 23, Column: 5, Code: WarnLoopDB, Text: `find queries the database inside the loop`},
+	}, warnings)
+
+	warnings, err = vm.Analyze(`contract Test {
+	conditions {
+		DBInsert("keys", 1)
+	}
+}`, &OwnerInfo{StateID: 1})
+	assert.Error(t, err)
+	require.Len(t, warnings, 1)
+	assert.Equal(t, WarnCondWrite, warnings[0].Code)
+	assert.Equal(t, uint32(3), warnings[0].Line)
+
+	warnings, err = vm.Analyze(`contract Test {
+	data {
+		Name string
+	}
+	action {
+		var name string
+		name = $Name
+		if name == "" {
+			error "empty name"
+		}
+		DBInsert(name, 1)
+	}
+}`, &OwnerInfo{StateID: 1})
+	require.NoError(t, err)
+	assert.Empty(t, warnings)
+
+	_, err = vm.Analyze(`contract Test { action { var a string = "}`, &OwnerInfo{StateID: 1})
+	assert.Error(t, err)
+}
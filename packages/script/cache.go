@@ -0,0 +1,738 @@
+// Apla Software includes an integrated development
+// environment with a multi-level system for the management
+// of access rights to data, interfaces, and Smart contracts. The
+// technical characteristics of the Apla Software are indicated in
+// Apla Technical Paper.
+
+// Apla Users are granted a permission to deal in the Apla
+// Software without restrictions, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of Apla Software, and to permit persons
+// to whom Apla Software is furnished to do so, subject to the
+// following conditions:
+// * the copyright notice of GenesisKernel and EGAAS S.A.
+// and this permission notice shall be included in all copies or
+// substantial portions of the software;
+// * a result of the dealing in Apla Software cannot be
+// implemented outside of the Apla Platform environment.
+
+// THE APLA SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY
+// OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED
+// TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE, ERROR FREE AND NONINFRINGEMENT. IN
+// NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR
+// THE USE OR OTHER DEALINGS IN THE APLA SOFTWARE.
+
+package script
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/AplaProject/go-apla/packages/consts"
+	"github.com/AplaProject/go-apla/packages/types"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// CacheVersion is the version of the serialized blocks. It must be increased
+// if the bytecode or the structures of the compiled blocks are changed.
+const CacheVersion = 1
+
+// The kinds of the serialized values
+const (
+	cvNil = iota
+	cvInt
+	cvInt64
+	cvUint16
+	cvUint32
+	cvFloat
+	cvString
+	cvBool
+	cvBlock
+	cvObj       // the object of the serialized tree
+	cvObjVM     // the object of VM which is found by the name
+	cvObjExtern // the unknown contract in the extern mode
+	cvVar
+	cvIndex
+	cvFuncName
+	cvCatch
+	cvFor
+	cvMap
+	cvArray
+	cvMapItem
+)
+
+var (
+	errCacheVersion = errors.New(`wrong version of the cache`)
+	errCacheObject  = errors.New(`unknown object in the cache`)
+
+	cacheTypes = map[string]reflect.Type{}
+)
+
+func init() {
+	for _, item := range typesMap {
+		cacheTypes[item.Type.String()] = item.Type
+	}
+	iface := reflect.TypeOf((*interface{})(nil)).Elem()
+	cacheTypes[iface.String()] = iface
+}
+
+type cacheValue struct {
+	Kind  uint8
+	Int   int64
+	Float float64
+	Str   string
+	Bool  bool
+	Items []cacheValue
+	Keys  []string
+}
+
+type cacheObj struct {
+	Name  string
+	Type  int
+	Value int // the index of the block or the offset of the variable
+}
+
+type cacheField struct {
+	Name     string
+	Type     string
+	Original uint32
+	Tags     string
+}
+
+type cacheSetting struct {
+	Name  string
+	Value cacheValue
+}
+
+type cacheFuncName struct {
+	Name     string
+	Params   []string
+	Offset   []int
+	Variadic bool
+}
+
+type cacheInfo struct {
+	ID       uint32
+	Name     string
+	Used     []string
+	HasTx    bool
+	Tx       []cacheField
+	Settings []cacheSetting
+	Params   []string
+	Results  []string
+	HasNames bool
+	Names    []cacheFuncName
+	Variadic bool
+	CanWrite bool
+}
+
+type cacheCode struct {
+	Cmd   uint16
+	Line  uint32
+	Value cacheValue
+}
+
+type cacheBlock struct {
+	Type     int
+	Objects  []cacheObj
+	Info     cacheInfo
+	Vars     []string
+	Code     []cacheCode
+	Children []int
+}
+
+type cacheTree struct {
+	Version int
+	Blocks  []cacheBlock
+}
+
+type objRef struct {
+	block int
+	name  string
+}
+
+type blockEncoder struct {
+	vm     *VM
+	blocks map[*Block]int
+	objs   map[*ObjInfo]objRef
+	tree   []cacheBlock
+}
+
+type blockDecoder struct {
+	vm     *VM
+	owner  *OwnerInfo
+	blocks []*Block
+}
+
+// EncodeBlock serializes the compiled block before it is loaded into VM with FlushBlock.
+// The objects of VM which are called from the block are saved by their names.
+func (vm *VM) EncodeBlock(root *Block) ([]byte, error) {
+	enc := &blockEncoder{vm: vm, blocks: make(map[*Block]int), objs: make(map[*ObjInfo]objRef)}
+	enc.indexBlock(root)
+	enc.tree = make([]cacheBlock, len(enc.blocks))
+	if err := enc.block(root); err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(cacheTree{Version: CacheVersion, Blocks: enc.tree}); err != nil {
+		log.WithFields(log.Fields{"type": consts.MarshallingError, "error": err}).Error("encoding compiled block")
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (enc *blockEncoder) indexBlock(block *Block) {
+	ind := len(enc.blocks)
+	enc.blocks[block] = ind
+	for name, obj := range block.Objects {
+		enc.objs[obj] = objRef{ind, name}
+	}
+	for _, child := range block.Children {
+		enc.indexBlock(child)
+	}
+}
+
+func encodeType(t reflect.Type) (string, error) {
+	if t == nil {
+		return ``, nil
+	}
+	if _, ok := cacheTypes[t.String()]; !ok {
+		return ``, fmt.Errorf(`unsupported type %s in the cache`, t)
+	}
+	return t.String(), nil
+}
+
+func encodeTypes(list []reflect.Type) (ret []string, err error) {
+	ret = make([]string, len(list))
+	for i, t := range list {
+		if ret[i], err = encodeType(t); err != nil {
+			return
+		}
+	}
+	return
+}
+
+func (enc *blockEncoder) block(block *Block) (err error) {
+	item := cacheBlock{Type: block.Type}
+	for name, obj := range block.Objects {
+		cobj := cacheObj{Name: name, Type: obj.Type}
+		switch obj.Type {
+		case ObjVar:
+			cobj.Value = obj.Value.(int)
+		case ObjFunc, ObjContract:
+			cobj.Value = enc.blocks[obj.Value.(*Block)]
+		default:
+			return errCacheObject
+		}
+		item.Objects = append(item.Objects, cobj)
+	}
+	if item.Vars, err = encodeTypes(block.Vars); err != nil {
+		return
+	}
+	switch info := block.Info.(type) {
+	case *ContractInfo:
+		item.Info = cacheInfo{ID: info.ID, Name: info.Name, CanWrite: info.CanWrite}
+		for name := range info.Used {
+			item.Info.Used = append(item.Info.Used, name)
+		}
+		if info.Tx != nil {
+			item.Info.HasTx = true
+			for _, field := range *info.Tx {
+				cfield := cacheField{Name: field.Name, Original: field.Original, Tags: field.Tags}
+				if cfield.Type, err = encodeType(field.Type); err != nil {
+					return
+				}
+				item.Info.Tx = append(item.Info.Tx, cfield)
+			}
+		}
+		for name, value := range info.Settings {
+			cset := cacheSetting{Name: name}
+			if cset.Value, err = enc.value(value); err != nil {
+				return
+			}
+			item.Info.Settings = append(item.Info.Settings, cset)
+		}
+	case *FuncInfo:
+		item.Info = cacheInfo{ID: info.ID, Variadic: info.Variadic, CanWrite: info.CanWrite}
+		if item.Info.Params, err = encodeTypes(info.Params); err != nil {
+			return
+		}
+		if item.Info.Results, err = encodeTypes(info.Results); err != nil {
+			return
+		}
+		if info.Names != nil {
+			item.Info.HasNames = true
+			for name, fname := range *info.Names {
+				cname := cacheFuncName{Name: name, Offset: fname.Offset, Variadic: fname.Variadic}
+				if cname.Params, err = encodeTypes(fname.Params); err != nil {
+					return
+				}
+				item.Info.Names = append(item.Info.Names, cname)
+			}
+		}
+	}
+	for _, cmd := range block.Code {
+		ccode := cacheCode{Cmd: cmd.Cmd, Line: cmd.Line}
+		if ccode.Value, err = enc.value(cmd.Value); err != nil {
+			return
+		}
+		item.Code = append(item.Code, ccode)
+	}
+	for _, child := range block.Children {
+		item.Children = append(item.Children, enc.blocks[child])
+		if err = enc.block(child); err != nil {
+			return
+		}
+	}
+	enc.tree[enc.blocks[block]] = item
+	return nil
+}
+
+// objName returns the full name of the object of VM
+func (enc *blockEncoder) objName(obj *ObjInfo) (string, error) {
+	switch obj.Type {
+	case ObjExtFunc:
+		return obj.Value.(ExtFuncInfo).Name, nil
+	case ObjFunc, ObjContract:
+		if obj.Value == nil {
+			return ``, nil
+		}
+		var name string
+		for block := obj.Value.(*Block); block.Parent != nil; block = block.Parent {
+			var key string
+			for k, val := range block.Parent.Objects {
+				if val.Value == block {
+					key = k
+					break
+				}
+			}
+			if len(key) == 0 {
+				return ``, errCacheObject
+			}
+			if len(name) > 0 {
+				name = `.` + name
+			}
+			name = key + name
+			if block.Parent == &enc.vm.Block {
+				return name, nil
+			}
+		}
+	}
+	return ``, errCacheObject
+}
+
+func (enc *blockEncoder) varInfo(v *VarInfo) (cacheValue, error) {
+	if v.Owner == nil {
+		// $name variable
+		return cacheValue{Kind: cvVar, Int: -1, Str: v.Obj.Value.(string)}, nil
+	}
+	owner, ok := enc.blocks[v.Owner]
+	if !ok {
+		return cacheValue{}, errCacheObject
+	}
+	return cacheValue{Kind: cvVar, Int: int64(owner), Items: []cacheValue{{Kind: cvInt,
+		Int: int64(v.Obj.Value.(int))}}}, nil
+}
+
+func (enc *blockEncoder) values(list []mapItem) (ret []cacheValue, err error) {
+	ret = make([]cacheValue, len(list))
+	for i, item := range list {
+		if ret[i], err = enc.value(item); err != nil {
+			return
+		}
+	}
+	return
+}
+
+func (enc *blockEncoder) value(v interface{}) (ret cacheValue, err error) {
+	switch val := v.(type) {
+	case nil:
+		ret.Kind = cvNil
+	case int:
+		ret = cacheValue{Kind: cvInt, Int: int64(val)}
+	case int64:
+		ret = cacheValue{Kind: cvInt64, Int: val}
+	case uint16:
+		ret = cacheValue{Kind: cvUint16, Int: int64(val)}
+	case uint32:
+		ret = cacheValue{Kind: cvUint32, Int: int64(val)}
+	case float64:
+		ret = cacheValue{Kind: cvFloat, Float: val}
+	case string:
+		ret = cacheValue{Kind: cvString, Str: val}
+	case bool:
+		ret = cacheValue{Kind: cvBool, Bool: val}
+	case *Block:
+		ind, ok := enc.blocks[val]
+		if !ok {
+			return ret, errCacheObject
+		}
+		ret = cacheValue{Kind: cvBlock, Int: int64(ind)}
+	case *ObjInfo:
+		if ref, ok := enc.objs[val]; ok {
+			return cacheValue{Kind: cvObj, Int: int64(ref.block), Str: ref.name}, nil
+		}
+		if val.Type == ObjContract && val.Value == nil {
+			return cacheValue{Kind: cvObjExtern}, nil
+		}
+		var name string
+		if name, err = enc.objName(val); err != nil {
+			return
+		}
+		ret = cacheValue{Kind: cvObjVM, Str: name}
+	case *VarInfo:
+		ret, err = enc.varInfo(val)
+	case []*VarInfo:
+		ret.Kind = cvArray
+		ret.Items = make([]cacheValue, len(val))
+		for i, item := range val {
+			if ret.Items[i], err = enc.varInfo(item); err != nil {
+				return
+			}
+		}
+	case *IndexInfo:
+		ret = cacheValue{Kind: cvIndex, Int: int64(val.VarOffset), Str: val.Extend}
+		if val.Owner != nil {
+			var owner cacheValue
+			if owner, err = enc.value(val.Owner); err != nil {
+				return
+			}
+			ret.Items = []cacheValue{owner}
+		}
+	case FuncNameCmd:
+		ret = cacheValue{Kind: cvFuncName, Int: int64(val.Count), Str: val.Name}
+	case *CatchInfo:
+		ret = cacheValue{Kind: cvCatch, Int: int64(enc.blocks[val.Block])}
+		if val.Var != nil {
+			var cvar cacheValue
+			if cvar, err = enc.varInfo(val.Var); err != nil {
+				return
+			}
+			ret.Items = []cacheValue{cvar}
+		}
+	case *ForInfo:
+		ret = cacheValue{Kind: cvFor, Int: int64(enc.blocks[val.Block]), Bool: val.Range,
+			Items: make([]cacheValue, len(val.Vars))}
+		for i, item := range val.Vars {
+			if ret.Items[i], err = enc.varInfo(item); err != nil {
+				return
+			}
+		}
+	case *types.Map:
+		ret = cacheValue{Kind: cvMap, Keys: val.Keys(), Items: make([]cacheValue, val.Size())}
+		for i, key := range ret.Keys {
+			item, _ := val.Get(key)
+			if ret.Items[i], err = enc.value(item); err != nil {
+				return
+			}
+		}
+	case []mapItem:
+		ret.Kind = cvArray
+		ret.Items, err = enc.values(val)
+	case mapItem:
+		var item cacheValue
+		if item, err = enc.value(val.Value); err != nil {
+			return
+		}
+		ret = cacheValue{Kind: cvMapItem, Int: int64(val.Type), Items: []cacheValue{item}}
+	default:
+		err = fmt.Errorf(`unsupported value %T in the cache`, v)
+	}
+	return
+}
+
+// DecodeBlock restores the block which has been serialized with EncodeBlock. The returned block
+// must be loaded into VM with FlushBlock. The error is returned if the called objects are not found in VM.
+func (vm *VM) DecodeBlock(data []byte, owner *OwnerInfo) (*Block, error) {
+	var tree cacheTree
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&tree); err != nil {
+		log.WithFields(log.Fields{"type": consts.UnmarshallingError, "error": err}).Error("decoding compiled block")
+		return nil, err
+	}
+	if tree.Version != CacheVersion || len(tree.Blocks) == 0 {
+		return nil, errCacheVersion
+	}
+	dec := &blockDecoder{vm: vm, owner: owner, blocks: make([]*Block, len(tree.Blocks))}
+	for i := range tree.Blocks {
+		dec.blocks[i] = &Block{}
+	}
+	root := dec.blocks[0]
+	root.Info = owner.StateID
+	root.Owner = owner
+	// the objects must be created before the code because the code refers to them
+	for i, item := range tree.Blocks {
+		block := dec.blocks[i]
+		block.Type = item.Type
+		if len(item.Objects) > 0 {
+			block.Objects = make(map[string]*ObjInfo)
+		}
+		for _, obj := range item.Objects {
+			cobj := &ObjInfo{Type: obj.Type, Value: obj.Value}
+			if obj.Type != ObjVar {
+				if obj.Value <= 0 || obj.Value >= len(dec.blocks) {
+					return nil, errCacheObject
+				}
+				cobj.Value = dec.blocks[obj.Value]
+			}
+			block.Objects[obj.Name] = cobj
+		}
+		for _, child := range item.Children {
+			if child <= 0 || child >= len(dec.blocks) {
+				return nil, errCacheObject
+			}
+			dec.blocks[child].Parent = block
+			block.Children = append(block.Children, dec.blocks[child])
+		}
+	}
+	for i, item := range tree.Blocks {
+		if err := dec.block(dec.blocks[i], &item); err != nil {
+			return nil, err
+		}
+	}
+	return root, nil
+}
+
+func decodeType(name string) (reflect.Type, error) {
+	if len(name) == 0 {
+		return nil, nil
+	}
+	if t, ok := cacheTypes[name]; ok {
+		return t, nil
+	}
+	return nil, fmt.Errorf(`unsupported type %s in the cache`, name)
+}
+
+func decodeTypes(list []string) (ret []reflect.Type, err error) {
+	ret = make([]reflect.Type, len(list))
+	for i, name := range list {
+		if ret[i], err = decodeType(name); err != nil {
+			return
+		}
+	}
+	return
+}
+
+func (dec *blockDecoder) block(block *Block, item *cacheBlock) (err error) {
+	if len(item.Vars) > 0 {
+		if block.Vars, err = decodeTypes(item.Vars); err != nil {
+			return
+		}
+	}
+	switch block.Type {
+	case ObjContract:
+		info := &ContractInfo{ID: item.Info.ID, Name: item.Info.Name, Owner: dec.owner,
+			CanWrite: item.Info.CanWrite}
+		if len(item.Info.Used) > 0 {
+			info.Used = make(map[string]bool)
+			for _, name := range item.Info.Used {
+				info.Used[name] = true
+			}
+		}
+		if item.Info.HasTx {
+			tx := make([]*FieldInfo, len(item.Info.Tx))
+			for i, field := range item.Info.Tx {
+				tx[i] = &FieldInfo{Name: field.Name, Original: field.Original, Tags: field.Tags}
+				if tx[i].Type, err = decodeType(field.Type); err != nil {
+					return
+				}
+			}
+			info.Tx = &tx
+		}
+		if item.Info.Settings != nil {
+			info.Settings = make(map[string]interface{})
+			for _, cset := range item.Info.Settings {
+				if info.Settings[cset.Name], err = dec.value(&cset.Value); err != nil {
+					return
+				}
+			}
+		}
+		block.Info = info
+	case ObjFunc:
+		info := &FuncInfo{ID: item.Info.ID, Variadic: item.Info.Variadic, CanWrite: item.Info.CanWrite}
+		if len(item.Info.Params) > 0 {
+			if info.Params, err = decodeTypes(item.Info.Params); err != nil {
+				return
+			}
+		}
+		if len(item.Info.Results) > 0 {
+			if info.Results, err = decodeTypes(item.Info.Results); err != nil {
+				return
+			}
+		}
+		if item.Info.HasNames {
+			names := make(map[string]FuncName)
+			for _, cname := range item.Info.Names {
+				fname := FuncName{Offset: cname.Offset, Variadic: cname.Variadic}
+				if fname.Params, err = decodeTypes(cname.Params); err != nil {
+					return
+				}
+				names[cname.Name] = fname
+			}
+			info.Names = &names
+		}
+		block.Info = info
+	}
+	if len(item.Code) > 0 {
+		block.Code = make(ByteCodes, len(item.Code))
+	}
+	for i, ccode := range item.Code {
+		cmd := &ByteCode{Cmd: ccode.Cmd, Line: ccode.Line}
+		if cmd.Value, err = dec.value(&ccode.Value); err != nil {
+			return
+		}
+		block.Code[i] = cmd
+	}
+	return nil
+}
+
+func (dec *blockDecoder) getBlock(ind int64) (*Block, error) {
+	if ind < 0 || ind >= int64(len(dec.blocks)) {
+		return nil, errCacheObject
+	}
+	return dec.blocks[ind], nil
+}
+
+func (dec *blockDecoder) varInfo(v *cacheValue) (*VarInfo, error) {
+	if v.Int == -1 {
+		return &VarInfo{Obj: &ObjInfo{Type: ObjExtend, Value: v.Str}}, nil
+	}
+	owner, err := dec.getBlock(v.Int)
+	if err != nil || len(v.Items) != 1 {
+		return nil, errCacheObject
+	}
+	return &VarInfo{Obj: &ObjInfo{Type: ObjVar, Value: int(v.Items[0].Int)}, Owner: owner}, nil
+}
+
+func (dec *blockDecoder) mapItems(list []cacheValue) (ret []mapItem, err error) {
+	ret = make([]mapItem, len(list))
+	for i := range list {
+		var item interface{}
+		if item, err = dec.value(&list[i]); err != nil {
+			return
+		}
+		var ok bool
+		if ret[i], ok = item.(mapItem); !ok {
+			return nil, errCacheObject
+		}
+	}
+	return
+}
+
+func (dec *blockDecoder) value(v *cacheValue) (ret interface{}, err error) {
+	switch v.Kind {
+	case cvNil:
+	case cvInt:
+		ret = int(v.Int)
+	case cvInt64:
+		ret = v.Int
+	case cvUint16:
+		ret = uint16(v.Int)
+	case cvUint32:
+		ret = uint32(v.Int)
+	case cvFloat:
+		ret = v.Float
+	case cvString:
+		ret = v.Str
+	case cvBool:
+		ret = v.Bool
+	case cvBlock:
+		ret, err = dec.getBlock(v.Int)
+	case cvObj:
+		var block *Block
+		if block, err = dec.getBlock(v.Int); err != nil {
+			return
+		}
+		obj, ok := block.Objects[v.Str]
+		if !ok {
+			return nil, errCacheObject
+		}
+		ret = obj
+	case cvObjVM:
+		obj := dec.vm.getObjByName(v.Str)
+		if obj == nil {
+			log.WithFields(log.Fields{"type": consts.VMError, "name": v.Str}).Debug("object of the cache is not found")
+			return nil, errCacheObject
+		}
+		ret = obj
+	case cvObjExtern:
+		ret = &ObjInfo{Type: ObjContract}
+	case cvVar:
+		ret, err = dec.varInfo(v)
+	case cvIndex:
+		info := &IndexInfo{VarOffset: int(v.Int), Extend: v.Str}
+		if len(v.Items) > 0 {
+			if info.Owner, err = dec.getBlock(v.Items[0].Int); err != nil {
+				return
+			}
+		}
+		ret = info
+	case cvFuncName:
+		ret = FuncNameCmd{Name: v.Str, Count: int(v.Int)}
+	case cvCatch:
+		info := &CatchInfo{}
+		if info.Block, err = dec.getBlock(v.Int); err != nil {
+			return
+		}
+		if len(v.Items) > 0 {
+			if info.Var, err = dec.varInfo(&v.Items[0]); err != nil {
+				return
+			}
+		}
+		ret = info
+	case cvFor:
+		info := &ForInfo{Range: v.Bool, Vars: make([]*VarInfo, len(v.Items))}
+		if info.Block, err = dec.getBlock(v.Int); err != nil {
+			return
+		}
+		for i := range v.Items {
+			if info.Vars[i], err = dec.varInfo(&v.Items[i]); err != nil {
+				return
+			}
+		}
+		ret = info
+	case cvMap:
+		if len(v.Keys) != len(v.Items) {
+			return nil, errCacheObject
+		}
+		m := types.NewMap()
+		for i, key := range v.Keys {
+			var item interface{}
+			if item, err = dec.value(&v.Items[i]); err != nil {
+				return
+			}
+			m.Set(key, item)
+		}
+		ret = m
+	case cvArray:
+		if len(v.Items) > 0 && v.Items[0].Kind == cvVar {
+			vars := make([]*VarInfo, len(v.Items))
+			for i := range v.Items {
+				if vars[i], err = dec.varInfo(&v.Items[i]); err != nil {
+					return
+				}
+			}
+			return vars, nil
+		}
+		ret, err = dec.mapItems(v.Items)
+	case cvMapItem:
+		if len(v.Items) != 1 {
+			return nil, errCacheObject
+		}
+		var item interface{}
+		if item, err = dec.value(&v.Items[0]); err != nil {
+			return
+		}
+		ret = mapItem{Type: int(v.Int), Value: item}
+	default:
+		err = errCacheObject
+	}
+	return
+}
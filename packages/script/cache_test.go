@@ -0,0 +1,148 @@
+// Apla Software includes an integrated development
+// environment with a multi-level system for the management
+// of access rights to data, interfaces, and Smart contracts. The
+// technical characteristics of the Apla Software are indicated in
+// Apla Technical Paper.
+
+// Apla Users are granted a permission to deal in the Apla
+// Software without restrictions, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of Apla Software, and to permit persons
+// to whom Apla Software is furnished to do so, subject to the
+// following conditions:
+// * the copyright notice of GenesisKernel and EGAAS S.A.
+// and this permission notice shall be included in all copies or
+// substantial portions of the software;
+// * a result of the dealing in Apla Software cannot be
+// implemented outside of the Apla Platform environment.
+
+// THE APLA SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY
+// OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED
+// TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE, ERROR FREE AND NONINFRINGEMENT. IN
+// NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR
+// THE USE OR OTHER DEALINGS IN THE APLA SOFTWARE.
+
+package script
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/AplaProject/go-apla/packages/conf/syspar"
+)
+
+func TestCacheBlock(t *testing.T) {
+	syspar.SetLoops(true)
+	defer syspar.SetLoops(false)
+
+	base := `func base(a int) int {
+		return a * 10
+	}
+	contract Base {
+		action {
+			$result = "base"
+		}
+	}`
+	test := []TestVM{
+		{`func cacheFunc(par int, s string) string {
+			var m map
+			var arr array
+			m = {"a": par, "b": [s, 2], "c": {"d": 1.5}}
+			arr = [par, "x", $ext]
+			arr[1] = Sprintf("%v-%v", m["b"], m["c"])
+			return Sprintf("%d %v", base(par), arr)
+		}
+		func cacheTest() string {
+			return cacheFunc(7, "str")
+		}`, `cacheTest`, `70 [7 [str 2]-map[d:1.5] <nil>]`},
+		{`func cacheTail(name string).Format(format string, pars ...) string {
+			return Sprintf(format, pars) + name
+		}
+		func CacheCall() string {
+			return Base()
+		}
+		func cacheNames() string {
+			return cacheTail("ok").Format("%v", 1, "two") + CacheCall()
+		}`, `cacheNames`, `[1 two]okbase`},
+		{`func cacheLoop() string {
+			var s string
+			var i int
+			var m map
+			m = {"a": 1, "b": 2}
+			for k, v in m {
+				s = s + Sprintf("%s=%d;", k, v)
+			}
+			for i in 1..3 {
+				s = s + Str(i)
+			}
+			try {
+				i = i / 0
+			} catch err {
+				s = s + err["type"]
+			}
+			while i < 5 {
+				i = i + 1
+				if i == 4 {
+					continue
+				}
+			}
+			return s + Str(i)
+		}`, `cacheLoop`, `a=1;b=2;12panic5`},
+		{`contract CacheContract {
+			data {
+				Name string "optional"
+			}
+			settings {
+				rate = 1.5
+			}
+			func conditions {
+				$cond = "cond"
+			}
+			action {
+				$result = Sprintf("%s %s %v", $cond, $Name, Settings("rate"))
+			}
+		}
+		func cacheContract() string {
+			return CacheContract("Name", "test")
+		}`, `cacheContract`, `cond test 1.5`},
+	}
+	newVM := func() *VM {
+		vm := NewVM()
+		vm.Extern = true
+		vm.Extend(&ExtendData{map[string]interface{}{"Sprintf": fmt.Sprintf, "Str": str,
+			"Settings": func(name string) string { return `1.5` }}, nil, map[string]struct{}{"Sprintf": {}}})
+		if err := vm.Compile([]rune(base), &OwnerInfo{StateID: 1, Active: true, TableID: 1}); err != nil {
+			t.Fatal(err)
+		}
+		return vm
+	}
+	for i, item := range test {
+		src, dest := newVM(), newVM()
+		owner := &OwnerInfo{StateID: 1, Active: true, TableID: int64(i + 2)}
+		root, err := src.CompileBlock([]rune(item.Input), owner)
+		if err != nil {
+			t.Fatal(item.Func, err)
+		}
+		data, err := src.EncodeBlock(root)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if root, err = dest.DecodeBlock(data, owner); err != nil {
+			t.Fatal(err)
+		}
+		dest.FlushBlock(root)
+		out, err := dest.Call(item.Func, nil, &map[string]interface{}{`rt_state`: uint32(1)})
+		if err != nil {
+			t.Errorf(`%s: %s`, item.Func, err)
+		} else if out[0].(string) != item.Output {
+			t.Errorf(`%s: %s != %s`, item.Func, out[0], item.Output)
+		}
+	}
+	if _, err := NewVM().DecodeBlock([]byte(`wrong`), &OwnerInfo{}); err == nil {
+		t.Error(`wrong cache has been decoded`)
+	}
+}
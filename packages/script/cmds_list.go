@@ -54,6 +54,9 @@ const (
 	cmdMapInit               // map initialization
 	cmdArrayInit             // array initialization
 	cmdError                 // error command
+	cmdTry                   // run block and pass its error to catch
+	cmdCatch                 // run block if try has failed
+	cmdFor                   // run block for each item of array, map or range
 )
 
 // the commands for operations in expressions are listed below
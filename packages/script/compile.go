@@ -121,6 +121,8 @@ const (
 	stateConstsAssign
 	stateConstsValue
 	stateFields
+	stateCatch
+	stateFor
 	stateEval
 
 	// The list of state flags
@@ -182,6 +184,14 @@ const (
 	cfContinue
 	cfBreak
 	cfCmdError
+	cfTry
+	cfCatch
+	cfCatchVar
+	cfFor
+	cfForVar
+	cfForIn
+	cfForRange
+	cfForBlock
 
 //	cfEval
 )
@@ -222,6 +232,14 @@ var (
 		fContinue,
 		fBreak,
 		fCmdError,
+		fTry,
+		fCatch,
+		fCatchVar,
+		fFor,
+		fForVar,
+		fForIn,
+		fForRange,
+		fForBlock,
 	}
 
 	// 'states' describes a finite machine with states on the base of which a bytecode will be generated
@@ -241,6 +259,9 @@ var (
 			lexKeyword | (keyIf << 8):       {stateEval | statePush | stateToBlock | stateMustEval, cfIf},
 			lexKeyword | (keyWhile << 8):    {stateEval | statePush | stateToBlock | stateLabel | stateMustEval, cfWhile},
 			lexKeyword | (keyElse << 8):     {stateBlock | statePush, cfElse},
+			lexKeyword | (keyTry << 8):      {stateBlock | statePush, cfTry},
+			lexKeyword | (keyCatch << 8):    {stateCatch | statePush, cfCatch},
+			lexKeyword | (keyFor << 8):      {stateFor | statePush, cfFor},
 			lexKeyword | (keyVar << 8):      {stateVar, 0},
 			lexKeyword | (keyTX << 8):       {stateTX, cfTX},
 			lexKeyword | (keySettings << 8): {stateSettings, cfSettings},
@@ -365,6 +386,20 @@ var (
 			isRCurly:   {stateToBody, cfFields},
 			0:          {errMustRCurly, cfError},
 		},
+		{ // stateCatch
+			lexIdent: {stateBlock, cfCatchVar},
+			isLCurly: {stateBody, 0},
+			0:        {errMustLCurly, cfError},
+		},
+		{ // stateFor
+			lexNewLine:                   {stateFor, 0},
+			lexIdent:                     {stateFor, cfForVar},
+			isComma:                      {stateFor, 0},
+			lexKeyword | (keyIn << 8):    {stateEval | stateMustEval, cfForIn},
+			lexKeyword | (keyRange << 8): {stateEval | stateMustEval, cfForRange},
+			isLCurly:                     {stateBody, cfForBlock},
+			0:                            {errMustLCurly, cfError},
+		},
 	}
 )
 
@@ -397,12 +432,12 @@ func fFuncResult(buf *[]*Block, state int, lexem *Lexem) error {
 }
 
 func fReturn(buf *[]*Block, state int, lexem *Lexem) error {
-	(*(*buf)[len(*buf)-1]).Code = append((*(*buf)[len(*buf)-1]).Code, &ByteCode{cmdReturn, 0})
+	(*(*buf)[len(*buf)-1]).Code = append((*(*buf)[len(*buf)-1]).Code, &ByteCode{cmdReturn, lexem.Line, 0})
 	return nil
 }
 
 func fCmdError(buf *[]*Block, state int, lexem *Lexem) error {
-	(*(*buf)[len(*buf)-1]).Code = append((*(*buf)[len(*buf)-1]).Code, &ByteCode{cmdError, lexem.Value})
+	(*(*buf)[len(*buf)-1]).Code = append((*(*buf)[len(*buf)-1]).Code, &ByteCode{cmdError, lexem.Line, lexem.Value})
 	return nil
 }
 
@@ -526,23 +561,23 @@ func fFNameParam(buf *[]*Block, state int, lexem *Lexem) error {
 }
 
 func fIf(buf *[]*Block, state int, lexem *Lexem) error {
-	(*(*buf)[len(*buf)-2]).Code = append((*(*buf)[len(*buf)-2]).Code, &ByteCode{cmdIf, (*buf)[len(*buf)-1]})
+	(*(*buf)[len(*buf)-2]).Code = append((*(*buf)[len(*buf)-2]).Code, &ByteCode{cmdIf, lexem.Line, (*buf)[len(*buf)-1]})
 	return nil
 }
 
 func fWhile(buf *[]*Block, state int, lexem *Lexem) error {
-	(*(*buf)[len(*buf)-2]).Code = append((*(*buf)[len(*buf)-2]).Code, &ByteCode{cmdWhile, (*buf)[len(*buf)-1]})
-	(*(*buf)[len(*buf)-2]).Code = append((*(*buf)[len(*buf)-2]).Code, &ByteCode{cmdContinue, 0})
+	(*(*buf)[len(*buf)-2]).Code = append((*(*buf)[len(*buf)-2]).Code, &ByteCode{cmdWhile, lexem.Line, (*buf)[len(*buf)-1]})
+	(*(*buf)[len(*buf)-2]).Code = append((*(*buf)[len(*buf)-2]).Code, &ByteCode{cmdContinue, lexem.Line, 0})
 	return nil
 }
 
 func fContinue(buf *[]*Block, state int, lexem *Lexem) error {
-	(*(*buf)[len(*buf)-1]).Code = append((*(*buf)[len(*buf)-1]).Code, &ByteCode{cmdContinue, 0})
+	(*(*buf)[len(*buf)-1]).Code = append((*(*buf)[len(*buf)-1]).Code, &ByteCode{cmdContinue, lexem.Line, 0})
 	return nil
 }
 
 func fBreak(buf *[]*Block, state int, lexem *Lexem) error {
-	(*(*buf)[len(*buf)-1]).Code = append((*(*buf)[len(*buf)-1]).Code, &ByteCode{cmdBreak, 0})
+	(*(*buf)[len(*buf)-1]).Code = append((*(*buf)[len(*buf)-1]).Code, &ByteCode{cmdBreak, lexem.Line, 0})
 	return nil
 }
 
@@ -574,15 +609,15 @@ func fAssignVar(buf *[]*Block, state int, lexem *Lexem) error {
 	}
 	prev = append(prev, &ivar)
 	if len(prev) == 1 {
-		(*(*buf)[len(*buf)-1]).Code = append((*block).Code, &ByteCode{cmdAssignVar, prev})
+		(*(*buf)[len(*buf)-1]).Code = append((*block).Code, &ByteCode{cmdAssignVar, lexem.Line, prev})
 	} else {
-		(*(*buf)[len(*buf)-1]).Code[len(block.Code)-1] = &ByteCode{cmdAssignVar, prev}
+		(*(*buf)[len(*buf)-1]).Code[len(block.Code)-1] = &ByteCode{cmdAssignVar, lexem.Line, prev}
 	}
 	return nil
 }
 
 func fAssign(buf *[]*Block, state int, lexem *Lexem) error {
-	(*(*buf)[len(*buf)-1]).Code = append((*(*buf)[len(*buf)-1]).Code, &ByteCode{cmdAssign, 0})
+	(*(*buf)[len(*buf)-1]).Code = append((*(*buf)[len(*buf)-1]).Code, &ByteCode{cmdAssign, lexem.Line, 0})
 	return nil
 }
 
@@ -701,7 +736,113 @@ func fElse(buf *[]*Block, state int, lexem *Lexem) error {
 		logger.WithFields(log.Fields{"type": consts.ParseError}).Error("there is not if before")
 		return fmt.Errorf(`there is not if before %v [Ln:%d Col:%d]`, lexem.Type, lexem.Line, lexem.Column)
 	}
-	(*(*buf)[len(*buf)-2]).Code = append(code, &ByteCode{cmdElse, (*buf)[len(*buf)-1]})
+	(*(*buf)[len(*buf)-2]).Code = append(code, &ByteCode{cmdElse, lexem.Line, (*buf)[len(*buf)-1]})
+	return nil
+}
+
+func fTry(buf *[]*Block, state int, lexem *Lexem) error {
+	(*(*buf)[len(*buf)-2]).Code = append((*(*buf)[len(*buf)-2]).Code, &ByteCode{cmdTry, lexem.Line, (*buf)[len(*buf)-1]})
+	return nil
+}
+
+func fCatch(buf *[]*Block, state int, lexem *Lexem) error {
+	code := (*(*buf)[len(*buf)-2]).Code
+	if len(code) == 0 || code[len(code)-1].Cmd != cmdTry {
+		logger := lexem.GetLogger()
+		logger.WithFields(log.Fields{"type": consts.ParseError}).Error("there is not try before")
+		return fmt.Errorf(`there is not try before %v [Ln:%d Col:%d]`, lexem.Type, lexem.Line, lexem.Column)
+	}
+	(*(*buf)[len(*buf)-2]).Code = append(code, &ByteCode{cmdCatch, lexem.Line, &CatchInfo{Block: (*buf)[len(*buf)-1]}})
+	return nil
+}
+
+// fCatchVar defines the variable of the error in the block which contains try and catch
+func fCatchVar(buf *[]*Block, state int, lexem *Lexem) error {
+	block := (*buf)[len(*buf)-2]
+	if block.Objects == nil {
+		block.Objects = make(map[string]*ObjInfo)
+	}
+	objInfo := &ObjInfo{Type: ObjVar, Value: len(block.Vars)}
+	block.Objects[lexem.Value.(string)] = objInfo
+	block.Vars = append(block.Vars, reflect.TypeOf(&types.Map{}))
+	block.Code[len(block.Code)-1].Value.(*CatchInfo).Var = &VarInfo{objInfo, block}
+	return nil
+}
+
+// checkTry checks that every try block is followed by catch block
+func checkTry(block *Block) error {
+	for i, cmd := range block.Code {
+		if cmd.Cmd == cmdTry && (i == len(block.Code)-1 || block.Code[i+1].Cmd != cmdCatch) {
+			log.WithFields(log.Fields{"type": consts.ParseError, "line": cmd.Line}).Error("try without catch")
+			return errTryCatch
+		}
+	}
+	for _, child := range block.Children {
+		if err := checkTry(child); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fFor appends for command. The code of the iterated expression is compiled into the body
+// at first and it is moved before for command when the body begins.
+func fFor(buf *[]*Block, state int, lexem *Lexem) error {
+	(*(*buf)[len(*buf)-2]).Code = append((*(*buf)[len(*buf)-2]).Code, &ByteCode{cmdFor, lexem.Line,
+		&ForInfo{Block: (*buf)[len(*buf)-1]}})
+	return nil
+}
+
+func getForInfo(buf *[]*Block) *ForInfo {
+	code := (*(*buf)[len(*buf)-2]).Code
+	return code[len(code)-1].Value.(*ForInfo)
+}
+
+// fForVar defines the variable of the loop in the block which contains for
+func fForVar(buf *[]*Block, state int, lexem *Lexem) error {
+	info := getForInfo(buf)
+	if len(info.Block.Code) > 0 {
+		return fmt.Errorf(eForIn, lexem.Line, lexem.Column)
+	}
+	if len(info.Vars) == 2 {
+		return fmt.Errorf(eForVars, lexem.Line, lexem.Column)
+	}
+	block := (*buf)[len(*buf)-2]
+	objInfo := &ObjInfo{Type: ObjVar, Value: len(block.Vars)}
+	block.Objects[lexem.Value.(string)] = objInfo
+	block.Vars = append(block.Vars, reflect.TypeOf((*interface{})(nil)).Elem())
+	info.Vars = append(info.Vars, &VarInfo{objInfo, block})
+	return nil
+}
+
+func fForIn(buf *[]*Block, state int, lexem *Lexem) error {
+	if len(getForInfo(buf).Vars) == 0 {
+		return fmt.Errorf(eForVars, lexem.Line, lexem.Column)
+	}
+	return nil
+}
+
+func fForRange(buf *[]*Block, state int, lexem *Lexem) error {
+	info := getForInfo(buf)
+	if info.Range {
+		return fmt.Errorf(eForIn, lexem.Line, lexem.Column)
+	}
+	if len(info.Vars) != 1 {
+		return fmt.Errorf(eForRange, lexem.Line, lexem.Column)
+	}
+	info.Range = true
+	return nil
+}
+
+func fForBlock(buf *[]*Block, state int, lexem *Lexem) error {
+	info := getForInfo(buf)
+	if len(info.Block.Code) == 0 {
+		return fmt.Errorf(eForIn, lexem.Line, lexem.Column)
+	}
+	block := (*buf)[len(*buf)-2]
+	cmd := block.Code[len(block.Code)-1]
+	block.Code = append(append(block.Code[:len(block.Code)-1], info.Block.Code...), cmd)
+	info.Block.Code = nil
 	return nil
 }
 
@@ -778,7 +919,7 @@ func (vm *VM) CompileBlock(input []rune, owner *OwnerInfo) (*Block, error) {
 		}
 		if nextState == stateEval {
 			if newState.NewState&stateLabel > 0 {
-				(*blockstack[len(blockstack)-1]).Code = append((*blockstack[len(blockstack)-1]).Code, &ByteCode{cmdLabel, 0})
+				(*blockstack[len(blockstack)-1]).Code = append((*blockstack[len(blockstack)-1]).Code, &ByteCode{cmdLabel, lexem.Line, 0})
 			}
 			curlen := len((*blockstack[len(blockstack)-1]).Code)
 			if err := vm.compileEval(&lexems, &i, &blockstack); err != nil {
@@ -812,7 +953,7 @@ func (vm *VM) CompileBlock(input []rune, owner *OwnerInfo) (*Block, error) {
 				if len(prev.Code) > 0 && (*prev).Code[len((*prev).Code)-1].Cmd == cmdContinue {
 					(*prev).Code = (*prev).Code[:len((*prev).Code)-1]
 					prev = blockstack[len(blockstack)-1]
-					(*prev).Code = append((*prev).Code, &ByteCode{cmdContinue, 0})
+					(*prev).Code = append((*prev).Code, &ByteCode{cmdContinue, lexem.Line, 0})
 				}
 			}
 			blockstack = blockstack[:len(blockstack)-1]
@@ -833,6 +974,9 @@ func (vm *VM) CompileBlock(input []rune, owner *OwnerInfo) (*Block, error) {
 	if len(stack) > 0 {
 		return nil, fError(&blockstack, errMustRCurly, lexems[len(lexems)-1])
 	}
+	if err := checkTry(root); err != nil {
+		return nil, err
+	}
 	for _, item := range root.Objects {
 		if item.Type == ObjContract {
 			if cond, ok := item.Value.(*Block).Objects[`conditions`]; ok {
@@ -1094,7 +1238,10 @@ func setWritable(block *[]*Block) {
 
 // This function is responsible for the compilation of expressions
 func (vm *VM) compileEval(lexems *Lexems, ind *int, block *[]*Block) error {
-	var indexInfo *IndexInfo
+	var (
+		indexInfo *IndexInfo
+		indexLine uint32
+	)
 
 	i := *ind
 	curBlock := (*block)[len(*block)-1]
@@ -1117,7 +1264,7 @@ main:
 				if err != nil {
 					return err
 				}
-				bytecode = append(bytecode, &ByteCode{cmdMapInit, pMap})
+				bytecode = append(bytecode, &ByteCode{cmdMapInit, lexem.Line, pMap})
 				continue
 			}
 			if lexem.Type == isLBrack {
@@ -1125,13 +1272,19 @@ main:
 				if err != nil {
 					return err
 				}
-				bytecode = append(bytecode, &ByteCode{cmdArrayInit, pArray})
+				bytecode = append(bytecode, &ByteCode{cmdArrayInit, lexem.Line, pArray})
 				continue
 			}
 		}
 		noMap = false
 
 		switch lexem.Type {
+		case lexKeyword | (keyRange << 8):
+			// the bounds of the range are compiled as two expressions
+			if i > *ind {
+				i--
+				break main
+			}
 		case isRCurly, isLCurly:
 			i--
 			if prevLex == isComma || prevLex == lexOper {
@@ -1149,9 +1302,9 @@ main:
 			}
 			break main
 		case isLPar:
-			buffer = append(buffer, &ByteCode{cmdSys, uint16(0xff)})
+			buffer = append(buffer, &ByteCode{cmdSys, lexem.Line, uint16(0xff)})
 		case isLBrack:
-			buffer = append(buffer, &ByteCode{cmdSys, uint16(0xff)})
+			buffer = append(buffer, &ByteCode{cmdSys, lexem.Line, uint16(0xff)})
 		case isComma:
 			if len(parcount) > 0 {
 				parcount[len(parcount)-1]++
@@ -1197,7 +1350,7 @@ main:
 					}
 					if objInfo.Type == ObjFunc && objInfo.Value.(*Block).Info.(*FuncInfo).Names != nil {
 						if len(bytecode) == 0 || bytecode[len(bytecode)-1].Cmd != cmdFuncName {
-							bytecode = append(bytecode, &ByteCode{cmdPush, nil})
+							bytecode = append(bytecode, &ByteCode{cmdPush, lexem.Line, nil})
 						}
 						if i < len(*lexems)-4 && (*lexems)[i+1].Type == isDot {
 							if (*lexems)[i+2].Type != lexIdent {
@@ -1210,7 +1363,7 @@ main:
 								if i < len(*lexems)-5 && (*lexems)[i+3].Type == isLPar {
 									objInfo, _ := vm.findObj((*lexems)[i+2].Value.(string), block)
 									if objInfo != nil && objInfo.Type == ObjFunc || objInfo.Type == ObjExtFunc {
-										tail = &ByteCode{uint16(cmdCall), objInfo}
+										tail = &ByteCode{uint16(cmdCall), lexem.Line, objInfo}
 									}
 								}
 								if tail == nil {
@@ -1219,7 +1372,7 @@ main:
 								}
 							}
 							if tail == nil {
-								buffer = append(buffer, &ByteCode{cmdFuncName, FuncNameCmd{Name: (*lexems)[i+2].Value.(string)}})
+								buffer = append(buffer, &ByteCode{cmdFuncName, lexem.Line, FuncNameCmd{Name: (*lexems)[i+2].Value.(string)}})
 								count := 0
 								if (*lexems)[i+3].Type != isRPar {
 									count++
@@ -1248,7 +1401,7 @@ main:
 						}
 					}
 					if prev.Cmd == cmdCallVari {
-						bytecode = append(bytecode, &ByteCode{cmdPush, count})
+						bytecode = append(bytecode, &ByteCode{cmdPush, lexem.Line, count})
 					}
 					buffer = buffer[:len(buffer)-1]
 					bytecode = append(bytecode, prev)
@@ -1281,6 +1434,7 @@ main:
 						i++
 						setIndex = true
 						indexInfo = prev.Value.(*IndexInfo)
+						indexLine = lexem.Line
 						noMap = false
 						continue
 					}
@@ -1304,7 +1458,7 @@ main:
 				} else if prevLex == lexOper && oper.Priority != cmdUnary {
 					return errOper
 				}
-				byteOper := &ByteCode{oper.Cmd, oper.Priority}
+				byteOper := &ByteCode{oper.Cmd, lexem.Line, oper.Priority}
 				for {
 					if len(buffer) == 0 {
 						buffer = append(buffer, byteOper)
@@ -1334,7 +1488,7 @@ main:
 			}
 		case lexNumber, lexString:
 			noMap = true
-			cmd = &ByteCode{cmdPush, lexem.Value}
+			cmd = &ByteCode{cmdPush, lexem.Line, lexem.Value}
 		case lexExtend:
 			noMap = true
 			if i < len(*lexems)-2 {
@@ -1344,14 +1498,14 @@ main:
 						count++
 					}
 					parcount = append(parcount, count)
-					buffer = append(buffer, &ByteCode{cmdCallExtend, lexem.Value.(string)})
+					buffer = append(buffer, &ByteCode{cmdCallExtend, lexem.Line, lexem.Value.(string)})
 					call = true
 				}
 			}
 			if !call {
-				cmd = &ByteCode{cmdExtend, lexem.Value.(string)}
+				cmd = &ByteCode{cmdExtend, lexem.Line, lexem.Value.(string)}
 				if i < len(*lexems)-1 && (*lexems)[i+1].Type == isLBrack {
-					buffer = append(buffer, &ByteCode{cmdIndex, &IndexInfo{Extend: lexem.Value.(string)}})
+					buffer = append(buffer, &ByteCode{cmdIndex, lexem.Line, &IndexInfo{Extend: lexem.Value.(string)}})
 				}
 			}
 		case lexIdent:
@@ -1391,7 +1545,7 @@ main:
 					if (*lexems)[i+2].Type != isRPar {
 						count++
 					}
-					buffer = append(buffer, &ByteCode{cmdCall, objInfo})
+					buffer = append(buffer, &ByteCode{cmdCall, lexem.Line, objInfo})
 					if isContract {
 						name := StateName((*block)[0].Info.(uint32), lexem.Value.(string))
 						for j := len(*block) - 1; j >= 0; j-- {
@@ -1409,17 +1563,17 @@ main:
 						if objContract != nil && objContract.Info.(*ContractInfo).CanWrite {
 							setWritable(block)
 						}
-						bytecode = append(bytecode, &ByteCode{cmdPush, name})
+						bytecode = append(bytecode, &ByteCode{cmdPush, lexem.Line, name})
 						if count == 0 {
 							count = 2
-							bytecode = append(bytecode, &ByteCode{cmdPush, ""})
-							bytecode = append(bytecode, &ByteCode{cmdPush, ""})
+							bytecode = append(bytecode, &ByteCode{cmdPush, lexem.Line, ""})
+							bytecode = append(bytecode, &ByteCode{cmdPush, lexem.Line, ""})
 						}
 						count++
 					}
 					if lexem.Value.(string) == `CallContract` {
 						count++
-						bytecode = append(bytecode, &ByteCode{cmdPush, (*block)[0].Info.(uint32)})
+						bytecode = append(bytecode, &ByteCode{cmdPush, lexem.Line, (*block)[0].Info.(uint32)})
 					}
 					parcount = append(parcount, count)
 					call = true
@@ -1429,14 +1583,14 @@ main:
 						logger.WithFields(log.Fields{"lex_value": lexem.Value.(string), "type": consts.ParseError}).Error("unknown variable")
 						return fmt.Errorf(`unknown variable %s`, lexem.Value.(string))
 					}
-					buffer = append(buffer, &ByteCode{cmdIndex, &IndexInfo{objInfo.Value.(int), tobj, ``}})
+					buffer = append(buffer, &ByteCode{cmdIndex, lexem.Line, &IndexInfo{objInfo.Value.(int), tobj, ``}})
 				}
 			}
 			if !call {
 				if objInfo.Type != ObjVar {
 					return fmt.Errorf(`unknown variable %s`, lexem.Value.(string))
 				}
-				cmd = &ByteCode{cmdVar, &VarInfo{objInfo, tobj}}
+				cmd = &ByteCode{cmdVar, lexem.Line, &VarInfo{objInfo, tobj}}
 			}
 		}
 		if lexem.Type != lexNewLine {
@@ -1444,7 +1598,7 @@ main:
 		}
 		if lexem.Type&0xff == lexKeyword {
 			if lexem.Value.(uint32) == keyTail {
-				cmd = &ByteCode{cmdUnwrapArr, 0}
+				cmd = &ByteCode{cmdUnwrapArr, lexem.Line, 0}
 			}
 		}
 		if cmd != nil {
@@ -1463,7 +1617,7 @@ main:
 		bytecode = append(bytecode, buffer[i])
 	}
 	if setIndex {
-		bytecode = append(bytecode, &ByteCode{cmdSetIndex, indexInfo})
+		bytecode = append(bytecode, &ByteCode{cmdSetIndex, indexLine, indexInfo})
 	}
 	curBlock.Code = append(curBlock.Code, bytecode...)
 	return nil
@@ -33,6 +33,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/AplaProject/go-apla/packages/conf/syspar"
 	"github.com/AplaProject/go-apla/packages/types"
 
 	"github.com/shopspring/decimal"
@@ -93,6 +94,9 @@ func outMap(v *types.Map) string {
 }
 
 func TestVMCompile(t *testing.T) {
+	syspar.SetLoops(true)
+	defer syspar.SetLoops(false)
+
 	test := []TestVM{
 		{`contract sets {
 			settings {
@@ -683,6 +687,124 @@ func TestVMCompile(t *testing.T) {
 		{`func result() {
 				error "test"*
 				}`, `result`, `unexpected end of the expression`},
+		{`func tryErr() string {
+			var s string
+			try {
+				s = "try"
+				error "wrong value"
+			} catch err {
+				s = s + "=" + err["type"] + "=" + err["message"]
+			}
+			return s
+		}`, `tryErr`, `try=error=wrong value`},
+		{`func tryOK() string {
+			var s string
+			try {
+				s = "try"
+			} catch {
+				s = "catch"
+			}
+			return s + "=ok"
+		}`, `tryOK`, `try=ok`},
+		{`func tryPanic() string {
+			var i int
+			try {
+				i = 10 / i
+			} catch err {
+				return err["type"]
+			}
+			return "none"
+		}`, `tryPanic`, `panic`},
+		{`contract qqtry {
+			data {
+				Id int
+			}
+			action {
+				error "contract " + str($Id)
+			}
+		}
+		func tryContract() string {
+			try {
+				qqtry("Id", 7)
+			} catch err {
+				return err["message"]
+			}
+			return "none"
+		}`, `tryContract`, `contract 7`},
+		{`func forArr() string {
+			var a array
+			var s string
+			a = [10, "b", 30]
+			for item in a {
+				s = s + str(item)
+			}
+			for i, item in a {
+				s = s + "," + str(i) + "=" + str(item)
+			}
+			return s
+		}`, `forArr`, `10b30,0=10,1=b,2=30`},
+		{`func forMap() string {
+			var m map
+			var s string
+			m = {"b": 2, "c": 3, "a": 1}
+			for key in m {
+				s = s + key
+			}
+			for key, value in m {
+				s = s + "," + key + "=" + str(value)
+			}
+			return s
+		}`, `forMap`, `abc,a=1,b=2,c=3`},
+		{`func forRange(n int) string {
+			var s string
+			for i in 0..10 {
+				if i == 2 {
+					continue
+				}
+				if i == 5 {
+					break
+				}
+				s = s + str(i)
+			}
+			for i in n..n+2 {
+				s = s + "," + str(i)
+			}
+			return s
+		}
+		func forResult() string {
+			return forRange(7)
+		}`, `forResult`, `0134,7,8`},
+		{`func forReturn() string {
+			for i in 1..100 {
+				if i * i > 50 {
+					return str(i)
+				}
+			}
+			return "none"
+		}`, `forReturn`, `8`},
+		{`func forVars() string {
+			var a array
+			for i, j, k in a {
+			}
+			return "none"
+		}`, `forVars`, `for must have one or two variables [Ln:3 Col:15]`},
+		{`func forType() string {
+			for i in "string" {
+			}
+			return "none"
+		}`, `forType`, `cannot iterate over string`},
+		{`func tryNoCatch() string {
+			try {
+				return "try"
+			}
+			return "none"
+		}`, `tryNoCatch`, `try must be followed by catch`},
+		{`func catchNoTry() string {
+			catch err {
+				return "catch"
+			}
+			return "none"
+		}`, `catchNoTry`, `there is not try before 5896 [Ln:2 Col:5]`},
 	}
 	vm := NewVM()
 	vm.Extern = true
@@ -44,6 +44,10 @@ const (
 	eDataType        = `expecting type of the data field [Ln:%d Col:%d]`
 	eDataName        = `expecting name of the data field [Ln:%d Col:%d]`
 	eDataTag         = `unexpected tag [Ln:%d Col:%d]`
+	eForVars         = `for must have one or two variables [Ln:%d Col:%d]`
+	eForIn           = `expecting in [Ln:%d Col:%d]`
+	eForRange        = `range of for must have one variable [Ln:%d Col:%d]`
+	eForType         = `cannot iterate over %s`
 )
 
 var (
@@ -65,4 +69,6 @@ var (
 	errSelfAssignment  = errors.New(`self assignment`)
 	errEndExp          = errors.New(`unexpected end of the expression`)
 	errOper            = errors.New(`unexpected operator; expecting operand`)
+	errTryCatch        = errors.New(`try must be followed by catch`)
+	errForRange        = errors.New(`bounds of range must be integers`)
 )
@@ -35,6 +35,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/AplaProject/go-apla/packages/conf/syspar"
 	"github.com/AplaProject/go-apla/packages/consts"
 	"github.com/AplaProject/go-apla/packages/types"
 
@@ -121,6 +122,11 @@ const (
 	keyCond
 	keyTail
 	keyError
+	keyTry
+	keyCatch
+	keyFor
+	keyIn
+	keyRange
 )
 
 const (
@@ -154,7 +160,8 @@ var (
 		msgInfo: keyInfo, `while`: keyWhile, `data`: keyTX, `settings`: keySettings, `nil`: keyNil,
 		`action`: keyAction, `conditions`: keyCond,
 		`true`: keyTrue, `false`: keyFalse, `break`: keyBreak, `continue`: keyContinue,
-		`var`: keyVar, `...`: keyTail}
+		`var`: keyVar, `...`: keyTail, `try`: keyTry, `catch`: keyCatch,
+		`for`: keyFor, `in`: keyIn, `..`: keyRange}
 
 	// list of available types
 	// The list of types which save the corresponding 'reflect' type
@@ -208,6 +215,7 @@ func lexParser(input []rune) (Lexems, error) {
 
 	lexems := make(Lexems, 0, len(input)/4)
 	irune := len(alphabet) - 1
+	loops := syspar.IsLoops()
 
 	// This function according to the next symbol looks with help of lexTable what new state we will have,
 	// whether we got the lexeme and what flags are displayed
@@ -310,7 +318,19 @@ func lexParser(input []rune) (Lexems, error) {
 				value = binary.BigEndian.Uint32(append(make([]byte, 4-len(oper)), oper...))
 			case lexNumber:
 				name := string(input[lexOff:right])
-				if strings.ContainsAny(name, `.`) {
+				if strings.HasSuffix(name, `..`) {
+					// the number is the beginning of the range 0..n
+					name = name[:len(name)-2]
+					val, err := strconv.ParseInt(name, 10, 64)
+					if err != nil {
+						log.WithFields(log.Fields{"error": err, "value": name, "lex_line": line, "lex_col": off - offline + 1, "type": consts.ConversionError}).Error("converting lex number to int")
+						return nil, fmt.Errorf(`%v %s [Ln:%d Col:%d]`, err, name, line, off-offline+1)
+					}
+					lexems = append(lexems, &Lexem{lexNumber, 0, val, line, lexOff - offline + 1})
+					lexID = lexKeyword | (keyRange << 8)
+					value = uint32(keyRange)
+					lexOff = right - 2
+				} else if strings.ContainsAny(name, `.`) {
 					if val, err := strconv.ParseFloat(name, 64); err == nil {
 						value = val
 					} else {
@@ -328,7 +348,7 @@ func lexParser(input []rune) (Lexems, error) {
 				if name[0] == '$' {
 					lexID = lexExtend
 					value = name[1:]
-				} else if keyID, ok := keywords[name]; ok {
+				} else if keyID, ok := keywords[name]; ok && (loops || (keyID != keyFor && keyID != keyIn)) {
 					switch keyID {
 					case keyIf:
 						ifbuf = append(ifbuf, ifBuf{})
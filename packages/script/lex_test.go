@@ -31,6 +31,8 @@ package script
 import (
 	"fmt"
 	"testing"
+
+	"github.com/AplaProject/go-apla/packages/conf/syspar"
 )
 
 type TestLexem struct {
@@ -80,3 +82,25 @@ func TestLexParser(t *testing.T) {
 		}
 	}
 }
+
+func TestLexLoops(t *testing.T) {
+	source := []rune(`for in`)
+	lexems, err := lexParser(source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out := lexems.String(source); out != `[4 for][4 in]` {
+		t.Errorf("for and in must be identifiers before loops_block %s", out)
+	}
+
+	syspar.SetLoops(true)
+	defer syspar.SetLoops(false)
+	if lexems, err = lexParser(source); err != nil {
+		t.Fatal(err)
+	}
+	for i, key := range []uint32{keyFor, keyIn} {
+		if lexems[i].Type != lexKeyword|(key<<8) {
+			t.Errorf("wrong lexem %d %v", i, lexems[i].Value)
+		}
+	}
+}
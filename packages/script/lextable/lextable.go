@@ -117,7 +117,7 @@ var (
 	},
 	"ddot": {
 		".": ["main", "ident", "pop next"],
-		"d": ["error", "", ""]
+		"d": ["main", "ident", "pop"]
 	},
 	"and": {
 			"&": ["main", "oper", "pop next"],
@@ -141,10 +141,20 @@ var (
 			"d": ["main", "oper", "pop"]
 		},
 	"number": {
-			"01.": ["number", "", "next"],
+			"01": ["number", "", "next"],
+			".": ["numdot", "", "next"],
+			"a_r": ["error", "", ""],
+			"d": ["main", "number", "pop"]
+		},
+	"numdot": {
+			"01": ["number", "", "next"],
+			".": ["numrange", "", "next"],
 			"a_r": ["error", "", ""],
 			"d": ["main", "number", "pop"]
 		},
+	"numrange": {
+			"d": ["main", "number", "pop"]
+		},
 	"ident": {
 			"01a_r": ["ident", "", "next"],
 			"d": ["main", "ident", "pop"]
@@ -0,0 +1,251 @@
+// Apla Software includes an integrated development
+// environment with a multi-level system for the management
+// of access rights to data, interfaces, and Smart contracts. The
+// technical characteristics of the Apla Software are indicated in
+// Apla Technical Paper.
+
+// Apla Users are granted a permission to deal in the Apla
+// Software without restrictions, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of Apla Software, and to permit persons
+// to whom Apla Software is furnished to do so, subject to the
+// following conditions:
+// * the copyright notice of GenesisKernel and EGAAS S.A.
+// and this permission notice shall be included in all copies or
+// substantial portions of the software;
+// * a result of the dealing in Apla Software cannot be
+// implemented outside of the Apla Platform environment.
+
+// THE APLA SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY
+// OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED
+// TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE, ERROR FREE AND NONINFRINGEMENT. IN
+// NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR
+// THE USE OR OTHER DEALINGS IN THE APLA SOFTWARE.
+
+package script
+
+import (
+	"fmt"
+)
+
+const (
+	// DefaultTraceSteps is the default maximum number of steps which are recorded by Tracer
+	DefaultTraceSteps = 10000
+
+	maxTraceStack = 16
+	maxTraceValue = 256
+)
+
+var cmdNames = map[uint16]string{
+	cmdPush:       `push`,
+	cmdVar:        `var`,
+	cmdExtend:     `extend`,
+	cmdCallExtend: `callextend`,
+	cmdPushStr:    `pushstr`,
+	cmdCall:       `call`,
+	cmdCallVari:   `callvari`,
+	cmdReturn:     `return`,
+	cmdIf:         `if`,
+	cmdElse:       `else`,
+	cmdAssignVar:  `assignvar`,
+	cmdAssign:     `assign`,
+	cmdLabel:      `label`,
+	cmdContinue:   `continue`,
+	cmdWhile:      `while`,
+	cmdBreak:      `break`,
+	cmdIndex:      `index`,
+	cmdSetIndex:   `setindex`,
+	cmdFuncName:   `funcname`,
+	cmdUnwrapArr:  `unwraparr`,
+	cmdMapInit:    `mapinit`,
+	cmdArrayInit:  `arrayinit`,
+	cmdError:      `error`,
+	cmdTry:        `try`,
+	cmdCatch:      `catch`,
+	cmdFor:        `for`,
+	cmdNot:        `not`,
+	cmdSign:       `sign`,
+	cmdAdd:        `add`,
+	cmdSub:        `sub`,
+	cmdMul:        `mul`,
+	cmdDiv:        `div`,
+	cmdAnd:        `and`,
+	cmdOr:         `or`,
+	cmdEqual:      `equal`,
+	cmdNotEq:      `noteq`,
+	cmdLess:       `less`,
+	cmdNotLess:    `notless`,
+	cmdGreat:      `great`,
+	cmdNotGreat:   `notgreat`,
+}
+
+// TraceStep is the state of the virtual machine before the execution of the bytecode command
+type TraceStep struct {
+	Func  string            `json:"func"`
+	Line  uint32            `json:"line"`
+	Cmd   string            `json:"cmd"`
+	Depth int               `json:"depth"`
+	Stack []string          `json:"stack,omitempty"`
+	Vars  map[string]string `json:"vars,omitempty"`
+	Fuel  int64             `json:"fuel"` // the remaining fuel
+	Cost  int64             `json:"cost"` // the fuel consumed until the next step
+}
+
+// Tracer records the executed bytecode commands. It is not safe for concurrent use.
+type Tracer struct {
+	Steps     []*TraceStep `json:"steps"`
+	Truncated bool         `json:"truncated"`
+
+	maxSteps int
+	names    map[*Block]string
+}
+
+// NewTracer creates a new tracer which records up to maxSteps steps
+func NewTracer(maxSteps int) *Tracer {
+	if maxSteps <= 0 {
+		maxSteps = DefaultTraceSteps
+	}
+	return &Tracer{maxSteps: maxSteps, names: make(map[*Block]string)}
+}
+
+// SetTracer enables tracing of the execution
+func (rt *RunTime) SetTracer(tracer *Tracer) {
+	rt.tracer = tracer
+}
+
+// Tracer returns the tracer of the execution
+func (rt *RunTime) Tracer() *Tracer {
+	return rt.tracer
+}
+
+func traceValue(v interface{}) string {
+	var s string
+	switch val := v.(type) {
+	case string:
+		s = fmt.Sprintf(`%q`, val)
+	case *Block, *ObjInfo, *VarInfo, *IndexInfo:
+		s = fmt.Sprintf(`%T`, val)
+	default:
+		s = fmt.Sprint(val)
+	}
+	if len(s) > maxTraceValue {
+		s = s[:maxTraceValue] + `...`
+	}
+	return s
+}
+
+// funcName returns the name of function or contract which contains the block
+func (t *Tracer) funcName(block *Block) string {
+	if name, ok := t.names[block]; ok {
+		return name
+	}
+	var name string
+	b := block
+	for b != nil && b.Type != ObjFunc && b.Type != ObjContract {
+		b = b.Parent
+	}
+	if b != nil {
+		if b.Type == ObjContract {
+			name = b.Info.(*ContractInfo).Name
+		} else if b.Parent != nil {
+			for key, obj := range b.Parent.Objects {
+				if obj.Type == ObjFunc && obj.Value == b {
+					name = key
+					break
+				}
+			}
+			if b.Parent.Type == ObjContract {
+				name = b.Parent.Info.(*ContractInfo).Name + `.` + name
+			}
+		}
+	}
+	t.names[block] = name
+	return name
+}
+
+// update sets the cost of the last step
+func (t *Tracer) update(fuel int64) {
+	if len(t.Steps) == 0 {
+		return
+	}
+	last := t.Steps[len(t.Steps)-1]
+	if cost := last.Fuel - fuel; cost > 0 {
+		last.Cost = cost
+	}
+}
+
+func (t *Tracer) trace(rt *RunTime, block *Block, cmd *ByteCode) {
+	t.update(rt.cost)
+	if len(t.Steps) >= t.maxSteps {
+		t.Truncated = true
+		return
+	}
+	name, ok := cmdNames[cmd.Cmd]
+	if !ok {
+		name = fmt.Sprintf(`%#x`, cmd.Cmd)
+	}
+	step := &TraceStep{
+		Func:  t.funcName(block),
+		Line:  cmd.Line,
+		Cmd:   name,
+		Depth: int(rt.callDepth),
+		Fuel:  rt.cost,
+	}
+	start := len(rt.stack) - maxTraceStack
+	if start < 0 {
+		start = 0
+	}
+	for _, v := range rt.stack[start:] {
+		step.Stack = append(step.Stack, traceValue(v))
+	}
+	for b := block; b != nil; b = b.Parent {
+		offset := -1
+		for i := len(rt.blocks) - 1; i >= 0; i-- {
+			if rt.blocks[i].Block == b {
+				offset = rt.blocks[i].Offset
+				break
+			}
+		}
+		if offset >= 0 {
+			for key, obj := range b.Objects {
+				if obj.Type != ObjVar {
+					continue
+				}
+				if _, ok := step.Vars[key]; ok {
+					continue
+				}
+				if i := offset + obj.Value.(int); i < len(rt.vars) {
+					if step.Vars == nil {
+						step.Vars = make(map[string]string)
+					}
+					step.Vars[key] = traceValue(rt.vars[i])
+				}
+			}
+		}
+		if b.Type == ObjFunc || b.Type == ObjContract {
+			break
+		}
+	}
+	t.Steps = append(t.Steps, step)
+}
+
+// FuelStat accumulates the fuel which has been spent by extended functions and database queries.
+// It is not safe for concurrent use.
+type FuelStat struct {
+	Funcs   map[string]int64 `json:"funcs"`   // the price of calls of extended functions
+	Queries map[string]int64 `json:"queries"` // the cost of database queries
+}
+
+// NewFuelStat creates a new FuelStat
+func NewFuelStat() *FuelStat {
+	return &FuelStat{Funcs: make(map[string]int64), Queries: make(map[string]int64)}
+}
+
+// SetFuelStat enables the accounting of the spent fuel
+func (rt *RunTime) SetFuelStat(fuel *FuelStat) {
+	rt.fuel = fuel
+}
@@ -0,0 +1,82 @@
+// Apla Software includes an integrated development
+// environment with a multi-level system for the management
+// of access rights to data, interfaces, and Smart contracts. The
+// technical characteristics of the Apla Software are indicated in
+// Apla Technical Paper.
+
+// Apla Users are granted a permission to deal in the Apla
+// Software without restrictions, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of Apla Software, and to permit persons
+// to whom Apla Software is furnished to do so, subject to the
+// following conditions:
+// * the copyright notice of GenesisKernel and EGAAS S.A.
+// and this permission notice shall be included in all copies or
+// substantial portions of the software;
+// * a result of the dealing in Apla Software cannot be
+// implemented outside of the Apla Platform environment.
+
+// THE APLA SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY
+// OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED
+// TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE, ERROR FREE AND NONINFRINGEMENT. IN
+// NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR
+// THE USE OR OTHER DEALINGS IN THE APLA SOFTWARE.
+
+package script
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTracer(t *testing.T) {
+	vm := NewVM()
+	block, err := vm.CompileBlock([]rune(`func test int {
+		var b int
+		b = 2 + 3
+		if b > 4 {
+			b = b * 2
+		}
+		return b
+	}`), &OwnerInfo{StateID: 1})
+	require.NoError(t, err)
+
+	tracer := NewTracer(0)
+	rt := vm.RunInit(1000)
+	rt.SetTracer(tracer)
+	ret, err := rt.Run(block.Children[0], nil, &map[string]interface{}{})
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{int64(10)}, ret)
+
+	require.NotEmpty(t, tracer.Steps)
+	assert.False(t, tracer.Truncated)
+	var (
+		lines = make(map[uint32]bool)
+		cost  int64
+	)
+	for _, step := range tracer.Steps {
+		assert.Equal(t, `test`, step.Func)
+		lines[step.Line] = true
+		cost += step.Cost
+	}
+	assert.Equal(t, map[uint32]bool{3: true, 4: true, 5: true, 7: true}, lines)
+	assert.Equal(t, tracer.Steps[0].Fuel-rt.Cost(), cost)
+
+	last := tracer.Steps[len(tracer.Steps)-1]
+	assert.Equal(t, `return`, last.Cmd)
+	assert.Equal(t, `10`, last.Vars[`b`])
+
+	tracer = NewTracer(2)
+	rt = vm.RunInit(1000)
+	rt.SetTracer(tracer)
+	_, err = rt.Run(block.Children[0], nil, &map[string]interface{}{})
+	require.NoError(t, err)
+	assert.Len(t, tracer.Steps, 2)
+	assert.True(t, tracer.Truncated)
+}
@@ -34,6 +34,7 @@ import (
 	"fmt"
 	"reflect"
 	"runtime/debug"
+	"sort"
 	"strconv"
 	"strings"
 	"unsafe"
@@ -113,6 +114,9 @@ type RunTime struct {
 	callDepth uint16
 	mem       int64
 	memVars   map[interface{}]int64
+	tracer    *Tracer
+	fuel      *FuelStat
+	costs     OpCosts
 }
 
 func isSysVar(name string) bool {
@@ -264,6 +268,9 @@ func (rt *RunTime) callFunc(cmd uint16, obj *ObjInfo) (err error) {
 					}
 
 					rt.cost -= cost
+					if rt.fuel != nil {
+						rt.fuel.Queries[finfo.Name] += cost
+					}
 					continue
 				}
 			}
@@ -466,6 +473,7 @@ func (vm *VM) RunInit(cost int64) *RunTime {
 		vm:      vm,
 		cost:    cost,
 		memVars: make(map[interface{}]int64),
+		costs:   GetOpCosts(),
 	}
 	return &rt
 }
@@ -562,6 +570,163 @@ func isSelfAssignment(dest, value interface{}) bool {
 	return false
 }
 
+// runTry executes the try block. If the block fails then its changes are rolled back and
+// the error is returned as caught for the catch block. Exceeding the limits of the resources can't be caught.
+func (rt *RunTime) runTry(block *Block) (status int, caught error, err error) {
+	var (
+		sp Savepointer
+		id int
+	)
+	if rt.extend != nil {
+		sp, _ = (*rt.extend)[`sc`].(Savepointer)
+	}
+	if sp != nil {
+		if id, err = sp.Savepoint(); err != nil {
+			return
+		}
+	}
+	blocks, stack := len(rt.blocks), len(rt.stack)
+	status, caught = rt.RunCode(block)
+	if caught == nil {
+		if sp != nil {
+			err = sp.ReleaseSavepoint(id)
+		}
+		return
+	}
+	if rt.cost <= 0 || rt.mem > memoryLimit {
+		return status, nil, caught
+	}
+	// the failed blocks can leave their data in the stacks
+	rt.blocks, rt.stack = rt.blocks[:blocks], rt.stack[:stack]
+	rt.err = nil
+	if sp != nil {
+		err = sp.RollbackSavepoint(id)
+	}
+	return statusNormal, caught, err
+}
+
+// errorToMap converts the error of try block to the map with type, code and message
+func errorToMap(err error) *types.Map {
+	var info struct {
+		Type  string `json:"type"`
+		Code  string `json:"id"`
+		Error string `json:"error"`
+	}
+	if coder, ok := err.(ErrorCoder); ok {
+		info.Type, info.Code, info.Error = coder.ErrorType(), coder.ErrorCode(), err.Error()
+	} else if errText := err.Error(); !strings.HasPrefix(errText, `{`) ||
+		json.Unmarshal([]byte(errText), &info) != nil {
+		info.Type, info.Error = `panic`, errText
+	}
+	ret := types.NewMap()
+	ret.Set(`type`, info.Type)
+	ret.Set(`code`, info.Code)
+	ret.Set(`message`, info.Error)
+	return ret
+}
+
+func (rt *RunTime) setVarInfo(item *VarInfo, value interface{}) {
+	for i := len(rt.blocks) - 1; i >= 0; i-- {
+		if item.Owner == rt.blocks[i].Block {
+			rt.setVar(rt.blocks[i].Offset+item.Obj.Value.(int), value)
+			break
+		}
+	}
+}
+
+// forIterator returns the function which sets the variables of the loop for the next item
+func (rt *RunTime) forIterator(info *ForInfo) (next func() bool, err error) {
+	var i int64
+	if info.Range {
+		var from, to int64
+		from, err = forBound(rt.stack[len(rt.stack)-2])
+		if err == nil {
+			to, err = forBound(rt.stack[len(rt.stack)-1])
+		}
+		rt.stack = rt.stack[:len(rt.stack)-2]
+		if err != nil {
+			return
+		}
+		i = from
+		return func() bool {
+			if i >= to {
+				return false
+			}
+			rt.setVarInfo(info.Vars[0], i)
+			i++
+			return true
+		}, nil
+	}
+	val := rt.stack[len(rt.stack)-1]
+	rt.stack = rt.stack[:len(rt.stack)-1]
+	switch v := val.(type) {
+	case []interface{}:
+		return func() bool {
+			if i >= int64(len(v)) {
+				return false
+			}
+			if len(info.Vars) == 1 {
+				rt.setVarInfo(info.Vars[0], v[i])
+			} else {
+				rt.setVarInfo(info.Vars[0], i)
+				rt.setVarInfo(info.Vars[1], v[i])
+			}
+			i++
+			return true
+		}, nil
+	case *types.Map:
+		// the keys are sorted so the order of the iterations does not depend on the history of the map
+		keys := v.Keys()
+		sort.Strings(keys)
+		return func() bool {
+			if i >= int64(len(keys)) {
+				return false
+			}
+			rt.setVarInfo(info.Vars[0], keys[i])
+			if len(info.Vars) == 2 {
+				item, _ := v.Get(keys[i])
+				rt.setVarInfo(info.Vars[1], item)
+			}
+			i++
+			return true
+		}, nil
+	}
+	return nil, fmt.Errorf(eForType, reflect.TypeOf(val))
+}
+
+func forBound(v interface{}) (int64, error) {
+	switch val := v.(type) {
+	case int64:
+		return val, nil
+	case int:
+		return int64(val), nil
+	}
+	return 0, errForRange
+}
+
+// runFor executes the body of for loop for each item. The fuel is charged for every iteration.
+func (rt *RunTime) runFor(info *ForInfo) (status int, err error) {
+	next, err := rt.forIterator(info)
+	if err != nil {
+		return
+	}
+	for next() {
+		rt.cost -= rt.costs.Iteration
+		if rt.cost <= 0 {
+			rt.vm.logger.WithFields(log.Fields{"type": consts.VMError}).Warn("paid CPU resource is over")
+			return 0, fmt.Errorf(`paid CPU resource is over`)
+		}
+		status, err = rt.RunCode(info.Block)
+		if err != nil || status == statusReturn {
+			return
+		}
+		if status == statusBreak {
+			break
+		}
+	}
+	return statusNormal, nil
+}
+
 // RunCode executes Block
 func (rt *RunTime) RunCode(block *Block) (status int, err error) {
 	top := make([]interface{}, 8)
@@ -610,6 +775,7 @@ func (rt *RunTime) RunCode(block *Block) (status int, err error) {
 		assign []*VarInfo
 		tmpInt int64
 		tmpDec decimal.Decimal
+		tryErr error
 	)
 	labels := make([]int, 0)
 	for ci := 0; ci < len(block.Code); ci++ {
@@ -625,6 +791,9 @@ func (rt *RunTime) RunCode(block *Block) (status int, err error) {
 		}
 
 		cmd := block.Code[ci]
+		if rt.tracer != nil {
+			rt.tracer.trace(rt, block, cmd)
+		}
 		var bin interface{}
 		size := len(rt.stack)
 		if size < int(cmd.Cmd>>8) {
@@ -706,6 +875,19 @@ func (rt *RunTime) RunCode(block *Block) (status int, err error) {
 			}
 		case cmdReturn:
 			status = statusReturn
+		case cmdTry:
+			status, tryErr, err = rt.runTry(cmd.Value.(*Block))
+		case cmdCatch:
+			if tryErr != nil {
+				catch := cmd.Value.(*CatchInfo)
+				if catch.Var != nil {
+					rt.setVarInfo(catch.Var, errorToMap(tryErr))
+				}
+				tryErr = nil
+				status, err = rt.RunCode(catch.Block)
+			}
+		case cmdFor:
+			status, err = rt.runFor(cmd.Value.(*ForInfo))
 		case cmdError:
 			eType := msgError
 			if cmd.Value.(uint32) == keyWarning {
@@ -744,13 +926,15 @@ func (rt *RunTime) RunCode(block *Block) (status int, err error) {
 						rt.vm.logger.WithFields(log.Fields{"type": consts.VMError}).Warning("paid CPU resource is over")
 						return 0, fmt.Errorf(`paid CPU resource is over`)
 					} else if cost == -1 {
-						rt.cost -= CostCall
-					} else {
-						rt.cost -= cost
+						cost = rt.costs.Call
+					}
+					rt.cost -= cost
+					if rt.fuel != nil {
+						rt.fuel.Funcs[finfo.Name] += cost
 					}
 				}
 			} else {
-				rt.cost -= CostCall
+				rt.cost -= rt.costs.Call
 			}
 			err = rt.callFunc(cmd.Cmd, cmd.Value.(*ObjInfo))
 
@@ -769,7 +953,7 @@ func (rt *RunTime) RunCode(block *Block) (status int, err error) {
 			}
 		case cmdExtend, cmdCallExtend:
 			if val, ok := (*rt.extend)[cmd.Value.(string)]; ok {
-				rt.cost -= CostExtend
+				rt.cost -= rt.costs.Extend
 				if cmd.Cmd == cmdCallExtend {
 					err = rt.extendFunc(cmd.Value.(string))
 					if err != nil {
@@ -1265,7 +1449,11 @@ func (rt *RunTime) Run(block *Block, params []interface{}, extend *map[string]in
 	}()
 	info := block.Info.(*FuncInfo)
 	rt.extend = extend
-	if _, err = rt.RunCode(block); err == nil {
+	_, err = rt.RunCode(block)
+	if rt.tracer != nil {
+		rt.tracer.update(rt.cost)
+	}
+	if err == nil {
 		off := len(rt.stack) - len(info.Results)
 		for i := 0; i < len(info.Results); i++ {
 			ret = append(ret, rt.stack[off+i])
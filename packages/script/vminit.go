@@ -41,9 +41,10 @@ import (
 	log "github.com/sirupsen/logrus"
 )
 
-// ByteCode stores a command and an additional parameter.
+// ByteCode stores a command, the line of the source code and an additional parameter.
 type ByteCode struct {
 	Cmd   uint16
+	Line  uint32
 	Value interface{}
 }
 
@@ -69,12 +70,14 @@ const (
 	// ObjExtend is an extended variable. $myvar
 	ObjExtend
 
-	// CostCall is the cost of the function calling
+	// CostCall is the default cost of the function calling
 	CostCall = 50
-	// CostContract is the cost of the contract calling
+	// CostContract is the default cost of the contract calling
 	CostContract = 100
-	// CostExtend is the cost of the extend function calling
+	// CostExtend is the default cost of the extend function calling
 	CostExtend = 10
+	// CostIteration is the default cost of the iteration of for loop
+	CostIteration = 3
 
 	// VMTypeSmart is smart vm type
 	VMTypeSmart VMType = 1
@@ -153,6 +156,19 @@ type VarInfo struct {
 	Owner *Block
 }
 
+// CatchInfo contains the catch block and the variable which gets the error of try block
+type CatchInfo struct {
+	Block *Block
+	Var   *VarInfo
+}
+
+// ForInfo contains the body of for loop and the variables which get the items
+type ForInfo struct {
+	Block *Block
+	Vars  []*VarInfo
+	Range bool // if true then the loop goes through the integers from..to
+}
+
 // IndexInfo contains the information for SetIndex
 type IndexInfo struct {
 	VarOffset int
@@ -200,6 +216,48 @@ type VM struct {
 	logger        *log.Entry
 }
 
+// The names of VM operations in the fuel schedule
+const (
+	OpCall      = `call`
+	OpContract  = `contract`
+	OpExtend    = `extend`
+	OpIteration = `iteration`
+)
+
+// OpCosts contains the fuel costs of VM operations
+type OpCosts struct {
+	Call      int64
+	Contract  int64
+	Extend    int64
+	Iteration int64
+}
+
+// DefaultOpCosts returns the default costs of VM operations by their names
+func DefaultOpCosts() map[string]int64 {
+	return map[string]int64{
+		OpCall:      CostCall,
+		OpContract:  CostContract,
+		OpExtend:    CostExtend,
+		OpIteration: CostIteration,
+	}
+}
+
+// GetOpCosts returns the costs of VM operations which are defined in fuel_schedule system parameter
+func GetOpCosts() OpCosts {
+	cost := func(name string, def int64) int64 {
+		if val, ok := syspar.GetOpCost(name); ok {
+			return val
+		}
+		return def
+	}
+	return OpCosts{
+		Call:      cost(OpCall, CostCall),
+		Contract:  cost(OpContract, CostContract),
+		Extend:    cost(OpExtend, CostExtend),
+		Iteration: cost(OpIteration, CostIteration),
+	}
+}
+
 // ExtendData is used for the definition of the extended functions and variables
 type ExtendData struct {
 	Objects    map[string]interface{}
@@ -213,6 +271,19 @@ type Stacker interface {
 	PopStack(fn string)
 }
 
+// Savepointer represents interface for rolling back the changes of the failed try block
+type Savepointer interface {
+	Savepoint() (int, error)
+	RollbackSavepoint(id int) error
+	ReleaseSavepoint(id int) error
+}
+
+// ErrorCoder represents the error which passes its type and code to the catch block
+type ErrorCoder interface {
+	ErrorType() string
+	ErrorCode() string
+}
+
 // ExecContract runs the name contract where txs contains the list of parameters and
 // params are the values of parameters
 func ExecContract(rt *RunTime, name, txs string, params ...interface{}) (interface{}, error) {
@@ -248,6 +319,22 @@ func ExecContract(rt *RunTime, name, txs string, params ...interface{}) (interfa
 		prevExtend[key] = item
 		delete(*rt.extend, key)
 	}
+	prevthis := (*rt.extend)[`this_contract`]
+	prevparent := (*rt.extend)[`parent`]
+	// the variables of the caller are restored even if the contract fails because the error can be caught
+	defer func() {
+		(*rt.extend)[`parent`] = prevparent
+		(*rt.extend)[`this_contract`] = prevthis
+		for key := range *rt.extend {
+			if isSysVar(key) {
+				continue
+			}
+			delete(*rt.extend, key)
+		}
+		for key, item := range prevExtend {
+			(*rt.extend)[key] = item
+		}
+	}()
 
 	var isSignature bool
 	if cblock.Info.(*ContractInfo).Tx != nil {
@@ -267,11 +354,9 @@ func ExecContract(rt *RunTime, name, txs string, params ...interface{}) (interfa
 	for i, ipar := range pars {
 		(*rt.extend)[ipar] = params[i]
 	}
-	prevthis := (*rt.extend)[`this_contract`]
 	_, nameContract := converter.ParseName(name)
 	(*rt.extend)[`this_contract`] = nameContract
 
-	prevparent := (*rt.extend)[`parent`]
 	parent := ``
 	for i := len(rt.blocks) - 1; i >= 0; i-- {
 		if rt.blocks[i].Block.Type == ObjFunc && rt.blocks[i].Block.Parent != nil &&
@@ -289,13 +374,14 @@ func ExecContract(rt *RunTime, name, txs string, params ...interface{}) (interfa
 			break
 		}
 	}
-	rt.cost -= CostContract
+	rt.cost -= rt.costs.Contract
 
 	var stack Stacker
 	if stack, ok = (*rt.extend)["sc"].(Stacker); ok {
 		if err := stack.AppendStack(name); err != nil {
 			return nil, err
 		}
+		defer stack.PopStack(name)
 	}
 	if (*rt.extend)[`sc`] != nil && isSignature {
 		obj := rt.vm.Objects[`check_signature`]
@@ -308,6 +394,8 @@ func ExecContract(rt *RunTime, name, txs string, params ...interface{}) (interfa
 	for _, method := range []string{`conditions`, `action`} {
 		if block, ok := (*cblock).Objects[method]; ok && block.Type == ObjFunc {
 			rtemp := rt.vm.RunInit(rt.cost)
+			rtemp.tracer = rt.tracer
+			rtemp.fuel = rt.fuel
 			(*rt.extend)[`parent`] = parent
 			_, err := rtemp.Run(block.Value.(*Block), nil, rt.extend)
 			rt.cost = rtemp.cost
@@ -317,25 +405,7 @@ func ExecContract(rt *RunTime, name, txs string, params ...interface{}) (interfa
 			}
 		}
 	}
-	if stack != nil {
-		stack.PopStack(name)
-	}
-	(*rt.extend)[`parent`] = prevparent
-	(*rt.extend)[`this_contract`] = prevthis
-
-	result := (*rt.extend)[`result`]
-	for key := range *rt.extend {
-		if isSysVar(key) {
-			continue
-		}
-		delete(*rt.extend, key)
-	}
-
-	for key, item := range prevExtend {
-		(*rt.extend)[key] = item
-	}
-
-	return result, nil
+	return (*rt.extend)[`result`], nil
 }
 
 // NewVM creates a new virtual machine
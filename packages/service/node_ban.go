@@ -34,10 +34,9 @@ import (
 
 	"github.com/AplaProject/go-apla/packages/conf"
 	"github.com/AplaProject/go-apla/packages/conf/syspar"
-	"github.com/AplaProject/go-apla/packages/consts"
 	"github.com/AplaProject/go-apla/packages/script"
+	"github.com/AplaProject/go-apla/packages/signer"
 	"github.com/AplaProject/go-apla/packages/smart"
-	"github.com/AplaProject/go-apla/packages/utils"
 	"github.com/AplaProject/go-apla/packages/utils/tx"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
@@ -140,14 +139,6 @@ func (nbs *NodesBanService) localBan(node syspar.FullNode) {
 }
 
 func (nbs *NodesBanService) newBadBlock(producer syspar.FullNode, blockId, blockTime int64, reason string) error {
-	nodePrivateKey, err := utils.GetNodePrivateKey()
-	if err != nil || len(nodePrivateKey) < 1 {
-		if err == nil {
-			log.WithFields(log.Fields{"type": consts.EmptyObject}).Error("node private key is empty")
-		}
-		return err
-	}
-
 	var currentNode syspar.FullNode
 	nbs.m.Lock()
 	for _, fn := range nbs.fullNodes {
@@ -182,7 +173,7 @@ func (nbs *NodesBanService) newBadBlock(producer syspar.FullNode, blockId, block
 		},
 	}
 
-	txData, txHash, err := tx.NewInternalTransaction(sc, nodePrivateKey)
+	txData, txHash, err := tx.NewInternalTransaction(sc, signer.Node())
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,67 @@
+// Apla Software includes an integrated development
+// environment with a multi-level system for the management
+// of access rights to data, interfaces, and Smart contracts. The
+// technical characteristics of the Apla Software are indicated in
+// Apla Technical Paper.
+
+// Apla Users are granted a permission to deal in the Apla
+// Software without restrictions, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of Apla Software, and to permit persons
+// to whom Apla Software is furnished to do so, subject to the
+// following conditions:
+// * the copyright notice of GenesisKernel and EGAAS S.A.
+// and this permission notice shall be included in all copies or
+// substantial portions of the software;
+// * a result of the dealing in Apla Software cannot be
+// implemented outside of the Apla Platform environment.
+
+// THE APLA SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY
+// OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED
+// TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE, ERROR FREE AND NONINFRINGEMENT. IN
+// NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR
+// THE USE OR OTHER DEALINGS IN THE APLA SOFTWARE.
+
+package signer
+
+import "github.com/AplaProject/go-apla/packages/keystore"
+
+// FileSigner signs the data by the private key which is stored in the file as hex or as the keystore
+type FileSigner struct {
+	path string
+}
+
+// NewFileSigner returns the signer of the key file
+func NewFileSigner(path string) *FileSigner {
+	return &FileSigner{path: path}
+}
+
+func (s *FileSigner) key() (*KeySigner, error) {
+	privateKey, err := keystore.ReadFile(s.path)
+	if err != nil {
+		return nil, err
+	}
+	return NewKeySigner(privateKey), nil
+}
+
+// PublicKey returns the public key of the signer
+func (s *FileSigner) PublicKey() ([]byte, error) {
+	k, err := s.key()
+	if err != nil {
+		return nil, err
+	}
+	return k.PublicKey()
+}
+
+// Sign returns the signature of the data
+func (s *FileSigner) Sign(data []byte) ([]byte, error) {
+	k, err := s.key()
+	if err != nil {
+		return nil, err
+	}
+	return k.Sign(data)
+}
@@ -0,0 +1,267 @@
+// Apla Software includes an integrated development
+// environment with a multi-level system for the management
+// of access rights to data, interfaces, and Smart contracts. The
+// technical characteristics of the Apla Software are indicated in
+// Apla Technical Paper.
+
+// Apla Users are granted a permission to deal in the Apla
+// Software without restrictions, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of Apla Software, and to permit persons
+// to whom Apla Software is furnished to do so, subject to the
+// following conditions:
+// * the copyright notice of GenesisKernel and EGAAS S.A.
+// and this permission notice shall be included in all copies or
+// substantial portions of the software;
+// * a result of the dealing in Apla Software cannot be
+// implemented outside of the Apla Platform environment.
+
+// THE APLA SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY
+// OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED
+// TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE, ERROR FREE AND NONINFRINGEMENT. IN
+// NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR
+// THE USE OR OTHER DEALINGS IN THE APLA SOFTWARE.
+
+package signer
+
+import (
+	"crypto/subtle"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"strings"
+
+	"github.com/AplaProject/go-apla/packages/consts"
+
+	log "github.com/sirupsen/logrus"
+)
+
+/*
+	Protocol of the remote signer, the connection can serve several requests
+	request:
+		op     1 byte (1 - public key, 2 - sign)
+		token  4 bytes length + data
+		data   4 bytes length + data
+	response:
+		status 1 byte (0 - ok, 1 - error)
+		data   4 bytes length + public key, signature or text of the error
+*/
+
+const (
+	opPublicKey byte = 1
+	opSign      byte = 2
+
+	statusOK    byte = 0
+	statusError byte = 1
+
+	maxMessageSize = 1 << 20
+)
+
+var (
+	// ErrMessageSize is returned if the message of the protocol is too big
+	ErrMessageSize = errors.New("Message size is too big")
+	// ErrAccessDenied is returned if the token is wrong
+	ErrAccessDenied = errors.New("Access denied")
+	// ErrUnknownOp is returned if the operation is unknown
+	ErrUnknownOp = errors.New("Unknown operation")
+	// ErrNoToken is returned if the token of the tcp signer is empty
+	ErrNoToken = errors.New("Token is required for tcp signer")
+	// ErrNoTLS is returned if the certificate of the tcp signer isn't specified
+	ErrNoTLS = errors.New("TLS certificate is required for tcp signer")
+)
+
+type request struct {
+	Op    byte
+	Token []byte
+	Data  []byte
+}
+
+type response struct {
+	Status byte
+	Data   []byte
+}
+
+func writeSlice(w io.Writer, data []byte) error {
+	if err := binary.Write(w, binary.LittleEndian, uint32(len(data))); err != nil {
+		return err
+	}
+	_, err := w.Write(data)
+	return err
+}
+
+func readSlice(r io.Reader) ([]byte, error) {
+	var size uint32
+	if err := binary.Read(r, binary.LittleEndian, &size); err != nil {
+		return nil, err
+	}
+	if size > maxMessageSize {
+		return nil, ErrMessageSize
+	}
+	data := make([]byte, size)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (req *request) Write(w io.Writer) error {
+	if _, err := w.Write([]byte{req.Op}); err != nil {
+		return err
+	}
+	if err := writeSlice(w, req.Token); err != nil {
+		return err
+	}
+	return writeSlice(w, req.Data)
+}
+
+func (req *request) Read(r io.Reader) (err error) {
+	op := make([]byte, 1)
+	if _, err = io.ReadFull(r, op); err != nil {
+		return
+	}
+	req.Op = op[0]
+	if req.Token, err = readSlice(r); err != nil {
+		return
+	}
+	req.Data, err = readSlice(r)
+	return
+}
+
+func (resp *response) Write(w io.Writer) error {
+	if _, err := w.Write([]byte{resp.Status}); err != nil {
+		return err
+	}
+	return writeSlice(w, resp.Data)
+}
+
+func (resp *response) Read(r io.Reader) (err error) {
+	status := make([]byte, 1)
+	if _, err = io.ReadFull(r, status); err != nil {
+		return
+	}
+	resp.Status = status[0]
+	resp.Data, err = readSlice(r)
+	return
+}
+
+// parseAddress returns the network and the address, unix:///path and tcp://host:port are supported.
+// The address without the scheme is tcp address
+func parseAddress(address string) (string, string) {
+	if strings.HasPrefix(address, "unix://") {
+		return "unix", strings.TrimPrefix(address, "unix://")
+	}
+	return "tcp", strings.TrimPrefix(address, "tcp://")
+}
+
+// Listen listens the address of the remote signer. The tcp address requires the token
+// and the TLS certificate with the private key, the unix socket is only accessible by the owner.
+func Listen(address, token, certFile, keyFile string) (net.Listener, error) {
+	var (
+		l   net.Listener
+		err error
+	)
+	network, addr := parseAddress(address)
+	if network == "unix" {
+		// removes the socket which is left by the previous process
+		os.Remove(addr)
+		l, err = net.Listen(network, addr)
+	} else {
+		if len(token) == 0 {
+			log.WithFields(log.Fields{"type": consts.AccessDenied, "address": address}).Error("token of tcp signer is empty")
+			return nil, ErrNoToken
+		}
+		if len(certFile) == 0 || len(keyFile) == 0 {
+			log.WithFields(log.Fields{"type": consts.AccessDenied, "address": address}).Error("certificate of tcp signer is empty")
+			return nil, ErrNoTLS
+		}
+		var cert tls.Certificate
+		if cert, err = tls.LoadX509KeyPair(certFile, keyFile); err != nil {
+			log.WithFields(log.Fields{"type": consts.CryptoError, "error": err, "cert": certFile}).Error("loading certificate of signer")
+			return nil, err
+		}
+		l, err = tls.Listen(network, addr, &tls.Config{
+			Certificates: []tls.Certificate{cert},
+			MinVersion:   tls.VersionTLS12,
+		})
+	}
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.ConnectionError, "error": err, "address": address}).Error("listening signer address")
+		return nil, err
+	}
+	if network == "unix" {
+		if err = os.Chmod(addr, 0600); err != nil {
+			l.Close()
+			log.WithFields(log.Fields{"type": consts.IOError, "error": err, "address": address}).Error("changing mode of signer socket")
+			return nil, err
+		}
+	}
+	return l, nil
+}
+
+// Serve serves the requests of the remote signers by the signer s.
+// The requests with the token which differs from token are refused.
+// The empty token is allowed only for unix sockets.
+func Serve(l net.Listener, s Signer, token string) error {
+	if len(token) == 0 && l.Addr().Network() != "unix" {
+		log.WithFields(log.Fields{"type": consts.AccessDenied, "address": l.Addr().String()}).Error("token of tcp signer is empty")
+		return ErrNoToken
+	}
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			log.WithFields(log.Fields{"type": consts.ConnectionError, "error": err}).Error("accepting signer connection")
+			return err
+		}
+		go func(conn net.Conn) {
+			defer conn.Close()
+			ServeConn(conn, s, token)
+		}(conn)
+	}
+}
+
+// ServeConn serves the requests of the connection
+func ServeConn(rw io.ReadWriter, s Signer, token string) {
+	for {
+		req := &request{}
+		if err := req.Read(rw); err != nil {
+			if err != io.EOF {
+				log.WithFields(log.Fields{"type": consts.ProtocolError, "error": err}).Warning("reading signer request")
+			}
+			return
+		}
+
+		resp := &response{Status: statusOK}
+		var err error
+		if subtle.ConstantTimeCompare(req.Token, []byte(token)) != 1 {
+			log.WithFields(log.Fields{"type": consts.AccessDenied}).Warning("wrong token of signer request")
+			err = ErrAccessDenied
+		} else {
+			switch req.Op {
+			case opPublicKey:
+				resp.Data, err = s.PublicKey()
+			case opSign:
+				resp.Data, err = s.Sign(req.Data)
+			default:
+				err = ErrUnknownOp
+			}
+		}
+		if err != nil {
+			resp = &response{Status: statusError, Data: []byte(err.Error())}
+		}
+
+		if werr := resp.Write(rw); werr != nil {
+			log.WithFields(log.Fields{"type": consts.IOError, "error": werr}).Warning("writing signer response")
+			return
+		}
+		if err == ErrAccessDenied || err == ErrUnknownOp {
+			return
+		}
+	}
+}
@@ -0,0 +1,168 @@
+// Apla Software includes an integrated development
+// environment with a multi-level system for the management
+// of access rights to data, interfaces, and Smart contracts. The
+// technical characteristics of the Apla Software are indicated in
+// Apla Technical Paper.
+
+// Apla Users are granted a permission to deal in the Apla
+// Software without restrictions, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of Apla Software, and to permit persons
+// to whom Apla Software is furnished to do so, subject to the
+// following conditions:
+// * the copyright notice of GenesisKernel and EGAAS S.A.
+// and this permission notice shall be included in all copies or
+// substantial portions of the software;
+// * a result of the dealing in Apla Software cannot be
+// implemented outside of the Apla Platform environment.
+
+// THE APLA SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY
+// OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED
+// TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE, ERROR FREE AND NONINFRINGEMENT. IN
+// NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR
+// THE USE OR OTHER DEALINGS IN THE APLA SOFTWARE.
+
+package signer
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"io/ioutil"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/AplaProject/go-apla/packages/consts"
+	"github.com/AplaProject/go-apla/packages/crypto"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const defaultRemoteTimeout = 5 * time.Second
+
+// ErrWrongSign is returned if the remote signer returns the wrong signature
+var ErrWrongSign = errors.New("Wrong signature of remote signer")
+
+// RemoteSigner requests the signatures from the signer which is running in the separate process
+type RemoteSigner struct {
+	network string
+	address string
+	token   string
+	timeout time.Duration
+	tls     *tls.Config
+
+	mutex     sync.Mutex
+	publicKey []byte
+}
+
+// NewRemoteSigner returns the signer which is listening the address,
+// unix:///path and tcp://host:port are supported. The tcp signer requires the token and
+// the file of its TLS certificate or the certificate of its CA.
+func NewRemoteSigner(address, token, caFile string, timeout time.Duration) (*RemoteSigner, error) {
+	if timeout <= 0 {
+		timeout = defaultRemoteTimeout
+	}
+	network, addr := parseAddress(address)
+	s := &RemoteSigner{
+		network: network,
+		address: addr,
+		token:   token,
+		timeout: timeout,
+	}
+	if network == "unix" {
+		return s, nil
+	}
+
+	if len(token) == 0 {
+		log.WithFields(log.Fields{"type": consts.AccessDenied, "address": address}).Error("token of tcp signer is empty")
+		return nil, ErrNoToken
+	}
+	if len(caFile) == 0 {
+		log.WithFields(log.Fields{"type": consts.AccessDenied, "address": address}).Error("certificate of tcp signer is empty")
+		return nil, ErrNoTLS
+	}
+	ca, err := ioutil.ReadFile(caFile)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.IOError, "error": err, "path": caFile}).Error("reading certificate of signer")
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(ca) {
+		log.WithFields(log.Fields{"type": consts.CryptoError, "path": caFile}).Error("parsing certificate of signer")
+		return nil, ErrNoTLS
+	}
+	s.tls = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+	return s, nil
+}
+
+func (s *RemoteSigner) dial() (net.Conn, error) {
+	if s.tls != nil {
+		return tls.DialWithDialer(&net.Dialer{Timeout: s.timeout}, s.network, s.address, s.tls)
+	}
+	return net.DialTimeout(s.network, s.address, s.timeout)
+}
+
+func (s *RemoteSigner) request(op byte, data []byte) ([]byte, error) {
+	conn, err := s.dial()
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.ConnectionError, "error": err, "address": s.address}).Error("dialing remote signer")
+		return nil, err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(s.timeout))
+
+	req := &request{Op: op, Token: []byte(s.token), Data: data}
+	if err = req.Write(conn); err != nil {
+		log.WithFields(log.Fields{"type": consts.IOError, "error": err, "address": s.address}).Error("sending request to remote signer")
+		return nil, err
+	}
+
+	resp := &response{}
+	if err = resp.Read(conn); err != nil {
+		log.WithFields(log.Fields{"type": consts.IOError, "error": err, "address": s.address}).Error("reading response of remote signer")
+		return nil, err
+	}
+	if resp.Status != statusOK {
+		err = errors.New(string(resp.Data))
+		log.WithFields(log.Fields{"type": consts.CryptoError, "error": err, "address": s.address}).Error("remote signer refused request")
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+// PublicKey returns the public key of the signer
+func (s *RemoteSigner) PublicKey() ([]byte, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.publicKey == nil {
+		publicKey, err := s.request(opPublicKey, nil)
+		if err != nil {
+			return nil, err
+		}
+		s.publicKey = publicKey
+	}
+	return s.publicKey, nil
+}
+
+// Sign returns the signature of the data, the signature is checked by the public key of the signer
+func (s *RemoteSigner) Sign(data []byte) ([]byte, error) {
+	publicKey, err := s.PublicKey()
+	if err != nil {
+		return nil, err
+	}
+	sign, err := s.request(opSign, data)
+	if err != nil {
+		return nil, err
+	}
+	if ok, err := crypto.CheckSign(publicKey, data, sign); !ok || err != nil {
+		log.WithFields(log.Fields{"type": consts.CryptoError, "error": err, "address": s.address}).Error("checking signature of remote signer")
+		return nil, ErrWrongSign
+	}
+	return sign, nil
+}
@@ -0,0 +1,127 @@
+// Apla Software includes an integrated development
+// environment with a multi-level system for the management
+// of access rights to data, interfaces, and Smart contracts. The
+// technical characteristics of the Apla Software are indicated in
+// Apla Technical Paper.
+
+// Apla Users are granted a permission to deal in the Apla
+// Software without restrictions, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of Apla Software, and to permit persons
+// to whom Apla Software is furnished to do so, subject to the
+// following conditions:
+// * the copyright notice of GenesisKernel and EGAAS S.A.
+// and this permission notice shall be included in all copies or
+// substantial portions of the software;
+// * a result of the dealing in Apla Software cannot be
+// implemented outside of the Apla Platform environment.
+
+// THE APLA SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY
+// OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED
+// TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE, ERROR FREE AND NONINFRINGEMENT. IN
+// NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR
+// THE USE OR OTHER DEALINGS IN THE APLA SOFTWARE.
+
+package signer
+
+import (
+	"errors"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/AplaProject/go-apla/packages/conf"
+	"github.com/AplaProject/go-apla/packages/consts"
+	"github.com/AplaProject/go-apla/packages/crypto"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// TypeFile is the signer which reads the node key from KeysDir
+	TypeFile = "file"
+	// TypeRemote is the signer which is running in the separate process
+	TypeRemote = "remote"
+)
+
+// ErrUnknownType is returned if the type of the signer is unknown
+var ErrUnknownType = errors.New("Unknown type of signer")
+
+// Signer signs the data by the key of the node
+type Signer interface {
+	// PublicKey returns the public key of the signer
+	PublicKey() ([]byte, error)
+	// Sign returns the signature of the data, it's the same as crypto.Sign
+	Sign(data []byte) ([]byte, error)
+}
+
+var (
+	mutex sync.RWMutex
+	node  Signer
+)
+
+// Init sets the signer of the node from the config
+func Init(cfg conf.SignerConfig) error {
+	var s Signer
+	switch cfg.Type {
+	case "", TypeFile:
+		s = NewFileSigner(filepath.Join(conf.Config.KeysDir, consts.NodePrivateKeyFilename))
+	case TypeRemote:
+		remote, err := NewRemoteSigner(cfg.Address, cfg.Token, cfg.CAFile, time.Duration(cfg.Timeout)*time.Second)
+		if err != nil {
+			return err
+		}
+		s = remote
+	default:
+		log.WithFields(log.Fields{"type": consts.ParameterExceeded, "signer": cfg.Type}).Error("unknown type of signer")
+		return ErrUnknownType
+	}
+
+	SetNode(s)
+	return nil
+}
+
+// SetNode sets the signer of the node
+func SetNode(s Signer) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	node = s
+}
+
+// Node returns the signer of the node. The node key from KeysDir is used if the signer hasn't been set
+func Node() Signer {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	if node == nil {
+		return NewFileSigner(filepath.Join(conf.Config.KeysDir, consts.NodePrivateKeyFilename))
+	}
+	return node
+}
+
+// KeySigner signs the data by the private key in memory
+type KeySigner struct {
+	privateKey []byte
+}
+
+// NewKeySigner returns the signer of the private key
+func NewKeySigner(privateKey []byte) *KeySigner {
+	return &KeySigner{privateKey: privateKey}
+}
+
+// PublicKey returns the public key of the signer
+func (s *KeySigner) PublicKey() ([]byte, error) {
+	publicKey, err := crypto.PrivateToPublic(s.privateKey)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.CryptoError, "error": err}).Error("converting private key to public")
+	}
+	return publicKey, err
+}
+
+// Sign returns the signature of the data
+func (s *KeySigner) Sign(data []byte) ([]byte, error) {
+	return crypto.Sign(s.privateKey, data)
+}
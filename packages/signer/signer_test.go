@@ -0,0 +1,166 @@
+// Apla Software includes an integrated development
+// environment with a multi-level system for the management
+// of access rights to data, interfaces, and Smart contracts. The
+// technical characteristics of the Apla Software are indicated in
+// Apla Technical Paper.
+
+// Apla Users are granted a permission to deal in the Apla
+// Software without restrictions, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of Apla Software, and to permit persons
+// to whom Apla Software is furnished to do so, subject to the
+// following conditions:
+// * the copyright notice of GenesisKernel and EGAAS S.A.
+// and this permission notice shall be included in all copies or
+// substantial portions of the software;
+// * a result of the dealing in Apla Software cannot be
+// implemented outside of the Apla Platform environment.
+
+// THE APLA SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY
+// OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED
+// TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE, ERROR FREE AND NONINFRINGEMENT. IN
+// NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR
+// THE USE OR OTHER DEALINGS IN THE APLA SOFTWARE.
+
+package signer
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/AplaProject/go-apla/packages/crypto"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func checkSigner(t *testing.T, s Signer, publicKey []byte) {
+	pub, err := s.PublicKey()
+	require.NoError(t, err)
+	assert.Equal(t, publicKey, pub)
+
+	data := []byte("block data")
+	sign, err := s.Sign(data)
+	require.NoError(t, err)
+	ok, err := crypto.CheckSign(publicKey, data, sign)
+	require.NoError(t, err)
+	assert.True(t, ok)
+}
+
+func TestFileSigner(t *testing.T) {
+	dir, err := ioutil.TempDir("", "signer")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	priv, pub, err := crypto.GenBytesKeys()
+	require.NoError(t, err)
+	path := filepath.Join(dir, "NodePrivateKey")
+	require.NoError(t, ioutil.WriteFile(path, []byte(hex.EncodeToString(priv)), 0600))
+
+	checkSigner(t, NewKeySigner(priv), pub)
+	checkSigner(t, NewFileSigner(path), pub)
+
+	_, err = NewFileSigner(filepath.Join(dir, "missing")).Sign([]byte("data"))
+	assert.Error(t, err)
+}
+
+func TestRemoteSigner(t *testing.T) {
+	dir, err := ioutil.TempDir("", "signer")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	priv, pub, err := crypto.GenBytesKeys()
+	require.NoError(t, err)
+
+	address := "unix://" + filepath.Join(dir, "signer.sock")
+	l, err := Listen(address, "", "", "")
+	require.NoError(t, err)
+	defer l.Close()
+	go Serve(l, NewKeySigner(priv), "secret")
+
+	checkSigner(t, newRemoteSigner(t, address, "secret", ""), pub)
+
+	_, err = newRemoteSigner(t, address, "wrong", "").Sign([]byte("data"))
+	assert.EqualError(t, err, ErrAccessDenied.Error())
+
+	_, err = newRemoteSigner(t, "unix://"+filepath.Join(dir, "missing.sock"), "secret", "").PublicKey()
+	assert.Error(t, err)
+}
+
+func newRemoteSigner(t *testing.T, address, token, caFile string) *RemoteSigner {
+	s, err := NewRemoteSigner(address, token, caFile, time.Second)
+	require.NoError(t, err)
+	return s
+}
+
+// writeCert writes the self-signed certificate of 127.0.0.1 and its key to the files
+func writeCert(t *testing.T, dir string) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "signer"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	require.NoError(t, ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	require.NoError(t, ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600))
+	return certFile, keyFile
+}
+
+func TestRemoteSignerTCP(t *testing.T) {
+	priv, pub, err := crypto.GenBytesKeys()
+	require.NoError(t, err)
+
+	dir, err := ioutil.TempDir("", "signer")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	certFile, keyFile := writeCert(t, dir)
+
+	_, err = Listen("tcp://127.0.0.1:0", "", certFile, keyFile)
+	assert.Equal(t, ErrNoToken, err)
+	_, err = Listen("tcp://127.0.0.1:0", "secret", "", "")
+	assert.Equal(t, ErrNoTLS, err)
+	_, err = NewRemoteSigner("tcp://127.0.0.1:7000", "", certFile, time.Second)
+	assert.Equal(t, ErrNoToken, err)
+	_, err = NewRemoteSigner("tcp://127.0.0.1:7000", "secret", "", time.Second)
+	assert.Equal(t, ErrNoTLS, err)
+
+	l, err := Listen("tcp://127.0.0.1:0", "secret", certFile, keyFile)
+	require.NoError(t, err)
+	defer l.Close()
+	assert.Equal(t, ErrNoToken, Serve(l, NewKeySigner(priv), ""))
+	go Serve(l, NewKeySigner(priv), "secret")
+
+	checkSigner(t, newRemoteSigner(t, l.Addr().String(), "secret", certFile), pub)
+
+	// the certificate of another signer isn't trusted
+	otherDir := filepath.Join(dir, "other")
+	require.NoError(t, os.Mkdir(otherDir, 0700))
+	otherCert, _ := writeCert(t, otherDir)
+	_, err = newRemoteSigner(t, l.Addr().String(), "secret", otherCert).PublicKey()
+	assert.Error(t, err)
+}
@@ -0,0 +1,135 @@
+// Apla Software includes an integrated development
+// environment with a multi-level system for the management
+// of access rights to data, interfaces, and Smart contracts. The
+// technical characteristics of the Apla Software are indicated in
+// Apla Technical Paper.
+
+// Apla Users are granted a permission to deal in the Apla
+// Software without restrictions, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of Apla Software, and to permit persons
+// to whom Apla Software is furnished to do so, subject to the
+// following conditions:
+// * the copyright notice of GenesisKernel and EGAAS S.A.
+// and this permission notice shall be included in all copies or
+// substantial portions of the software;
+// * a result of the dealing in Apla Software cannot be
+// implemented outside of the Apla Platform environment.
+
+// THE APLA SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY
+// OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED
+// TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE, ERROR FREE AND NONINFRINGEMENT. IN
+// NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR
+// THE USE OR OTHER DEALINGS IN THE APLA SOFTWARE.
+
+package smart
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/AplaProject/go-apla/packages/conf"
+	"github.com/AplaProject/go-apla/packages/conf/syspar"
+	"github.com/AplaProject/go-apla/packages/consts"
+	"github.com/AplaProject/go-apla/packages/script"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// The compiled contracts are stored in the files named by id of the contract.
+// Each file starts with the hash of the ecosystem, the source of the contract and the key words
+// which have been enabled by the activation parameters.
+
+func cachePath(id int64) string {
+	if len(conf.Config.ContractCacheDir) == 0 || id == 0 {
+		return ``
+	}
+	return filepath.Join(conf.Config.ContractCacheDir, fmt.Sprintf(`%d.bin`, id))
+}
+
+func cacheHash(src string, owner *script.OwnerInfo) []byte {
+	hash := sha256.Sum256([]byte(fmt.Sprintf("%d:%t:%s", owner.StateID, syspar.IsLoops(), src)))
+	return hash[:]
+}
+
+// loadCache returns the compiled block of the contract if the cache is valid
+func loadCache(vm *script.VM, src string, owner *script.OwnerInfo) *script.Block {
+	path := cachePath(owner.TableID)
+	if len(path) == 0 {
+		return nil
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.WithFields(log.Fields{"type": consts.IOError, "error": err, "path": path}).Error("reading contract cache")
+		}
+		return nil
+	}
+	hash := cacheHash(src, owner)
+	if len(data) < len(hash) || !bytes.Equal(data[:len(hash)], hash) {
+		return nil
+	}
+	root, err := vm.DecodeBlock(data[len(hash):], owner)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.VMError, "error": err, "path": path}).Warning("decoding contract cache")
+		return nil
+	}
+	return root
+}
+
+// saveCache writes the compiled block of the contract. It must be called before FlushBlock
+func saveCache(vm *script.VM, root *script.Block, src string, owner *script.OwnerInfo) {
+	path := cachePath(owner.TableID)
+	if len(path) == 0 {
+		return
+	}
+	data, err := vm.EncodeBlock(root)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.VMError, "error": err, "id": owner.TableID}).Warning("encoding contract cache")
+		return
+	}
+	if err = os.MkdirAll(conf.Config.ContractCacheDir, 0755); err != nil {
+		log.WithFields(log.Fields{"type": consts.IOError, "error": err, "path": conf.Config.ContractCacheDir}).Error("creating contract cache dir")
+		return
+	}
+	tmp := path + `.tmp`
+	if err = ioutil.WriteFile(tmp, append(cacheHash(src, owner), data...), 0600); err == nil {
+		err = os.Rename(tmp, path)
+	}
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.IOError, "error": err, "path": path}).Error("writing contract cache")
+	}
+}
+
+// dropCache removes the cache of the contract which has been changed
+func dropCache(id int64) {
+	path := cachePath(id)
+	if len(path) == 0 {
+		return
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		log.WithFields(log.Fields{"type": consts.IOError, "error": err, "path": path}).Error("removing contract cache")
+	}
+}
+
+// compileCached loads the contract from the cache or compiles it and updates the cache
+func compileCached(vm *script.VM, src string, owner *script.OwnerInfo) error {
+	root := loadCache(vm, src, owner)
+	if root == nil {
+		var err error
+		if root, err = VMCompileBlock(vm, src, owner); err != nil {
+			return err
+		}
+		saveCache(vm, root, src, owner)
+	}
+	VMFlushBlock(vm, root)
+	return nil
+}
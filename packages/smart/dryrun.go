@@ -0,0 +1,145 @@
+// Apla Software includes an integrated development
+// environment with a multi-level system for the management
+// of access rights to data, interfaces, and Smart contracts. The
+// technical characteristics of the Apla Software are indicated in
+// Apla Technical Paper.
+
+// Apla Users are granted a permission to deal in the Apla
+// Software without restrictions, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of Apla Software, and to permit persons
+// to whom Apla Software is furnished to do so, subject to the
+// following conditions:
+// * the copyright notice of GenesisKernel and EGAAS S.A.
+// and this permission notice shall be included in all copies or
+// substantial portions of the software;
+// * a result of the dealing in Apla Software cannot be
+// implemented outside of the Apla Platform environment.
+
+// THE APLA SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY
+// OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED
+// TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE, ERROR FREE AND NONINFRINGEMENT. IN
+// NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR
+// THE USE OR OTHER DEALINGS IN THE APLA SOFTWARE.
+
+package smart
+
+import (
+	"fmt"
+	"math/rand"
+
+	"github.com/AplaProject/go-apla/packages/consts"
+	"github.com/AplaProject/go-apla/packages/converter"
+	"github.com/AplaProject/go-apla/packages/model"
+	"github.com/AplaProject/go-apla/packages/script"
+	"github.com/AplaProject/go-apla/packages/utils"
+	"github.com/AplaProject/go-apla/packages/utils/tx"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// NextBlockData returns the data of the block which follows the last block. It is used
+// when contracts are executed outside of blocks.
+func NextBlockData(blockTime int64) (*utils.BlockData, error) {
+	infoBlock := &model.InfoBlock{}
+	if _, err := infoBlock.Get(); err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting info block")
+		return nil, err
+	}
+	return &utils.BlockData{
+		BlockID:      infoBlock.BlockID + 1,
+		Time:         blockTime,
+		EcosystemID:  infoBlock.EcosystemID,
+		KeyID:        infoBlock.KeyID,
+		NodePosition: converter.StrToInt64(infoBlock.NodePosition),
+	}, nil
+}
+
+// checkDryRun returns the error if sc is executed in dry run. It is called by the functions
+// which change the compiled contracts or the system parameters shared with block playback.
+func (sc *SmartContract) checkDryRun(name string) error {
+	if sc.DryRun {
+		return logErrorfShort(eDryRun, name, consts.InvalidObject)
+	}
+	return nil
+}
+
+// DryRun runs the conditions and action of the contract against the current state.
+// The signature and the payment aren't checked, all changes are rolled back at the end.
+// The functions which change the global state of the node return an error.
+// If tracer isn't nil then the execution is traced.
+func DryRun(contract *Contract, header tx.Header, params map[string]interface{},
+	tracer *script.Tracer) (result string, fuel int64, err error) {
+
+	logger := log.WithFields(log.Fields{"contract": contract.Name, "key_id": header.KeyID})
+
+	var txData map[string]interface{}
+	if info := contract.Info().Tx; info != nil {
+		if txData, err = FillTxData(*info, params); err != nil {
+			return
+		}
+	}
+
+	blockData, err := NextBlockData(header.Time)
+	if err != nil {
+		return
+	}
+
+	dbTx, err := model.StartTransaction()
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("starting transaction")
+		return
+	}
+	defer dbTx.Rollback()
+
+	return RunContract(&SmartContract{
+		Rollback: true,
+		DryRun:   true,
+		VM:       GetVM(),
+		TxSmart: tx.SmartContract{
+			Header:         header,
+			TokenEcosystem: consts.TokenEcosystem,
+		},
+		TxData:        txData,
+		TxContract:    contract,
+		BlockData:     blockData,
+		DbTransaction: dbTx,
+		Rand:          rand.New(rand.NewSource(header.Time)),
+		Tracer:        tracer,
+	})
+}
+
+// RunContract runs the conditions and action of sc.TxContract with the parameters from sc.TxData.
+// It returns the result of the contract and the spent fuel.
+func RunContract(sc *SmartContract) (result string, fuel int64, err error) {
+	contract := sc.TxContract
+	contract.Extend = sc.getExtend()
+	extend := *contract.Extend
+	if err = sc.AppendStack(contract.Name); err != nil {
+		return
+	}
+	_, name := converter.ParseName(contract.Name)
+	extend[`original_contract`] = name
+	extend[`this_contract`] = name
+
+	before := extend[`txcost`].(int64)
+	for i, method := range []string{`conditions`, `action`} {
+		cfunc := contract.GetFunc(method)
+		if cfunc == nil {
+			continue
+		}
+		contract.Called = 1 << uint32(i)
+		if _, err = VMRun(sc.VM, cfunc, nil, contract.Extend); err != nil {
+			break
+		}
+	}
+	fuel = before - extend[`txcost`].(int64)
+	if err == nil && extend[`result`] != nil {
+		result = fmt.Sprint(extend[`result`])
+	}
+	return
+}
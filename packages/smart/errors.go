@@ -62,6 +62,12 @@ const (
 	eTableNotEmpty       = `Table %s is not empty`
 	eColumnNotDeleted    = `Column %s cannot be deleted`
 	eRollbackContract    = `Wrong rollback of the latest contract %d != %d`
+	eUnknownFuelItem     = `Unknown item %s of the fuel schedule`
+	eEventName           = `Incorrect event name %s`
+	eEventSize           = `Event data is more than %d bytes`
+	eEventCount          = `The number of events is more than %d`
+	eDryRun              = `%s can't be executed in dry run`
+	eKeyType             = `Key type %s isn't activated`
 )
 
 var (
@@ -108,6 +114,9 @@ var (
 	errNotValidUTF        = errors.New(`Result is not valid utf-8 string`)
 	errFloat              = errors.New(`incorrect float value`)
 	errFloatResult        = errors.New(`incorrect float result`)
+	errAccountExists      = errors.New(`Account already exists`)
+	errMultisigSigns      = errors.New(`Not enough signatures of multi-signature account`)
+	errSavepoint          = errors.New(`Wrong savepoint`)
 
 	errMaxPrice = fmt.Errorf(`Price value is more than %d`, MaxPrice)
 )
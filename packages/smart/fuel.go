@@ -0,0 +1,89 @@
+// Apla Software includes an integrated development
+// environment with a multi-level system for the management
+// of access rights to data, interfaces, and Smart contracts. The
+// technical characteristics of the Apla Software are indicated in
+// Apla Technical Paper.
+
+// Apla Users are granted a permission to deal in the Apla
+// Software without restrictions, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of Apla Software, and to permit persons
+// to whom Apla Software is furnished to do so, subject to the
+// following conditions:
+// * the copyright notice of GenesisKernel and EGAAS S.A.
+// and this permission notice shall be included in all copies or
+// substantial portions of the software;
+// * a result of the dealing in Apla Software cannot be
+// implemented outside of the Apla Platform environment.
+
+// THE APLA SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY
+// OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED
+// TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE, ERROR FREE AND NONINFRINGEMENT. IN
+// NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR
+// THE USE OR OTHER DEALINGS IN THE APLA SOFTWARE.
+
+package smart
+
+import (
+	"fmt"
+
+	"github.com/AplaProject/go-apla/packages/conf/syspar"
+	"github.com/AplaProject/go-apla/packages/script"
+)
+
+// FuelCosts contains the fuel costs of VM operations and embedded functions
+type FuelCosts struct {
+	Ops   map[string]int64 `json:"ops"`
+	Funcs map[string]int64 `json:"funcs"`
+}
+
+// checkFuelSchedule validates the value of fuel_schedule system parameter
+func checkFuelSchedule(value string) error {
+	schedule, err := syspar.ParseFuelSchedule(value)
+	if err != nil {
+		return err
+	}
+	ops := script.DefaultOpCosts()
+	for name := range schedule.Ops {
+		if _, ok := ops[name]; !ok {
+			return fmt.Errorf(eUnknownFuelItem, name)
+		}
+	}
+	for name := range schedule.Funcs {
+		if obj, ok := smartVM.Objects[name]; !ok || obj.Type != script.ObjExtFunc {
+			return fmt.Errorf(eUnknownFuelItem, name)
+		}
+	}
+	return nil
+}
+
+// GetFuelSchedule returns the fuel costs which are used by smartVM in the current block
+func GetFuelSchedule() *FuelCosts {
+	ops := script.GetOpCosts()
+	ret := &FuelCosts{
+		Ops: map[string]int64{
+			script.OpCall:      ops.Call,
+			script.OpContract:  ops.Contract,
+			script.OpExtend:    ops.Extend,
+			script.OpIteration: ops.Iteration,
+		},
+		Funcs: make(map[string]int64),
+	}
+	for name, obj := range smartVM.Objects {
+		if obj.Type != script.ObjExtFunc {
+			continue
+		}
+		var cost int64
+		if smartVM.ExtCost != nil {
+			if cost = smartVM.ExtCost(name); cost == -1 {
+				cost = ops.Call
+			}
+		}
+		ret.Funcs[name] = cost
+	}
+	return ret
+}
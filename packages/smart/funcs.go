@@ -72,6 +72,9 @@ const (
 	historyLimit              = 250
 	dateTimeFormat            = "2006-01-02 15:04:05"
 	contractTxType            = 128
+	maxEventName              = 255
+	maxEventData              = 64 * 1024
+	maxEvents                 = 100 // the maximum number of events of the transaction
 )
 
 var (
@@ -124,10 +127,18 @@ type NotifyInfo struct {
 	List        []int64
 }
 
+type savepointInfo struct {
+	flush         int
+	notifications int
+	events        int64
+	stack         int
+}
+
 // SmartContract is storing smart contract data
 type SmartContract struct {
 	OBS           bool
 	Rollback      bool
+	DryRun        bool // the signature isn't checked and the global state of the node can't be changed
 	FullAccess    bool
 	SysUpdate     bool
 	VM            *script.VM
@@ -137,16 +148,23 @@ type SmartContract struct {
 	TxFuel        int64           // The fuel of executing contract
 	TxCost        int64           // Maximum cost of executing contract
 	TxUsedCost    decimal.Decimal // Used cost of CPU resources
+	TxPayment     decimal.Decimal // The amount of tokens which has been paid for the execution
 	BlockData     *utils.BlockData
 	Loop          map[string]bool
 	TxHash        []byte
 	TxSignature   []byte
 	TxSize        int64
+	TxIndex       int64 // the position of the transaction in the block
 	PublicKeys    [][]byte
+	KeyType       crypto.KeyType // the type of the key which has signed the transaction
 	DbTransaction *model.DbTransaction
 	Rand          *rand.Rand
 	FlushRollback []FlushInfo
 	Notifications []NotifyInfo
+	Tracer        *script.Tracer
+	FuelStat      *script.FuelStat
+	savepoints    []savepointInfo
+	events        int64 // the number of the emitted events
 }
 
 var (
@@ -181,6 +199,69 @@ func (sc *SmartContract) PopStack(fn string) {
 	}
 }
 
+// Savepoint saves the current state of the contract before the try block
+func (sc *SmartContract) Savepoint() (int, error) {
+	id := len(sc.savepoints)
+	if sc.DbTransaction != nil {
+		if err := sc.DbTransaction.NamedSavepoint(savepointName(id)); err != nil {
+			return 0, logError(err, consts.DBError, "creating savepoint")
+		}
+	}
+	sc.savepoints = append(sc.savepoints, savepointInfo{
+		flush:         len(sc.FlushRollback),
+		notifications: len(sc.Notifications),
+		events:        sc.events,
+		stack:         len(sc.TxContract.StackCont),
+	})
+	return id, nil
+}
+
+// RollbackSavepoint discards the changes which have been made after the savepoint
+func (sc *SmartContract) RollbackSavepoint(id int) error {
+	if id != len(sc.savepoints)-1 {
+		return errSavepoint
+	}
+	point := sc.savepoints[id]
+	sc.savepoints = sc.savepoints[:id]
+	if sc.DbTransaction != nil {
+		if err := sc.DbTransaction.RollbackNamedSavepoint(savepointName(id)); err != nil {
+			return logError(err, consts.DBError, "rolling back savepoint")
+		}
+		if err := sc.DbTransaction.ReleaseNamedSavepoint(savepointName(id)); err != nil {
+			return logError(err, consts.DBError, "releasing savepoint")
+		}
+	}
+	if len(sc.FlushRollback) > point.flush {
+		RollbackFlush(sc.FlushRollback[point.flush:], log.WithFields(log.Fields{"type": consts.ContractError}))
+		sc.FlushRollback = sc.FlushRollback[:point.flush]
+	}
+	sc.Notifications = sc.Notifications[:point.notifications]
+	sc.events = point.events
+	if len(sc.TxContract.StackCont) > point.stack {
+		sc.TxContract.StackCont = sc.TxContract.StackCont[:point.stack]
+		(*sc.TxContract.Extend)["stack"] = sc.TxContract.StackCont
+	}
+	return nil
+}
+
+// ReleaseSavepoint keeps the changes which have been made after the savepoint
+func (sc *SmartContract) ReleaseSavepoint(id int) error {
+	if id != len(sc.savepoints)-1 {
+		return errSavepoint
+	}
+	sc.savepoints = sc.savepoints[:id]
+	if sc.DbTransaction != nil {
+		if err := sc.DbTransaction.ReleaseNamedSavepoint(savepointName(id)); err != nil {
+			return logError(err, consts.DBError, "releasing savepoint")
+		}
+	}
+	return nil
+}
+
+func savepointName(id int) string {
+	return fmt.Sprintf("try-%d", id)
+}
+
 func (sc *SmartContract) isAllowStack(fn string) bool {
 	// Stack contains only contracts
 	c := VMGetContract(sc.VM, fn, uint32(sc.TxSmart.EcosystemID))
@@ -268,6 +349,9 @@ var (
 )
 
 func getCost(name string) int64 {
+	if val, ok := syspar.GetFuncCost(name); ok {
+		return val
+	}
 	if val, ok := extendCost[name]; ok {
 		return val
 	}
@@ -350,6 +434,7 @@ func EmbedFuncs(vm *script.VM, vt script.VMType) {
 		"StringToBytes":                StringToBytes,
 		"BytesToString":                BytesToString,
 		"SetPubKey":                    SetPubKey,
+		"CreateMultisig":               CreateMultisig,
 		"NewMoney":                     NewMoney,
 		"GetMapKeys":                   GetMapKeys,
 		"SortedKeys":                   SortedKeys,
@@ -409,8 +494,9 @@ func EmbedFuncs(vm *script.VM, vt script.VMType) {
 		vmFuncCallsDB(vm, funcCallsDB)
 	case script.VMTypeSmart:
 		f["GetBlock"] = GetBlock
-		ExtendCost(getCostP)
-		FuncCallsDB(funcCallsDBP)
+		f["EmitEvent"] = EmitEvent
+		vmExtendCost(vm, getCostP)
+		vmFuncCallsDB(vm, funcCallsDBP)
 	}
 
 	vmExtend(vm, &script.ExtendData{Objects: f, AutoPars: map[string]string{
@@ -431,6 +517,7 @@ func EmbedFuncs(vm *script.VM, vt script.VMType) {
 			"UnbindWallet":     {},
 			"EditEcosysName":   {},
 			"SetPubKey":        {},
+			"CreateMultisig":   {},
 			"NewMoney":         {},
 			"UpdateNodesBan":   {},
 			"UpdateCron":       {},
@@ -438,6 +525,7 @@ func EmbedFuncs(vm *script.VM, vt script.VMType) {
 			"DeleteOBS":        {},
 			"DelColumn":        {},
 			"DelTable":         {},
+			"EmitEvent":        {},
 		},
 	})
 }
@@ -524,33 +612,9 @@ func ContractConditions(sc *SmartContract, names ...interface{}) (bool, error) {
 	for _, iname := range names {
 		name := iname.(string)
 		if len(name) > 0 {
-			contract := VMGetContract(sc.VM, name, uint32(sc.TxSmart.EcosystemID))
-			if contract == nil {
-				contract = VMGetContract(sc.VM, name, 0)
-				if contract == nil {
-					return false, logErrorfShort(eUnknownContract, name, consts.NotFound)
-				}
-			}
-			block := contract.GetFunc(`conditions`)
-			if block == nil {
-				return false, logErrorfShort(eContractCondition, name, consts.EmptyObject)
-			}
-			vars := map[string]interface{}{
-				`ecosystem_id`:      int64(sc.TxSmart.EcosystemID),
-				`key_id`:            sc.TxSmart.KeyID,
-				`sc`:                sc,
-				`original_contract`: ``,
-				`this_contract`:     ``,
-				`guest_key`:         consts.GuestKey,
-			}
-			if err := sc.AppendStack(name); err != nil {
-				return false, err
-			}
-			_, err := VMRun(sc.VM, block, []interface{}{}, &vars)
-			if err != nil {
+			if err := contractConditions(sc, name); err != nil {
 				return false, err
 			}
-			sc.PopStack(name)
 		} else {
 			return false, logError(errEmptyContract, consts.EmptyObject, "ContractConditions")
 		}
@@ -558,6 +622,34 @@ func ContractConditions(sc *SmartContract, names ...interface{}) (bool, error) {
 	return true, nil
 }
 
+func contractConditions(sc *SmartContract, name string) error {
+	contract := VMGetContract(sc.VM, name, uint32(sc.TxSmart.EcosystemID))
+	if contract == nil {
+		contract = VMGetContract(sc.VM, name, 0)
+		if contract == nil {
+			return logErrorfShort(eUnknownContract, name, consts.NotFound)
+		}
+	}
+	block := contract.GetFunc(`conditions`)
+	if block == nil {
+		return logErrorfShort(eContractCondition, name, consts.EmptyObject)
+	}
+	vars := map[string]interface{}{
+		`ecosystem_id`:      int64(sc.TxSmart.EcosystemID),
+		`key_id`:            sc.TxSmart.KeyID,
+		`sc`:                sc,
+		`original_contract`: ``,
+		`this_contract`:     ``,
+		`guest_key`:         consts.GuestKey,
+	}
+	if err := sc.AppendStack(name); err != nil {
+		return err
+	}
+	defer sc.PopStack(name)
+	_, err := VMRun(sc.VM, block, []interface{}{}, &vars)
+	return err
+}
+
 func contractName(value string) (name string, err error) {
 	var list []string
 
@@ -1111,6 +1203,9 @@ func FlushContract(sc *SmartContract, iroot interface{}, id int64) error {
 	if err := validateAccess(`FlushContract`, sc, nNewContract, nEditContract, nImport); err != nil {
 		return err
 	}
+	if err := sc.checkDryRun(`FlushContract`); err != nil {
+		return err
+	}
 	root := iroot.(*script.Block)
 	if id != 0 {
 		if len(root.Children) != 1 || root.Children[0].Type != script.ObjContract {
@@ -1147,10 +1242,31 @@ func FlushContract(sc *SmartContract, iroot interface{}, id int64) error {
 		}
 
 	}
+	dropCache(id)
 	VMFlushBlock(sc.VM, root)
 	return nil
 }
 
+// RollbackFlush restores the contracts of VM which have been changed by FlushContract
+func RollbackFlush(flush []FlushInfo, logger *log.Entry) {
+	vm := GetVM()
+	for i := len(flush) - 1; i >= 0; i-- {
+		finfo := flush[i]
+		if finfo.Prev == nil {
+			if finfo.ID != uint32(len(vm.Children)-1) {
+				logger.WithFields(log.Fields{"type": consts.ContractError, "value": finfo.ID,
+					"len": len(vm.Children) - 1}).Error("flush rollback")
+			} else {
+				vm.Children = vm.Children[:len(vm.Children)-1]
+				delete(vm.Objects, finfo.Name)
+			}
+		} else {
+			vm.Children[finfo.ID] = finfo.Prev
+			vm.Objects[finfo.Name] = finfo.Info
+		}
+	}
+}
+
 // IsObject returns true if there is the specified contract
 func IsObject(sc *SmartContract, name string, state int64) bool {
 	return VMObjectExists(sc.VM, name, uint32(state))
@@ -1479,18 +1595,56 @@ func CreateColumn(sc *SmartContract, tableName, name, colType, permissions strin
 	return
 }
 
-// SetPubKey updates the publis key
-func SetPubKey(sc *SmartContract, id int64, pubKey []byte) (qcost int64, err error) {
+// decodePubKey decodes the public key if it is hex. The short hex keys of Ed25519 and secp256k1
+// are decoded only if keyTypes is true.
+func decodePubKey(pubKey []byte, keyTypes bool) ([]byte, error) {
+	if len(pubKey) >= consts.PubkeySizeLength*2 {
+		key, err := crypto.HexToPub(string(pubKey))
+		if err != nil {
+			return nil, logError(err, consts.ConversionError, "decoding public key from hex")
+		}
+		return key, nil
+	}
+	if !keyTypes {
+		return pubKey, nil
+	}
+	if key, err := hex.DecodeString(string(pubKey)); err == nil && crypto.KeyTypeOf(key) != crypto.KeyTypeP256 {
+		// hex of Ed25519 or compressed secp256k1 key
+		return key, nil
+	}
+	return pubKey, nil
+}
+
+// SetPubKey updates the publis key. The type of the key is detected by its format if it isn't specified
+func SetPubKey(sc *SmartContract, id int64, pubKey []byte, keyType ...interface{}) (qcost int64, err error) {
 	if err = validateAccess(`SetPubKey`, sc, nNewUser); err != nil {
 		return
 	}
-	if len(pubKey) >= consts.PubkeySizeLength*2 {
-		pubKey, err = crypto.HexToPub(string(pubKey))
-		if err != nil {
-			return 0, logError(err, consts.ConversionError, "decoding public key from hex")
+	blockID, err := sc.blockID()
+	if err != nil {
+		return
+	}
+	keyTypes := syspar.IsActive(syspar.KeyTypesBlock, blockID)
+	if pubKey, err = decodePubKey(pubKey, keyTypes); err != nil {
+		return
+	}
+	kt := crypto.KeyTypeP256
+	if keyTypes {
+		kt = crypto.KeyTypeOf(pubKey)
+	}
+	if len(keyType) > 0 {
+		var val int64
+		if val, err = converter.ValueToInt(keyType[0]); err != nil {
+			return 0, logError(err, consts.ConversionError, "converting key type to int")
+		}
+		if val != int64(crypto.KeyTypeP256) {
+			kt = crypto.KeyType(val)
 		}
 	}
-	qcost, _, err = sc.update([]string{`pub`}, []interface{}{pubKey}, `1_keys`, `id`, id)
+	if err = sc.checkKeyType(kt); err != nil {
+		return
+	}
+	qcost, _, err = sc.update([]string{`pub`, `key_type`}, []interface{}{pubKey, int64(kt)}, `1_keys`, `id`, id)
 	return
 }
 
@@ -2086,6 +2240,50 @@ func UpdateRolesNotifications(sc *SmartContract, ecosystemID int64, roles ...int
 	sc.Notifications = append(sc.Notifications, NotifyInfo{true, ecosystemID, rolesList})
 }
 
+// EmitEvent records the event of the current contract. The events are stored with the transaction
+// and are removed if the transaction or the block is rolled back. The cost depends on the size of data.
+func EmitEvent(sc *SmartContract, name string, data *types.Map) (qcost int64, err error) {
+	if len(name) == 0 || len(name) > maxEventName || !converter.IsLatin(name) {
+		return 0, fmt.Errorf(eEventName, name)
+	}
+	if sc.events >= maxEvents {
+		return 0, fmt.Errorf(eEventCount, maxEvents)
+	}
+	if data == nil {
+		data = types.NewMap()
+	}
+	out, err := JSONEncode(data)
+	if err != nil {
+		return 0, err
+	}
+	if len(out) > maxEventData {
+		return 0, fmt.Errorf(eEventSize, maxEventData)
+	}
+	qcost = syspar.GetEventFuel() * int64(1+(len(name)+len(out))/1024)
+	event := &model.Event{
+		TxHash:    sc.TxHash,
+		TxIdx:     sc.TxIndex,
+		Idx:       sc.events,
+		Ecosystem: sc.TxSmart.EcosystemID,
+		Contract:  sc.TxContract.Name,
+		Name:      name,
+		Data:      out,
+		Time:      sc.TxSmart.Time,
+	}
+	if len(sc.TxContract.StackCont) > 0 {
+		event.Contract = fmt.Sprint(sc.TxContract.StackCont[len(sc.TxContract.StackCont)-1])
+	}
+	if sc.BlockData != nil {
+		event.BlockID = sc.BlockData.BlockID
+		event.Time = sc.BlockData.Time
+	}
+	if err = event.Create(sc.DbTransaction); err != nil {
+		return 0, logErrorDB(err, "inserting event")
+	}
+	sc.events++
+	return qcost, nil
+}
+
 func TransactionData(blockId int64, hash []byte) (data *TxInfo, err error) {
 	var (
 		blockOwner      model.Block
@@ -2348,6 +2546,16 @@ func (throw *ThrowError) Error() string {
 	return throw.ErrText
 }
 
+// ErrorType returns the type of the error for catch blocks
+func (throw *ThrowError) ErrorType() string {
+	return throw.Type
+}
+
+// ErrorCode returns the code of the error for catch blocks
+func (throw *ThrowError) ErrorCode() string {
+	return throw.Code
+}
+
 func Throw(code, errText string) error {
 	if len(errText) > script.MaxErrLen {
 		errText = errText[:script.MaxErrLen] + `...`
@@ -0,0 +1,111 @@
+// Apla Software includes an integrated development
+// environment with a multi-level system for the management
+// of access rights to data, interfaces, and Smart contracts. The
+// technical characteristics of the Apla Software are indicated in
+// Apla Technical Paper.
+
+// Apla Users are granted a permission to deal in the Apla
+// Software without restrictions, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of Apla Software, and to permit persons
+// to whom Apla Software is furnished to do so, subject to the
+// following conditions:
+// * the copyright notice of GenesisKernel and EGAAS S.A.
+// and this permission notice shall be included in all copies or
+// substantial portions of the software;
+// * a result of the dealing in Apla Software cannot be
+// implemented outside of the Apla Platform environment.
+
+// THE APLA SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY
+// OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED
+// TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE, ERROR FREE AND NONINFRINGEMENT. IN
+// NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR
+// THE USE OR OTHER DEALINGS IN THE APLA SOFTWARE.
+
+package smart
+
+import (
+	"fmt"
+
+	"github.com/AplaProject/go-apla/packages/consts"
+	"github.com/AplaProject/go-apla/packages/crypto"
+	"github.com/AplaProject/go-apla/packages/model"
+	"github.com/AplaProject/go-apla/packages/multisig"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// CreateMultisig creates the multi-signature account with the threshold and the public keys and returns its id
+func CreateMultisig(sc *SmartContract, threshold int64, publicKeys []interface{}) (int64, error) {
+	if err := validateAccess(`CreateMultisig`, sc, nMultiwalletCreate); err != nil {
+		return 0, err
+	}
+	keys := make([][]byte, len(publicKeys))
+	types := make([]crypto.KeyType, len(publicKeys))
+	for i, item := range publicKeys {
+		key, err := decodePubKey([]byte(fmt.Sprint(item)), true)
+		if err != nil {
+			return 0, err
+		}
+		keys[i], types[i] = key, crypto.KeyTypeOf(key)
+		if err = sc.checkKeyType(types[i]); err != nil {
+			return 0, err
+		}
+	}
+	if err := multisig.CheckKeys(threshold, keys); err != nil {
+		return 0, logErrorValue(err, consts.InvalidObject, "checking multi-signature keys", fmt.Sprint(threshold))
+	}
+
+	id := multisig.AccountID(threshold, keys)
+	wallet := &model.Key{}
+	wallet.SetTablePrefix(sc.TxSmart.EcosystemID)
+	found, err := wallet.Get(id)
+	if err != nil {
+		return 0, logErrorDB(err, "getting multi-signature account")
+	}
+	if found {
+		return 0, logErrorValue(errAccountExists, consts.InvalidObject, "creating multi-signature account", fmt.Sprint(id))
+	}
+	if _, _, err = sc.insert([]string{`id`, `multi`, `ecosystem`},
+		[]interface{}{id, threshold, sc.TxSmart.EcosystemID}, `1_keys`); err != nil {
+		return 0, err
+	}
+	for i, key := range keys {
+		if _, _, err = sc.insert([]string{`account_id`, `key_id`, `pub`, `key_type`, `ecosystem`},
+			[]interface{}{id, crypto.Address(key), key, int64(types[i]), sc.TxSmart.EcosystemID},
+			`1_multisig_signers`); err != nil {
+			return 0, err
+		}
+	}
+	return id, nil
+}
+
+// checkMultisig checks the signatures of the transaction of the multi-signature account
+func (sc *SmartContract) checkMultisig(wallet *model.Key) error {
+	logger := sc.GetLogger()
+	signs, err := multisig.SplitSignatures(sc.TxSignature)
+	if err != nil {
+		return err
+	}
+	signers, err := model.GetMultisigSigners(sc.DbTransaction, sc.TxSmart.EcosystemID, wallet.ID)
+	if err != nil {
+		return logErrorDB(err, "getting multi-signature signers")
+	}
+	signed, err := multisig.Verify(signers, sc.TxHash, signs)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.InvalidObject, "error": err, "key_id": wallet.ID}).Error("checking multi-signature")
+		return errIncorrectSign
+	}
+	if int64(len(signed)) < wallet.Multi {
+		logger.WithFields(log.Fields{"type": consts.InvalidObject, "signatures": len(signed), "threshold": wallet.Multi}).Error("checking multi-signature")
+		return errMultisigSigns
+	}
+	for _, signer := range signed {
+		sc.PublicKeys = append(sc.PublicKeys, signer.PublicKey)
+	}
+	return nil
+}
@@ -32,6 +32,8 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/AplaProject/go-apla/packages/types"
+
 	log "github.com/sirupsen/logrus"
 )
 
@@ -52,13 +54,14 @@ func (tc TestKeyTableChecker) IsKeyTable(tableName string) bool {
 	return tc.Val
 }
 func TestSqlFields(t *testing.T) {
-	qb := smartQueryBuilder{
-		Entry:        log.WithFields(log.Fields{"mod": "test"}),
-		table:        "1_keys",
-		Fields:       []string{"+amount"},
-		FieldValues:  []interface{}{2912910000000000000},
-		WhereFields:  []string{"id"},
-		WhereValues:  []string{"-6752330173818123413"},
+	qb := SQLQueryBuilder{
+		Entry:       log.WithFields(log.Fields{"mod": "test"}),
+		Table:       "1_keys",
+		Fields:      []string{"+amount"},
+		FieldValues: []interface{}{2912910000000000000},
+		Where: types.LoadMap(map[string]interface{}{
+			"id": "-6752330173818123413",
+		}),
 		KeyTableChkr: TestKeyTableChecker{true},
 	}
 
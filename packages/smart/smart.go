@@ -44,6 +44,7 @@ import (
 	"github.com/AplaProject/go-apla/packages/crypto"
 	"github.com/AplaProject/go-apla/packages/model"
 	"github.com/AplaProject/go-apla/packages/script"
+	"github.com/AplaProject/go-apla/packages/signer"
 	"github.com/AplaProject/go-apla/packages/utils"
 
 	"github.com/shopspring/decimal"
@@ -126,6 +127,13 @@ func init() {
 	smartVM = newVM()
 }
 
+// NewVM returns a new virtual machine with the embedded functions of the specified type
+func NewVM(vt script.VMType) *script.VM {
+	vm := newVM()
+	EmbedFuncs(vm, vt)
+	return vm
+}
+
 // GetVM is returning smart vm
 func GetVM() *script.VM {
 	return smartVM
@@ -208,6 +216,10 @@ func VMRun(vm *script.VM, block *script.Block, params []interface{}, extend *map
 		cost = syspar.GetMaxCost()
 	}
 	rt := vm.RunInit(cost)
+	if sc, ok := (*extend)[`sc`].(*SmartContract); ok {
+		rt.SetTracer(sc.Tracer)
+		rt.SetFuelStat(sc.FuelStat)
+	}
 	ret, err = rt.Run(block, params, extend)
 	if err != nil {
 		log.WithFields(log.Fields{"type": consts.VMError, "error": err}).Error("running block in smart vm")
@@ -301,6 +313,11 @@ func CompileBlock(src string, owner *script.OwnerInfo) (*script.Block, error) {
 	return VMCompileBlock(smartVM, src, owner)
 }
 
+// Analyze calls Analyze for smartVM
+func Analyze(src string, owner *script.OwnerInfo) ([]script.Warning, error) {
+	return smartVM.Analyze(src, owner)
+}
+
 // CompileEval calls CompileEval for smartVM
 func CompileEval(src string, prefix uint32) error {
 	return VMCompileEval(smartVM, src, prefix)
@@ -352,6 +369,9 @@ func SetContractWallet(sc *SmartContract, tblid, state int64, wallet int64) erro
 	if err := validateAccess(`SetContractWallet`, sc, nBindWallet, nUnbindWallet); err != nil {
 		return err
 	}
+	if err := sc.checkDryRun(`SetContractWallet`); err != nil {
+		return err
+	}
 	for i, item := range smartVM.Block.Children {
 		if item != nil && item.Type == script.ObjContract {
 			cinfo := item.Info.(*script.ContractInfo)
@@ -404,7 +424,7 @@ func loadContractList(list []model.Contract) error {
 			WalletID: item.WalletID,
 			TokenID:  item.TokenID,
 		}
-		if err = Compile(item.Value, &owner); err != nil {
+		if err = compileCached(smartVM, item.Value, &owner); err != nil {
 			logErrorValue(err, consts.EvalError, "Load Contract", strings.Join(clist, `,`))
 		}
 	}
@@ -424,8 +444,15 @@ func defineVMType() script.VMType {
 	return script.VMTypeSmart
 }
 
-// LoadContracts reads and compiles contracts from smart_contracts tables
+// LoadContracts reads and compiles contracts from smart_contracts tables, the compiled contracts
+// are taken from the cache if their sources have not been changed
 func LoadContracts() error {
+	blockData, err := NextBlockData(0)
+	if err != nil {
+		return err
+	}
+	syspar.LoadLoops(blockData.BlockID)
+
 	contract := &model.Contract{}
 	count, err := contract.Count()
 	if err != nil {
@@ -930,6 +957,7 @@ func (sc *SmartContract) payContract(fuelRate decimal.Decimal, payWallet *model.
 		fromIDString); ierr != nil {
 		return errCommission
 	}
+	sc.TxPayment = apl
 	return nil
 }
 
@@ -951,11 +979,11 @@ func (sc *SmartContract) GetSignedBy(public []byte) (int64, error) {
 				}
 			}
 		} else {
-			_, NodePublicKey, err := utils.GetNodeKeys()
+			nodePublicKey, err := signer.Node().PublicKey()
 			if err != nil {
 				return 0, err
 			}
-			isNode = PubToID(NodePublicKey) == signedBy
+			isNode = crypto.Address(nodePublicKey) == signedBy
 		}
 		if !isNode {
 			return 0, errDelayedContract
@@ -966,6 +994,37 @@ func (sc *SmartContract) GetSignedBy(public []byte) (int64, error) {
 	return signedBy, nil
 }
 
+// blockID returns the id of the block of the transaction. The next block is used
+// if the transaction is checked outside of the block.
+func (sc *SmartContract) blockID() (int64, error) {
+	if sc.BlockData != nil {
+		return sc.BlockData.BlockID, nil
+	}
+	blockData, err := NextBlockData(0)
+	if err != nil {
+		return 0, err
+	}
+	return blockData.BlockID, nil
+}
+
+// checkKeyType returns the error if the key type isn't activated in the block of the transaction
+func (sc *SmartContract) checkKeyType(keyType crypto.KeyType) error {
+	if !keyType.IsValid() {
+		return logErrorValue(crypto.ErrUnknownKeyType, consts.InvalidObject, "checking key type", keyType.String())
+	}
+	if keyType == crypto.KeyTypeP256 {
+		return nil
+	}
+	blockID, err := sc.blockID()
+	if err != nil {
+		return err
+	}
+	if !syspar.IsActive(syspar.KeyTypesBlock, blockID) {
+		return logErrorfShort(eKeyType, keyType.String(), consts.InvalidObject)
+	}
+	return nil
+}
+
 // CallContract calls the contract functions according to the specified flags
 func (sc *SmartContract) CallContract() (string, error) {
 	var (
@@ -1021,32 +1080,51 @@ func (sc *SmartContract) CallContract() (string, error) {
 	if wallet.Deleted == 1 {
 		return retError(errDeletedKey)
 	}
-	if len(wallet.PublicKey) > 0 {
-		public = wallet.PublicKey
-	}
-	if sc.TxSmart.ID == 258 { // UpdFullNodes
-		node := syspar.GetNode(sc.TxSmart.KeyID)
-		if node == nil {
-			logger.WithFields(log.Fields{"user_id": sc.TxSmart.KeyID, "type": consts.NotFound}).Error("unknown node id")
-			return retError(errUnknownNodeID)
+	if wallet.Multi > 0 && sc.TxSmart.ID != 258 {
+		// the transaction isn't signed in dry run
+		if !sc.DryRun {
+			if err = sc.checkMultisig(wallet); err != nil {
+				return retError(err)
+			}
 		}
-		public = node.PublicKey
-	}
-	if len(public) == 0 {
-		logger.WithFields(log.Fields{"type": consts.EmptyObject}).Error("empty public key")
-		return retError(errEmptyPublicKey)
-	}
-	sc.PublicKeys = append(sc.PublicKeys, public)
+	} else {
+		keyType := sc.TxSmart.KeyType
+		if len(wallet.PublicKey) > 0 {
+			public = wallet.PublicKey
+			keyType = crypto.KeyType(wallet.KeyType)
+		}
+		if sc.TxSmart.ID == 258 { // UpdFullNodes
+			node := syspar.GetNode(sc.TxSmart.KeyID)
+			if node == nil {
+				logger.WithFields(log.Fields{"user_id": sc.TxSmart.KeyID, "type": consts.NotFound}).Error("unknown node id")
+				return retError(errUnknownNodeID)
+			}
+			public = node.PublicKey
+			keyType = crypto.KeyTypeP256
+		}
+		if len(public) == 0 {
+			logger.WithFields(log.Fields{"type": consts.EmptyObject}).Error("empty public key")
+			return retError(errEmptyPublicKey)
+		}
+		sc.PublicKeys = append(sc.PublicKeys, public)
 
-	var CheckSignResult bool
-	CheckSignResult, err = utils.CheckSign(sc.PublicKeys, sc.TxHash, sc.TxSignature, false)
-	if err != nil {
-		logger.WithFields(log.Fields{"type": consts.CryptoError, "error": err}).Error("checking tx data sign")
-		return retError(err)
-	}
-	if !CheckSignResult {
-		logger.WithFields(log.Fields{"type": consts.InvalidObject}).Error("incorrect sign")
-		return retError(errIncorrectSign)
+		if err = sc.checkKeyType(keyType); err != nil {
+			return retError(err)
+		}
+		sc.KeyType = keyType
+
+		if !sc.DryRun {
+			var CheckSignResult bool
+			CheckSignResult, err = utils.CheckSignType(keyType, sc.PublicKeys, sc.TxHash, sc.TxSignature, false)
+			if err != nil {
+				logger.WithFields(log.Fields{"type": consts.CryptoError, "error": err}).Error("checking tx data sign")
+				return retError(err)
+			}
+			if !CheckSignResult {
+				logger.WithFields(log.Fields{"type": consts.InvalidObject}).Error("incorrect sign")
+				return retError(errIncorrectSign)
+			}
+		}
 	}
 
 	needPayment := sc.TxSmart.EcosystemID > 0 && !sc.OBS && !syspar.IsPrivateBlockchain()
@@ -60,6 +60,7 @@ var (
 		"DBUpdateSysParam": {},
 		"DBUpdateExt":      {},
 		"DBSelect":         {},
+		"EmitEvent":        {},
 	}
 
 	extendCostSysParams = map[string]string{
@@ -113,6 +114,7 @@ const (
 	nEditLangJoint     = "EditLangJoint"
 	nEditTable         = "EditTable"
 	nImport            = "Import"
+	nMultiwalletCreate = "MultiwalletCreate"
 	nNewColumn         = "NewColumn"
 	nNewContract       = "NewContract"
 	nNewEcosystem      = "NewEcosystem"
@@ -138,6 +140,9 @@ type TxSignJSON struct {
 }
 
 func getCostP(name string) int64 {
+	if val, ok := syspar.GetFuncCost(name); ok {
+		return val
+	}
 	if key, ok := extendCostSysParams[name]; ok && syspar.HasSys(key) {
 		return syspar.SysInt64(key)
 	}
@@ -146,6 +151,9 @@ func getCostP(name string) int64 {
 
 // UpdateSysParam updates the system parameter
 func UpdateSysParam(sc *SmartContract, name, value, conditions string) (int64, error) {
+	if err := sc.checkDryRun(`UpdateSysParam`); err != nil {
+		return 0, err
+	}
 	var (
 		fields []string
 		values []interface{}
@@ -180,7 +188,7 @@ func UpdateSysParam(sc *SmartContract, name, value, conditions string) (int64, e
 			ok = ival > 0 && ival < 86400
 		case syspar.RbBlocks1, syspar.NumberNodes:
 			ok = ival > 0 && ival < 1000
-		case syspar.CommissionSize:
+		case syspar.CommissionSize, syspar.EventFuel:
 			ok = ival >= 0
 		case syspar.MaxBlockSize, syspar.MaxTxSize, syspar.MaxTxCount, syspar.MaxColumns,
 			syspar.MaxIndexes, syspar.MaxBlockUserTx, syspar.MaxTxFuel, syspar.MaxBlockFuel, syspar.MaxForsignSize:
@@ -200,6 +208,11 @@ func UpdateSysParam(sc *SmartContract, name, value, conditions string) (int64, e
 				}
 			}
 			checked = true
+		case syspar.FuelSchedule:
+			if err := checkFuelSchedule(value); err != nil {
+				return 0, logErrorValue(err, consts.InvalidObject, err.Error(), value)
+			}
+			checked = true
 		case syspar.FullNodes:
 			fnodes := []syspar.FullNode{}
 			if err := json.Unmarshal([]byte(value), &fnodes); err != nil {
@@ -207,6 +220,15 @@ func UpdateSysParam(sc *SmartContract, name, value, conditions string) (int64, e
 			}
 			checked = len(fnodes) > 0
 		default:
+			if syspar.IsActivationParam(name) {
+				// the feature can be activated only in the future blocks and can't be disabled after activation
+				blockID, err := sc.blockID()
+				if err != nil {
+					return 0, err
+				}
+				ok = ival > blockID || (ival == 0 && !syspar.IsActive(name, blockID))
+				break
+			}
 			if strings.HasPrefix(name, `extend_cost_`) || strings.HasSuffix(name, `_price`) {
 				ok = ival >= 0
 				break
@@ -425,6 +447,9 @@ func CreateEcosystem(sc *SmartContract, wallet int64, name string) (int64, error
 	if err := validateAccess(`CreateEcosystem`, sc, nNewEcosystem); err != nil {
 		return 0, err
 	}
+	if err := sc.checkDryRun(`CreateEcosystem`); err != nil {
+		return 0, err
+	}
 
 	var sp model.StateParameter
 	sp.SetTablePrefix(`1`)
@@ -607,7 +632,7 @@ func CheckSignature(i *map[string]interface{}, name string) error {
 		forsign += fmt.Sprintf(`,%v`, val)
 	}
 
-	CheckSignResult, err := utils.CheckSign(sc.PublicKeys, []byte(forsign), hexsign, true)
+	CheckSignResult, err := utils.CheckSignType(sc.KeyType, sc.PublicKeys, []byte(forsign), hexsign, true)
 	if err != nil {
 		return err
 	}
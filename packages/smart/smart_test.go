@@ -29,11 +29,15 @@
 package smart
 
 import (
+	"io/ioutil"
+	"os"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/AplaProject/go-apla/packages/conf"
 	"github.com/AplaProject/go-apla/packages/script"
+	"github.com/AplaProject/go-apla/packages/utils/tx"
 )
 
 type TestSmart struct {
@@ -49,7 +53,7 @@ func TestNewContract(t *testing.T) {
 				MyVal  string
 			}
 			func conditions {
-				Println( "Front", Random(10, 5000))
+				Println( "Front", Sprintf("%d", 10))
 				//$tmp = "Test string"
 //				Println("NewCitizen Front", $tmp, $key_id, $ecosystem_id, $PublicKey )
 			}
@@ -81,6 +85,7 @@ func TestNewContract(t *testing.T) {
 }
 
 func TestCheckAppend(t *testing.T) {
+	InitVM()
 	appendTestContract := `contract AppendTest {
 		action {
 			var list array
@@ -105,3 +110,71 @@ func TestCheckAppend(t *testing.T) {
 	_, err := Run(cfunc, nil, &map[string]interface{}{})
 	require.NoError(t, err)
 }
+
+func TestContractCache(t *testing.T) {
+	dir, err := ioutil.TempDir(``, `contracts-cache`)
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	conf.Config.ContractCacheDir = dir
+	defer func() { conf.Config.ContractCacheDir = `` }()
+
+	src := `contract CacheTest {
+		action {
+			$result = Sprintf("%d", 10 * 2)
+		}
+	}`
+	owner := script.OwnerInfo{StateID: 1, TableID: 100}
+
+	vm := newVM()
+	require.NoError(t, compileCached(vm, src, &owner))
+	root := loadCache(vm, src, &owner)
+	require.NotNil(t, root)
+	require.Nil(t, loadCache(vm, src+` `, &owner))
+
+	vm = newVM()
+	require.NoError(t, compileCached(vm, src, &owner))
+	obj := vm.Objects[`@1CacheTest`]
+	require.NotNil(t, obj)
+	info := obj.Value.(*script.Block).Info.(*script.ContractInfo)
+	require.Equal(t, int64(100), info.Owner.TableID)
+	extend := map[string]interface{}{}
+	_, err = VMRun(vm, VMGetContract(vm, `CacheTest`, 1).GetFunc(`action`), nil, &extend)
+	require.NoError(t, err)
+	require.Equal(t, `20`, extend[`result`])
+
+	root, err = VMCompileBlock(GetVM(), src, &owner)
+	require.NoError(t, err)
+	require.NoError(t, SysFlushContract(root, owner.TableID, false))
+	require.Nil(t, loadCache(vm, src, &owner))
+}
+
+func TestCheckDryRun(t *testing.T) {
+	sc := &SmartContract{DryRun: true}
+	_, err := UpdateSysParam(sc, "max_tx_size", "1", "")
+	require.EqualError(t, err, "UpdateSysParam can't be executed in dry run")
+	require.NoError(t, (&SmartContract{}).checkDryRun("UpdateSysParam"))
+}
+
+func TestRollbackSavepointStack(t *testing.T) {
+	vm := newVM()
+	owner := script.OwnerInfo{StateID: 1}
+	root, err := VMCompileBlock(vm, `contract StackTest {
+		action {}
+	}`, &owner)
+	require.NoError(t, err)
+	VMFlushBlock(vm, root)
+
+	sc := &SmartContract{
+		VM:         vm,
+		TxSmart:    tx.SmartContract{Header: tx.Header{EcosystemID: 1}},
+		TxContract: &Contract{Extend: &map[string]interface{}{}},
+	}
+	id, err := sc.Savepoint()
+	require.NoError(t, err)
+	require.NoError(t, sc.AppendStack(`StackTest`))
+	require.Len(t, sc.TxContract.StackCont, 1)
+
+	require.NoError(t, sc.RollbackSavepoint(id))
+	require.Empty(t, sc.TxContract.StackCont)
+	require.Empty(t, (*sc.TxContract.Extend)[`stack`])
+}
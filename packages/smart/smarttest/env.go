@@ -0,0 +1,408 @@
+// Apla Software includes an integrated development
+// environment with a multi-level system for the management
+// of access rights to data, interfaces, and Smart contracts. The
+// technical characteristics of the Apla Software are indicated in
+// Apla Technical Paper.
+
+// Apla Users are granted a permission to deal in the Apla
+// Software without restrictions, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of Apla Software, and to permit persons
+// to whom Apla Software is furnished to do so, subject to the
+// following conditions:
+// * the copyright notice of GenesisKernel and EGAAS S.A.
+// and this permission notice shall be included in all copies or
+// substantial portions of the software;
+// * a result of the dealing in Apla Software cannot be
+// implemented outside of the Apla Platform environment.
+
+// THE APLA SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY
+// OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED
+// TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE, ERROR FREE AND NONINFRINGEMENT. IN
+// NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR
+// THE USE OR OTHER DEALINGS IN THE APLA SOFTWARE.
+
+// Package smarttest runs contracts against an in-memory ecosystem state. It allows testing
+// contracts without a running node and PostgreSQL. The database functions DBInsert, DBUpdate,
+// DBUpdateExt, DBSelect (and so DBFind), EcosysParam, AppParam, SysParamString and SysParamInt
+// work with the tables of Store. Permissions of tables and columns are not checked.
+package smarttest
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AplaProject/go-apla/packages/consts"
+	"github.com/AplaProject/go-apla/packages/converter"
+	"github.com/AplaProject/go-apla/packages/script"
+	"github.com/AplaProject/go-apla/packages/smart"
+	"github.com/AplaProject/go-apla/packages/types"
+	"github.com/AplaProject/go-apla/packages/utils"
+	"github.com/AplaProject/go-apla/packages/utils/tx"
+)
+
+const sysParamsTable = `system_parameters`
+
+var (
+	errUpdNotExistRecord = errors.New(`Update for not existing record`)
+	errWhereUpdate       = errors.New(`There is not Where in Update request`)
+	errSysParams         = errors.New(`system parameters access denied`)
+)
+
+// Env is the fake ecosystem where contracts are executed
+type Env struct {
+	VM          *script.VM
+	Store       *Store
+	EcosystemID int64
+	BlockID     int64
+	Time        int64
+	tableID     int64
+}
+
+// Result is the result of the contract execution
+type Result struct {
+	Result string
+	// Fuel is the fuel spent by the virtual machine. The cost of database queries isn't included.
+	Fuel    int64
+	Changes []Change
+}
+
+// New creates the environment for the specified ecosystem with the empty store
+func New(ecosystemID int64) (*Env, error) {
+	env := &Env{
+		VM:          smart.NewVM(script.VMTypeSmart),
+		Store:       NewStore(),
+		EcosystemID: ecosystemID,
+		BlockID:     1,
+		Time:        time.Now().Unix(),
+	}
+	env.VM.Extend(&script.ExtendData{Objects: map[string]interface{}{
+		"DBInsert":       env.dbInsert,
+		"DBSelect":       env.dbSelect,
+		"DBUpdate":       env.dbUpdate,
+		"DBUpdateExt":    env.dbUpdateExt,
+		"EcosysParam":    env.ecosysParam,
+		"AppParam":       env.appParam,
+		"SysParamString": env.sysParamString,
+		"SysParamInt":    env.sysParamInt,
+	}, AutoPars: map[string]string{
+		`*smart.SmartContract`: `sc`,
+	}, WriteFuncs: map[string]struct{}{
+		"DBInsert":    {},
+		"DBUpdate":    {},
+		"DBUpdateExt": {},
+	}})
+	if err := smart.LoadSysFuncs(env.VM, 1); err != nil {
+		return nil, err
+	}
+	env.VM.FlushExtern()
+	return env, nil
+}
+
+// TableName returns the name of the table in the store like contracts of the ecosystem see it
+func (env *Env) TableName(name string) string {
+	return converter.ParseTable(name, env.EcosystemID)
+}
+
+// Insert preloads the row into the table of the ecosystem and returns its id
+func (env *Env) Insert(table string, row Row) int64 {
+	return env.Store.Insert(env.TableName(table), row)
+}
+
+// Row returns the row of the table of the ecosystem
+func (env *Env) Row(table string, id int64) Row {
+	return env.Store.Get(env.TableName(table), id)
+}
+
+// Rows returns all rows of the table of the ecosystem
+func (env *Env) Rows(table string) []Row {
+	return env.Store.Rows(env.TableName(table))
+}
+
+// AddKey adds the key with the public key and the amount of tokens
+func (env *Env) AddKey(keyID int64, pub []byte, amount string) {
+	env.Insert(`keys`, Row{`id`: strconv.FormatInt(keyID, 10), `pub`: string(pub), `amount`: amount})
+}
+
+// SetParam sets the value of the ecosystem parameter
+func (env *Env) SetParam(name, value string) {
+	env.setParam(env.TableName(`parameters`), Row{`name`: name,
+		`ecosystem`: strconv.FormatInt(env.EcosystemID, 10)}, value)
+}
+
+// SetAppParam sets the value of the application parameter
+func (env *Env) SetAppParam(appID int64, name, value string) {
+	env.setParam(env.TableName(`app_params`), Row{`name`: name, `app_id`: strconv.FormatInt(appID, 10),
+		`ecosystem`: strconv.FormatInt(env.EcosystemID, 10)}, value)
+}
+
+// SetSysParam sets the value of the system parameter
+func (env *Env) SetSysParam(name, value string) {
+	env.setParam(sysParamsTable, Row{`name`: name}, value)
+}
+
+func (env *Env) setParam(table string, key Row, value string) {
+	if row := env.param(table, key); row != nil {
+		row[`value`] = value
+		return
+	}
+	key[`value`] = value
+	env.Store.Insert(table, key)
+}
+
+func (env *Env) param(table string, key Row) Row {
+	where := types.NewMap()
+	for name, val := range key {
+		where.Set(name, val)
+	}
+	rows, _ := env.Store.find(table, where, nil)
+	if len(rows) == 0 {
+		return nil
+	}
+	return rows[0]
+}
+
+// AddContract compiles the source of contracts and functions and adds them into the ecosystem.
+// Contracts which are called by other contracts must be added first.
+func (env *Env) AddContract(source string) error {
+	env.tableID++
+	root, err := smart.VMCompileBlock(env.VM, source, &script.OwnerInfo{StateID: uint32(env.EcosystemID),
+		Active: true, TableID: env.tableID})
+	if err != nil {
+		return err
+	}
+	smart.VMFlushBlock(env.VM, root)
+	return nil
+}
+
+// Run executes the contract with the parameters on behalf of the signer. If the contract
+// fails then all changes of the store are rolled back.
+func (env *Env) Run(name string, params map[string]interface{}, signer int64) (*Result, error) {
+	contract := smart.VMGetContract(env.VM, name, uint32(env.EcosystemID))
+	if contract == nil {
+		return nil, fmt.Errorf(`unknown contract %s`, name)
+	}
+	var (
+		txData map[string]interface{}
+		err    error
+	)
+	if info := contract.Info().Tx; info != nil {
+		if txData, err = smart.FillTxData(*info, params); err != nil {
+			return nil, err
+		}
+	}
+	sc := &smart.SmartContract{
+		VM: env.VM,
+		TxSmart: tx.SmartContract{
+			Header: tx.Header{
+				ID:          int(contract.Info().Owner.TableID + consts.ShiftContractID),
+				Time:        env.Time,
+				EcosystemID: env.EcosystemID,
+				KeyID:       signer,
+			},
+			TokenEcosystem: consts.TokenEcosystem,
+		},
+		TxData:     txData,
+		TxContract: contract,
+		BlockData: &utils.BlockData{
+			BlockID:     env.BlockID,
+			Time:        env.Time,
+			EcosystemID: env.EcosystemID,
+		},
+		Rand: rand.New(rand.NewSource(env.Time)),
+	}
+
+	result := &Result{}
+	env.Store.begin()
+	result.Result, result.Fuel, err = smart.RunContract(sc)
+	if err != nil {
+		env.Store.rollback()
+		return result, err
+	}
+	result.Changes = env.Store.commit()
+	return result, nil
+}
+
+func (env *Env) dbInsert(sc *smart.SmartContract, tblname string, values *types.Map) (int64, int64, error) {
+	if tblname == sysParamsTable {
+		return 0, 0, errSysParams
+	}
+	if values.IsEmpty() {
+		return 0, 0, fmt.Errorf(`values are undefined`)
+	}
+	row := make(Row)
+	for _, key := range values.Keys() {
+		v, _ := values.Get(key)
+		row[converter.Sanitize(key, ` ->+`)] = toString(v)
+	}
+	return 0, env.Store.Insert(smart.GetTableName(sc, tblname), row), nil
+}
+
+func (env *Env) dbUpdateExt(sc *smart.SmartContract, tblname string, where *types.Map,
+	values *types.Map) (int64, error) {
+	if tblname == sysParamsTable {
+		return 0, errSysParams
+	}
+	if where == nil || where.IsEmpty() {
+		return 0, errWhereUpdate
+	}
+	if values.IsEmpty() {
+		return 0, fmt.Errorf(`values are undefined`)
+	}
+	tblname = smart.GetTableName(sc, tblname)
+	rows, err := env.Store.find(tblname, where, nil)
+	if err != nil {
+		return 0, err
+	}
+	if len(rows) == 0 {
+		return 0, errUpdNotExistRecord
+	}
+	id, _ := strconv.ParseInt(rows[0][`id`], 10, 64)
+	return 0, env.Store.update(tblname, id, values)
+}
+
+func (env *Env) dbUpdate(sc *smart.SmartContract, tblname string, id int64, values *types.Map) (int64, error) {
+	return env.dbUpdateExt(sc, tblname, types.LoadMap(map[string]interface{}{`id`: id}), values)
+}
+
+func (env *Env) dbSelect(sc *smart.SmartContract, tblname string, inColumns interface{}, id int64,
+	inOrder interface{}, offset, limit int64, inWhere *types.Map) (int64, []interface{}, error) {
+
+	columns, err := smart.GetColumns(inColumns)
+	if err != nil {
+		return 0, nil, err
+	}
+	order, err := getOrder(inOrder)
+	if err != nil {
+		return 0, nil, err
+	}
+	if id != 0 {
+		inWhere = types.LoadMap(map[string]interface{}{`id`: id})
+		limit = 1
+	}
+	if limit == 0 {
+		limit = 25
+	}
+	if limit < 0 || limit > consts.DBFindLimit {
+		limit = consts.DBFindLimit
+	}
+	rows, err := env.Store.find(smart.GetTableName(sc, tblname), inWhere, order)
+	if err != nil {
+		return 0, nil, err
+	}
+	if offset >= int64(len(rows)) {
+		rows = nil
+	} else {
+		rows = rows[offset:]
+	}
+	if int64(len(rows)) > limit {
+		rows = rows[:limit]
+	}
+
+	result := make([]interface{}, 0, len(rows))
+	for _, row := range rows {
+		item := types.NewMap()
+		for _, col := range columns {
+			if col == `*` {
+				keys := make([]string, 0, len(row))
+				for key := range row {
+					keys = append(keys, key)
+				}
+				sort.Strings(keys)
+				for _, key := range keys {
+					item.Set(key, row[key])
+				}
+				continue
+			}
+			item.Set(strings.Replace(col, `->`, `.`, 1), column(row, col))
+		}
+		result = append(result, item)
+	}
+	return 0, result, nil
+}
+
+// getOrder converts the order of DBFind to the list of columns, descending columns have '-' prefix
+func getOrder(inOrder interface{}) ([]string, error) {
+	var orders []string
+	add := func(col string, value interface{}) {
+		col = strings.ToLower(strings.TrimSpace(col))
+		if strings.HasSuffix(col, ` desc`) {
+			col, value = strings.TrimSpace(strings.TrimSuffix(col, ` desc`)), -1
+		} else {
+			col = strings.TrimSpace(strings.TrimSuffix(col, ` asc`))
+		}
+		if len(col) == 0 {
+			return
+		}
+		if fmt.Sprint(value) == `-1` {
+			col = `-` + col
+		}
+		orders = append(orders, col)
+	}
+	var parse func(interface{}) error
+	parse = func(in interface{}) error {
+		switch v := in.(type) {
+		case nil:
+		case string:
+			for _, col := range strings.Split(v, `,`) {
+				add(col, nil)
+			}
+		case *types.Map:
+			for _, key := range v.Keys() {
+				val, _ := v.Get(key)
+				add(key, val)
+			}
+		case []interface{}:
+			for _, item := range v {
+				if err := parse(item); err != nil {
+					return err
+				}
+			}
+		default:
+			return fmt.Errorf(`wrong type of order %T`, in)
+		}
+		return nil
+	}
+	if err := parse(inOrder); err != nil {
+		return nil, err
+	}
+	return append(orders, `id`), nil
+}
+
+func (env *Env) ecosysParam(sc *smart.SmartContract, name string) string {
+	row := env.param(smart.GetTableName(sc, `parameters`), Row{`name`: name,
+		`ecosystem`: strconv.FormatInt(sc.TxSmart.EcosystemID, 10)})
+	if row == nil {
+		return ``
+	}
+	return row[`value`]
+}
+
+func (env *Env) appParam(sc *smart.SmartContract, app int64, name string, ecosystem int64) (string, error) {
+	row := env.param(converter.ParseTable(`app_params`, ecosystem), Row{`name`: name,
+		`app_id`: strconv.FormatInt(app, 10), `ecosystem`: strconv.FormatInt(ecosystem, 10)})
+	if row == nil {
+		return ``, fmt.Errorf(`app parameter %s has not been found`, name)
+	}
+	return row[`value`], nil
+}
+
+func (env *Env) sysParamString(name string) string {
+	if row := env.param(sysParamsTable, Row{`name`: name}); row != nil {
+		return row[`value`]
+	}
+	return ``
+}
+
+func (env *Env) sysParamInt(name string) int64 {
+	return converter.StrToInt64(env.sysParamString(name))
+}
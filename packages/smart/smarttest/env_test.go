@@ -0,0 +1,114 @@
+// Apla Software includes an integrated development
+// environment with a multi-level system for the management
+// of access rights to data, interfaces, and Smart contracts. The
+// technical characteristics of the Apla Software are indicated in
+// Apla Technical Paper.
+
+// Apla Users are granted a permission to deal in the Apla
+// Software without restrictions, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of Apla Software, and to permit persons
+// to whom Apla Software is furnished to do so, subject to the
+// following conditions:
+// * the copyright notice of GenesisKernel and EGAAS S.A.
+// and this permission notice shall be included in all copies or
+// substantial portions of the software;
+// * a result of the dealing in Apla Software cannot be
+// implemented outside of the Apla Platform environment.
+
+// THE APLA SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY
+// OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED
+// TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE, ERROR FREE AND NONINFRINGEMENT. IN
+// NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR
+// THE USE OR OTHER DEALINGS IN THE APLA SOFTWARE.
+
+package smarttest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const transferContract = `contract Transfer {
+	data {
+		Recipient int
+		Amount money
+	}
+	conditions {
+		if $Amount <= 0 {
+			error "wrong amount"
+		}
+		var row map
+		row = DBFind("keys").Columns("amount").WhereId($key_id).Row()
+		if Money(row["amount"]) < $Amount {
+			error "not enough tokens"
+		}
+	}
+	action {
+		DBUpdate("keys", $key_id, {"-amount": $Amount})
+		DBUpdate("keys", $Recipient, {"+amount": $Amount})
+		var comment string
+		comment = EcosysParam("transfer_comment")
+		DBInsert("history", {"sender_id": $key_id, "recipient_id": $Recipient, "amount": $Amount, "comment": comment})
+		$result = Len(DBFind("history").Where({"sender_id": $key_id}))
+	}
+}`
+
+func TestRun(t *testing.T) {
+	env, err := New(1)
+	require.NoError(t, err)
+	env.AddKey(100, []byte{1, 2, 3}, `1000`)
+	env.AddKey(200, nil, `0`)
+	env.SetParam(`transfer_comment`, `test`)
+	require.NoError(t, env.AddContract(transferContract))
+
+	ret, err := env.Run(`Transfer`, map[string]interface{}{`Recipient`: int64(200), `Amount`: `300`}, 100)
+	require.NoError(t, err)
+	assert.Equal(t, `1`, ret.Result)
+	assert.True(t, ret.Fuel > 0)
+	require.Len(t, ret.Changes, 3)
+	assert.Equal(t, Change{Table: `1_keys`, ID: 100,
+		Prev: Row{`id`: `100`, `pub`: "\x01\x02\x03", `amount`: `1000`},
+		Row:  Row{`id`: `100`, `pub`: "\x01\x02\x03", `amount`: `700`}}, ret.Changes[0])
+	assert.Equal(t, `300`, ret.Changes[1].Row[`amount`])
+	assert.True(t, ret.Changes[2].Insert)
+	assert.Equal(t, Row{`id`: `1`, `sender_id`: `100`, `recipient_id`: `200`, `amount`: `300`,
+		`comment`: `test`}, env.Row(`history`, 1))
+
+	ret, err = env.Run(`Transfer`, map[string]interface{}{`Recipient`: int64(200), `Amount`: `800`}, 100)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `not enough tokens`)
+	assert.Empty(t, ret.Changes)
+
+	_, err = env.Run(`Transfer`, map[string]interface{}{`Recipient`: int64(300), `Amount`: `100`}, 100)
+	require.Error(t, err)
+	assert.Equal(t, `700`, env.Row(`keys`, 100)[`amount`])
+	assert.Len(t, env.Rows(`history`), 1)
+
+	_, err = env.Run(`Unknown`, nil, 100)
+	assert.Error(t, err)
+}
+
+func TestStoreFind(t *testing.T) {
+	env, err := New(1)
+	require.NoError(t, err)
+	for _, name := range []string{`b`, `a`, `c`} {
+		env.Insert(`items`, Row{`name`: name, `doc`: `{"size": "` + name + `"}`})
+	}
+	require.NoError(t, env.AddContract(`contract Find {
+	action {
+		var list array
+		list = DBFind("items").Columns("name,doc->size").Where({"$or": [{"name": "a"}, {"name": {"$in": ["b", "c"]}}], "id": {"$gt": 1}}).Order({"name": "-1"})
+		$result = Join(list, ",")
+	}
+}`))
+	ret, err := env.Run(`Find`, nil, 1)
+	require.NoError(t, err)
+	assert.Equal(t, `map[name:c doc.size:c],map[name:a doc.size:a]`, ret.Result)
+}
@@ -0,0 +1,415 @@
+// Apla Software includes an integrated development
+// environment with a multi-level system for the management
+// of access rights to data, interfaces, and Smart contracts. The
+// technical characteristics of the Apla Software are indicated in
+// Apla Technical Paper.
+
+// Apla Users are granted a permission to deal in the Apla
+// Software without restrictions, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of Apla Software, and to permit persons
+// to whom Apla Software is furnished to do so, subject to the
+// following conditions:
+// * the copyright notice of GenesisKernel and EGAAS S.A.
+// and this permission notice shall be included in all copies or
+// substantial portions of the software;
+// * a result of the dealing in Apla Software cannot be
+// implemented outside of the Apla Platform environment.
+
+// THE APLA SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY
+// OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED
+// TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE, ERROR FREE AND NONINFRINGEMENT. IN
+// NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR
+// THE USE OR OTHER DEALINGS IN THE APLA SOFTWARE.
+
+package smarttest
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/AplaProject/go-apla/packages/types"
+
+	"github.com/shopspring/decimal"
+)
+
+// Row is a record of the table. All values are kept as strings like they are returned by DBSelect.
+type Row map[string]string
+
+func (r Row) copy() Row {
+	if r == nil {
+		return nil
+	}
+	ret := make(Row, len(r))
+	for key, val := range r {
+		ret[key] = val
+	}
+	return ret
+}
+
+// Change describes the row which has been inserted or updated by the contract
+type Change struct {
+	Table  string
+	ID     int64
+	Insert bool
+	Prev   Row // the row before the contract execution, nil for inserted rows
+	Row    Row
+}
+
+type table struct {
+	rows   map[int64]Row
+	lastID int64
+}
+
+// Store is an in-memory storage of tables which replaces the database for contracts
+type Store struct {
+	tables  map[string]*table
+	journal []*Change
+	changes map[string]*Change
+}
+
+// NewStore creates an empty store
+func NewStore() *Store {
+	return &Store{tables: make(map[string]*table)}
+}
+
+func (s *Store) table(name string) *table {
+	t, ok := s.tables[name]
+	if !ok {
+		t = &table{rows: make(map[int64]Row)}
+		s.tables[name] = t
+	}
+	return t
+}
+
+func changeKey(name string, id int64) string {
+	return name + `:` + strconv.FormatInt(id, 10)
+}
+
+// touch saves the state of the row before the first modification
+func (s *Store) touch(name string, id int64, insert bool) {
+	if s.changes == nil {
+		return
+	}
+	key := changeKey(name, id)
+	if _, ok := s.changes[key]; ok {
+		return
+	}
+	change := &Change{Table: name, ID: id, Insert: insert}
+	if !insert {
+		change.Prev = s.table(name).rows[id].copy()
+	}
+	s.changes[key] = change
+	s.journal = append(s.journal, change)
+}
+
+func (s *Store) begin() {
+	s.journal = nil
+	s.changes = make(map[string]*Change)
+}
+
+// commit finishes the journal and returns the list of changes
+func (s *Store) commit() []Change {
+	ret := make([]Change, len(s.journal))
+	for i, change := range s.journal {
+		change.Row = s.tables[change.Table].rows[change.ID].copy()
+		ret[i] = *change
+	}
+	s.journal, s.changes = nil, nil
+	return ret
+}
+
+// rollback restores the rows which have been changed since begin
+func (s *Store) rollback() {
+	for i := len(s.journal) - 1; i >= 0; i-- {
+		change := s.journal[i]
+		if change.Insert {
+			delete(s.tables[change.Table].rows, change.ID)
+		} else {
+			s.tables[change.Table].rows[change.ID] = change.Prev
+		}
+	}
+	s.journal, s.changes = nil, nil
+}
+
+// Insert adds the row into the table. If the row doesn't have id then the next id is used.
+// It returns the id of the row.
+func (s *Store) Insert(name string, row Row) int64 {
+	t := s.table(name)
+	row = row.copy()
+	if row == nil {
+		row = make(Row)
+	}
+	id, _ := strconv.ParseInt(row[`id`], 10, 64)
+	if id == 0 {
+		id = t.lastID + 1
+		row[`id`] = strconv.FormatInt(id, 10)
+	}
+	if id > t.lastID {
+		t.lastID = id
+	}
+	s.touch(name, id, true)
+	t.rows[id] = row
+	return id
+}
+
+// Get returns the copy of the row with the specified id or nil if it doesn't exist
+func (s *Store) Get(name string, id int64) Row {
+	if t, ok := s.tables[name]; ok {
+		return t.rows[id].copy()
+	}
+	return nil
+}
+
+// Rows returns the copies of all rows of the table ordered by id
+func (s *Store) Rows(name string) []Row {
+	ret := make([]Row, 0)
+	t, ok := s.tables[name]
+	if !ok {
+		return ret
+	}
+	for _, id := range t.ids() {
+		ret = append(ret, t.rows[id].copy())
+	}
+	return ret
+}
+
+func (t *table) ids() []int64 {
+	ids := make([]int64, 0, len(t.rows))
+	for id := range t.rows {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+// update modifies the row like DBUpdate does. Columns can be prefixed with + or - for
+// the arithmetic update and can have col->key form for json fields.
+func (s *Store) update(name string, id int64, values *types.Map) error {
+	row := s.table(name).rows[id]
+	s.touch(name, id, false)
+	for _, key := range values.Keys() {
+		v, _ := values.Get(key)
+		val := toString(v)
+		switch {
+		case strings.Contains(key, `->`):
+			colfield := strings.SplitN(key, `->`, 2)
+			obj := make(map[string]interface{})
+			if len(row[colfield[0]]) > 0 {
+				if err := json.Unmarshal([]byte(row[colfield[0]]), &obj); err != nil {
+					return err
+				}
+			}
+			obj[colfield[1]] = val
+			out, err := json.Marshal(obj)
+			if err != nil {
+				return err
+			}
+			row[colfield[0]] = string(out)
+		case key[0] == '+' || key[0] == '-':
+			prev, err := decimal.NewFromString(row[key[1:]])
+			if err != nil {
+				prev = decimal.Zero
+			}
+			delta, err := decimal.NewFromString(val)
+			if err != nil {
+				return fmt.Errorf(`wrong value %s of %s`, val, key)
+			}
+			if key[0] == '-' {
+				delta = delta.Neg()
+			}
+			row[key[1:]] = prev.Add(delta).String()
+		default:
+			row[key] = val
+		}
+	}
+	return nil
+}
+
+// find returns the rows which match where, ordered by order
+func (s *Store) find(name string, where *types.Map, order []string) ([]Row, error) {
+	t, ok := s.tables[name]
+	if !ok {
+		return nil, nil
+	}
+	ret := make([]Row, 0)
+	for _, id := range t.ids() {
+		match, err := matchWhere(t.rows[id], where)
+		if err != nil {
+			return nil, err
+		}
+		if match {
+			ret = append(ret, t.rows[id])
+		}
+	}
+	sort.SliceStable(ret, func(i, j int) bool {
+		for _, item := range order {
+			col, desc := item, false
+			if strings.HasPrefix(col, `-`) {
+				col, desc = col[1:], true
+			}
+			if cmp := compare(ret[i][col], ret[j][col]); cmp != 0 {
+				return (cmp < 0) != desc
+			}
+		}
+		return false
+	})
+	return ret, nil
+}
+
+func toString(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return ``
+	case string:
+		return val
+	case []byte:
+		return string(val)
+	case *types.Map, map[string]interface{}, []interface{}:
+		out, _ := json.Marshal(val)
+		return string(out)
+	}
+	return fmt.Sprint(v)
+}
+
+// compare compares values as numbers if both of them are numbers or as strings otherwise
+func compare(left, right string) int {
+	if l, err := decimal.NewFromString(left); err == nil {
+		if r, err := decimal.NewFromString(right); err == nil {
+			return l.Cmp(r)
+		}
+	}
+	return strings.Compare(left, right)
+}
+
+func column(row Row, name string) string {
+	if strings.Contains(name, `->`) {
+		colfield := strings.SplitN(name, `->`, 2)
+		obj := make(map[string]interface{})
+		if json.Unmarshal([]byte(row[colfield[0]]), &obj) != nil {
+			return ``
+		}
+		return toString(obj[colfield[1]])
+	}
+	return row[name]
+}
+
+// matchWhere checks the row against the where map in the format of DBFind
+func matchWhere(row Row, where *types.Map) (bool, error) {
+	if where == nil {
+		return true, nil
+	}
+	for _, key := range where.Keys() {
+		v, _ := where.Get(key)
+		var (
+			match bool
+			err   error
+		)
+		switch key = strings.ToLower(key); key {
+		case `$and`, `$or`:
+			list, ok := v.([]interface{})
+			if !ok {
+				return false, fmt.Errorf(`wrong value of %s`, key)
+			}
+			match = key == `$and`
+			for _, item := range list {
+				cond, ok := item.(*types.Map)
+				if !ok {
+					continue
+				}
+				ret, err := matchWhere(row, cond)
+				if err != nil {
+					return false, err
+				}
+				if key == `$and` {
+					match = match && ret
+				} else {
+					match = match || ret
+				}
+			}
+		default:
+			match, err = matchValue(column(row, key), v)
+		}
+		if err != nil || !match {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func matchValue(value string, cond interface{}) (bool, error) {
+	switch v := cond.(type) {
+	case *types.Map:
+		for _, oper := range v.Keys() {
+			arg, _ := v.Get(oper)
+			match, err := matchOper(value, strings.ToLower(oper), arg)
+			if err != nil || !match {
+				return false, err
+			}
+		}
+		return true, nil
+	case []interface{}:
+		for _, item := range v {
+			match, err := matchValue(value, item)
+			if err != nil || !match {
+				return false, err
+			}
+		}
+		return true, nil
+	}
+	if toString(cond) == `$isnull` {
+		return len(value) == 0, nil
+	}
+	return compare(value, toString(cond)) == 0, nil
+}
+
+func matchOper(value, oper string, arg interface{}) (bool, error) {
+	str := toString(arg)
+	switch oper {
+	case `$eq`:
+		return compare(value, str) == 0, nil
+	case `$neq`:
+		return compare(value, str) != 0, nil
+	case `$gt`:
+		return compare(value, str) > 0, nil
+	case `$gte`:
+		return compare(value, str) >= 0, nil
+	case `$lt`:
+		return compare(value, str) < 0, nil
+	case `$lte`:
+		return compare(value, str) <= 0, nil
+	case `$like`:
+		return strings.Contains(value, str), nil
+	case `$begin`:
+		return strings.HasPrefix(value, str), nil
+	case `$end`:
+		return strings.HasSuffix(value, str), nil
+	case `$ilike`:
+		return strings.Contains(strings.ToLower(value), strings.ToLower(str)), nil
+	case `$ibegin`:
+		return strings.HasPrefix(strings.ToLower(value), strings.ToLower(str)), nil
+	case `$iend`:
+		return strings.HasSuffix(strings.ToLower(value), strings.ToLower(str)), nil
+	case `$in`, `$nin`:
+		list, ok := arg.([]interface{})
+		if !ok {
+			return false, fmt.Errorf(`wrong value of %s`, oper)
+		}
+		found := false
+		for _, item := range list {
+			if compare(value, toString(item)) == 0 {
+				found = true
+				break
+			}
+		}
+		return found == (oper == `$in`), nil
+	}
+	return false, fmt.Errorf(`unknown operator %s`, oper)
+}
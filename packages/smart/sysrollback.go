@@ -131,6 +131,7 @@ func SysFlushContract(iroot interface{}, id int64, active bool) error {
 			root.Children[i].Info.(*script.ContractInfo).Owner.Active = active
 		}
 	}
+	dropCache(id)
 	VMFlushBlock(GetVM(), root)
 	return nil
 }
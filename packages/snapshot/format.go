@@ -0,0 +1,225 @@
+// Apla Software includes an integrated development
+// environment with a multi-level system for the management
+// of access rights to data, interfaces, and Smart contracts. The
+// technical characteristics of the Apla Software are indicated in
+// Apla Technical Paper.
+
+// Apla Users are granted a permission to deal in the Apla
+// Software without restrictions, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of Apla Software, and to permit persons
+// to whom Apla Software is furnished to do so, subject to the
+// following conditions:
+// * the copyright notice of GenesisKernel and EGAAS S.A.
+// and this permission notice shall be included in all copies or
+// substantial portions of the software;
+// * a result of the dealing in Apla Software cannot be
+// implemented outside of the Apla Platform environment.
+
+// THE APLA SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY
+// OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED
+// TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE, ERROR FREE AND NONINFRINGEMENT. IN
+// NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR
+// THE USE OR OTHER DEALINGS IN THE APLA SOFTWARE.
+
+package snapshot
+
+import (
+	"bufio"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"hash"
+	"io"
+)
+
+// Version is the version of snapshot format
+const Version = 3
+
+var (
+	// ErrVersion is returned if the version of snapshot isn't supported
+	ErrVersion = errors.New("Unsupported version of snapshot")
+	// ErrDigest is returned if the digest of snapshot is wrong
+	ErrDigest = errors.New("Wrong digest of snapshot")
+	// ErrFormat is returned if the snapshot is damaged
+	ErrFormat = errors.New("Wrong format of snapshot")
+)
+
+// Header is the first record of snapshot
+type Header struct {
+	Version int    `json:"version"`
+	BlockID int64  `json:"block_id"`
+	Hash    []byte `json:"hash"`
+	Time    int64  `json:"time"`
+}
+
+// Column is the column of table
+type Column struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	NotNull  bool   `json:"not_null,omitempty"`
+	Default  string `json:"default,omitempty"`
+	Sequence string `json:"sequence,omitempty"`
+}
+
+// Index is the index of table
+type Index struct {
+	Name    string   `json:"name"`
+	Columns []string `json:"columns"`
+	Unique  bool     `json:"unique,omitempty"`
+}
+
+// Table describes the table, rows of the table follow it in snapshot
+type Table struct {
+	Name       string   `json:"name"`
+	Columns    []Column `json:"columns"`
+	PrimaryKey []string `json:"primary_key,omitempty"`
+	Indexes    []Index  `json:"indexes,omitempty"`
+}
+
+// Footer is the last record of snapshot
+type Footer struct {
+	Tables int    `json:"tables"`
+	Rows   int64  `json:"rows"`
+	Digest []byte `json:"digest"`
+}
+
+type entry struct {
+	Table  *Table    `json:"table,omitempty"`
+	Row    []*string `json:"row,omitempty"`
+	Footer *Footer   `json:"footer,omitempty"`
+}
+
+// writer writes snapshot as gzipped json records, the footer contains
+// sha256 digest of all previous records
+type writer struct {
+	gz     *gzip.Writer
+	enc    *json.Encoder
+	digest hash.Hash
+	footer Footer
+}
+
+func newWriter(w io.Writer) *writer {
+	gz := gzip.NewWriter(w)
+	digest := sha256.New()
+	return &writer{
+		gz:     gz,
+		enc:    json.NewEncoder(io.MultiWriter(gz, digest)),
+		digest: digest,
+	}
+}
+
+func (w *writer) writeHeader(h *Header) error {
+	h.Version = Version
+	return w.enc.Encode(h)
+}
+
+func (w *writer) writeTable(t *Table) error {
+	w.footer.Tables++
+	return w.enc.Encode(entry{Table: t})
+}
+
+func (w *writer) writeRow(row []*string) error {
+	w.footer.Rows++
+	return w.enc.Encode(entry{Row: row})
+}
+
+func (w *writer) close() error {
+	w.footer.Digest = w.digest.Sum(nil)
+	if err := json.NewEncoder(w.gz).Encode(entry{Footer: &w.footer}); err != nil {
+		return err
+	}
+	return w.gz.Close()
+}
+
+// reader reads snapshot records and checks the digest at the end
+type reader struct {
+	gz     *gzip.Reader
+	buf    *bufio.Reader
+	digest hash.Hash
+	footer *Footer
+	tables int
+	rows   int64
+}
+
+func newReader(r io.Reader) (*reader, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	return &reader{
+		gz:     gz,
+		buf:    bufio.NewReaderSize(gz, 1<<20),
+		digest: sha256.New(),
+	}, nil
+}
+
+func (r *reader) readLine() ([]byte, error) {
+	line, err := r.buf.ReadBytes('\n')
+	if err == io.EOF && len(line) > 0 {
+		return nil, ErrFormat
+	}
+	return line, err
+}
+
+func (r *reader) readHeader() (*Header, error) {
+	line, err := r.readLine()
+	if err != nil {
+		return nil, err
+	}
+	r.digest.Write(line)
+
+	h := &Header{}
+	if err = json.Unmarshal(line, h); err != nil {
+		return nil, err
+	}
+	if h.Version != Version {
+		return nil, ErrVersion
+	}
+	return h, nil
+}
+
+// next returns the next table or row, it returns io.EOF after the valid footer
+func (r *reader) next() (*Table, []*string, error) {
+	if r.footer != nil {
+		return nil, nil, io.EOF
+	}
+	line, err := r.readLine()
+	if err == io.EOF {
+		return nil, nil, ErrFormat
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var e entry
+	if err = json.Unmarshal(line, &e); err != nil {
+		return nil, nil, err
+	}
+	switch {
+	case e.Footer != nil:
+		r.footer = e.Footer
+		if e.Footer.Tables != r.tables || e.Footer.Rows != r.rows ||
+			string(e.Footer.Digest) != string(r.digest.Sum(nil)) {
+			return nil, nil, ErrDigest
+		}
+		return nil, nil, io.EOF
+	case e.Table != nil:
+		r.tables++
+	case e.Row != nil:
+		r.rows++
+	default:
+		return nil, nil, ErrFormat
+	}
+	r.digest.Write(line)
+	return e.Table, e.Row, nil
+}
+
+func (r *reader) close() error {
+	return r.gz.Close()
+}
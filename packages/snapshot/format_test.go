@@ -0,0 +1,114 @@
+// Apla Software includes an integrated development
+// environment with a multi-level system for the management
+// of access rights to data, interfaces, and Smart contracts. The
+// technical characteristics of the Apla Software are indicated in
+// Apla Technical Paper.
+
+// Apla Users are granted a permission to deal in the Apla
+// Software without restrictions, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of Apla Software, and to permit persons
+// to whom Apla Software is furnished to do so, subject to the
+// following conditions:
+// * the copyright notice of GenesisKernel and EGAAS S.A.
+// and this permission notice shall be included in all copies or
+// substantial portions of the software;
+// * a result of the dealing in Apla Software cannot be
+// implemented outside of the Apla Platform environment.
+
+// THE APLA SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY
+// OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED
+// TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE, ERROR FREE AND NONINFRINGEMENT. IN
+// NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR
+// THE USE OR OTHER DEALINGS IN THE APLA SOFTWARE.
+
+package snapshot
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeSnapshot(t *testing.T) []byte {
+	var buf bytes.Buffer
+	w := newWriter(&buf)
+	value := "value"
+	require.NoError(t, w.writeHeader(&Header{BlockID: 10, Hash: []byte{1, 2, 3}, Time: 100}))
+	require.NoError(t, w.writeTable(&Table{
+		Name:       "1_keys",
+		Columns:    []Column{{Name: "id", Type: "bigint", NotNull: true}, {Name: "pub", Type: "bytea", Default: "'\\x'::bytea"}},
+		PrimaryKey: []string{"id"},
+	}))
+	require.NoError(t, w.writeRow([]*string{&value, nil}))
+	require.NoError(t, w.writeRow([]*string{&value, &value}))
+	require.NoError(t, w.close())
+	return buf.Bytes()
+}
+
+func TestFormat(t *testing.T) {
+	r, err := newReader(bytes.NewReader(writeSnapshot(t)))
+	require.NoError(t, err)
+
+	h, err := r.readHeader()
+	require.NoError(t, err)
+	assert.Equal(t, &Header{Version: Version, BlockID: 10, Hash: []byte{1, 2, 3}, Time: 100}, h)
+
+	table, row, err := r.next()
+	require.NoError(t, err)
+	assert.Nil(t, row)
+	assert.Equal(t, "1_keys", table.Name)
+	assert.Equal(t, []string{"id"}, table.PrimaryKey)
+	assert.True(t, table.Columns[0].NotNull)
+	assert.Equal(t, `'\x'::bytea`, table.Columns[1].Default)
+
+	table, row, err = r.next()
+	require.NoError(t, err)
+	assert.Nil(t, table)
+	require.Len(t, row, 2)
+	assert.Equal(t, "value", *row[0])
+	assert.Nil(t, row[1])
+
+	_, _, err = r.next()
+	require.NoError(t, err)
+	_, _, err = r.next()
+	assert.Equal(t, io.EOF, err)
+	assert.NoError(t, r.close())
+}
+
+func TestFormatDamaged(t *testing.T) {
+	data := writeSnapshot(t)
+
+	var buf bytes.Buffer
+	w := newWriter(&buf)
+	require.NoError(t, w.writeHeader(&Header{BlockID: 10}))
+	require.NoError(t, w.writeTable(&Table{Name: "1_keys"}))
+	require.NoError(t, w.gz.Close())
+
+	// truncated snapshot without footer
+	r, err := newReader(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	_, err = r.readHeader()
+	require.NoError(t, err)
+	_, _, err = r.next()
+	require.NoError(t, err)
+	_, _, err = r.next()
+	assert.Equal(t, ErrFormat, err)
+
+	r, err = newReader(bytes.NewReader(data))
+	require.NoError(t, err)
+	r.digest.Write([]byte("x"))
+	_, err = r.readHeader()
+	require.NoError(t, err)
+	for err == nil {
+		_, _, err = r.next()
+	}
+	assert.Equal(t, ErrDigest, err)
+}
@@ -0,0 +1,660 @@
+// Apla Software includes an integrated development
+// environment with a multi-level system for the management
+// of access rights to data, interfaces, and Smart contracts. The
+// technical characteristics of the Apla Software are indicated in
+// Apla Technical Paper.
+
+// Apla Users are granted a permission to deal in the Apla
+// Software without restrictions, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of Apla Software, and to permit persons
+// to whom Apla Software is furnished to do so, subject to the
+// following conditions:
+// * the copyright notice of GenesisKernel and EGAAS S.A.
+// and this permission notice shall be included in all copies or
+// substantial portions of the software;
+// * a result of the dealing in Apla Software cannot be
+// implemented outside of the Apla Platform environment.
+
+// THE APLA SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY
+// OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED
+// TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE, ERROR FREE AND NONINFRINGEMENT. IN
+// NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR
+// THE USE OR OTHER DEALINGS IN THE APLA SOFTWARE.
+
+package snapshot
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/AplaProject/go-apla/packages/conf"
+	"github.com/AplaProject/go-apla/packages/conf/syspar"
+	"github.com/AplaProject/go-apla/packages/consts"
+	"github.com/AplaProject/go-apla/packages/converter"
+	"github.com/AplaProject/go-apla/packages/model"
+	"github.com/AplaProject/go-apla/packages/network/tcpclient"
+
+	"github.com/jinzhu/gorm"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	blockchainTable = "block_chain"
+)
+
+// ErrHashNotConfirmed is returned if peers don't confirm the hash of snapshot block
+var ErrHashNotConfirmed = errors.New("Hash of snapshot block is not confirmed by nodes")
+
+// localTables are the tables of the node state which aren't included into snapshot.
+// The tables marked as true are cleared on restore because their data belongs to the replaced state.
+var localTables = map[string]bool{
+	"confirmations":       true,
+	"install":             false,
+	"migration_history":   false,
+	"my_node_keys":        false,
+	"queue_blocks":        true,
+	"queue_tx":            true,
+	"rollback_tx":         true,
+	"stop_daemons":        false,
+	"transactions":        true,
+	"transactions_status": true,
+}
+
+var (
+	sequenceRegexp = regexp.MustCompile(`^nextval\('(.+)'::regclass\)$`)
+
+	// the types and the default values of columns are put into SQL statements on restore,
+	// so only the values which are used by the tables of the blockchain are allowed
+	typeRegexp = regexp.MustCompile(`^(bigint|integer|smallint|boolean|text|bytea|jsonb|json|uuid|date|real|` +
+		`double precision|timestamp (with|without) time zone|` +
+		`(numeric|character varying|character)(\(\d+(,\d+)?\))?)(\[\])?$`)
+	numberRegexp  = regexp.MustCompile(`^(-?\d+(\.\d+)?|\(-\d+(\.\d+)?\))$`)
+	literalRegexp = regexp.MustCompile(`^'((?:[^']|'')*)'(::(.+))?$`)
+	nullRegexp    = regexp.MustCompile(`^NULL(::(.+))?$`)
+)
+
+func quote(name string) string {
+	return `"` + strings.Replace(name, `"`, `""`, -1) + `"`
+}
+
+func quoteList(names []string) string {
+	list := make([]string, len(names))
+	for i, name := range names {
+		list[i] = quote(name)
+	}
+	return strings.Join(list, ",")
+}
+
+func quoteLiteral(value string) string {
+	return `'` + strings.Replace(value, `'`, `''`, -1) + `'`
+}
+
+func unquote(name string) string {
+	if len(name) > 1 && strings.HasPrefix(name, `"`) && strings.HasSuffix(name, `"`) {
+		return strings.Replace(name[1:len(name)-1], `""`, `"`, -1)
+	}
+	return name
+}
+
+// columnDefault builds the default value of the column from the parsed value of snapshot
+func columnDefault(col Column) (string, error) {
+	if len(col.Sequence) > 0 {
+		return `nextval(` + quoteLiteral(quote(col.Sequence)) + `::regclass)`, nil
+	}
+	value := col.Default
+	if len(value) == 0 || numberRegexp.MatchString(value) {
+		return value, nil
+	}
+	switch strings.ToLower(value) {
+	case `true`, `false`, `now()`, `current_timestamp`:
+		return value, nil
+	}
+	var cast string
+	if m := literalRegexp.FindStringSubmatch(value); m != nil {
+		value, cast = quoteLiteral(strings.Replace(m[1], `''`, `'`, -1)), m[3]
+	} else if m = nullRegexp.FindStringSubmatch(value); m != nil {
+		value, cast = `NULL`, m[2]
+	} else {
+		return ``, ErrFormat
+	}
+	if len(cast) > 0 {
+		if !typeRegexp.MatchString(cast) {
+			return ``, ErrFormat
+		}
+		value += `::` + cast
+	}
+	return value, nil
+}
+
+// checkTable checks the description of the table which has been read from the snapshot
+func checkTable(t *Table) error {
+	if len(t.Columns) == 0 {
+		return ErrFormat
+	}
+	for _, col := range t.Columns {
+		if !typeRegexp.MatchString(col.Type) {
+			return ErrFormat
+		}
+		if _, err := columnDefault(col); err != nil {
+			return err
+		}
+	}
+	for _, index := range t.Indexes {
+		if len(index.Name) == 0 || len(index.Columns) == 0 {
+			return ErrFormat
+		}
+	}
+	return nil
+}
+
+func getInfoBlock(tx *model.DbTransaction, infoBlock *model.InfoBlock) (bool, error) {
+	err := model.GetDB(tx).Last(infoBlock).Error
+	if err == gorm.ErrRecordNotFound {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func getTables(tx *model.DbTransaction) ([]string, error) {
+	var tables []string
+	rows, err := model.GetDB(tx).Raw(`SELECT tablename FROM pg_tables
+		WHERE schemaname = current_schema() ORDER BY tablename`).Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var name string
+		if err = rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		if _, ok := localTables[name]; !ok {
+			tables = append(tables, name)
+		}
+	}
+	return tables, rows.Err()
+}
+
+func getStrings(tx *model.DbTransaction, query string, args ...interface{}) ([]string, error) {
+	var list []string
+	rows, err := model.GetDB(tx).Raw(query, args...).Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var s string
+		if err = rows.Scan(&s); err != nil {
+			return nil, err
+		}
+		list = append(list, s)
+	}
+	return list, rows.Err()
+}
+
+func getTable(tx *model.DbTransaction, name string) (*Table, error) {
+	t := &Table{Name: name}
+	rows, err := model.GetDB(tx).Raw(`SELECT a.attname, format_type(a.atttypid, a.atttypmod), a.attnotnull,
+		COALESCE(pg_get_expr(d.adbin, d.adrelid), '')
+		FROM pg_attribute a LEFT JOIN pg_attrdef d ON d.adrelid = a.attrelid AND d.adnum = a.attnum
+		WHERE a.attrelid = ?::regclass AND a.attnum > 0 AND NOT a.attisdropped
+		ORDER BY a.attnum`, quote(name)).Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var col Column
+		if err = rows.Scan(&col.Name, &col.Type, &col.NotNull, &col.Default); err != nil {
+			return nil, err
+		}
+		if m := sequenceRegexp.FindStringSubmatch(col.Default); m != nil {
+			col.Sequence = unquote(strings.Replace(m[1], `''`, `'`, -1))
+		}
+		t.Columns = append(t.Columns, col)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if t.PrimaryKey, err = getStrings(tx, `SELECT a.attname FROM pg_index i
+		JOIN pg_attribute a ON a.attrelid = i.indrelid AND a.attnum = ANY(i.indkey)
+		WHERE i.indrelid = ?::regclass AND i.indisprimary`, quote(name)); err != nil {
+		return nil, err
+	}
+	// the expression and partial indexes are not used by the tables of the blockchain
+	indexes, err := model.GetDB(tx).Raw(`SELECT c.relname, x.indisunique,
+		(SELECT json_agg(a.attname ORDER BY k.n) FROM unnest(x.indkey::int2[]) WITH ORDINALITY k(attnum, n)
+		JOIN pg_attribute a ON a.attrelid = x.indrelid AND a.attnum = k.attnum)::text
+		FROM pg_index x JOIN pg_class c ON c.oid = x.indexrelid
+		WHERE x.indrelid = ?::regclass AND NOT x.indisprimary AND x.indexprs IS NULL AND x.indpred IS NULL
+		ORDER BY c.relname`, quote(name)).Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer indexes.Close()
+	for indexes.Next() {
+		var (
+			index   Index
+			columns string
+		)
+		if err = indexes.Scan(&index.Name, &index.Unique, &columns); err != nil {
+			return nil, err
+		}
+		if err = json.Unmarshal([]byte(columns), &index.Columns); err != nil {
+			return nil, err
+		}
+		t.Indexes = append(t.Indexes, index)
+	}
+	return t, indexes.Err()
+}
+
+func writeRows(tx *model.DbTransaction, w *writer, t *Table, where string, args ...interface{}) error {
+	cols := make([]string, len(t.Columns))
+	for i, col := range t.Columns {
+		cols[i] = quote(col.Name) + "::text"
+	}
+	query := fmt.Sprintf(`SELECT %s FROM %s`, strings.Join(cols, ","), quote(t.Name))
+	if len(where) > 0 {
+		query += " WHERE " + where
+	}
+	rows, err := model.GetDB(tx).Raw(query, args...).Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	values := make([]sql.NullString, len(cols))
+	dest := make([]interface{}, len(cols))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	for rows.Next() {
+		if err = rows.Scan(dest...); err != nil {
+			return err
+		}
+		row := make([]*string, len(values))
+		for i, v := range values {
+			if v.Valid {
+				s := v.String
+				row[i] = &s
+			}
+		}
+		if err = w.writeRow(row); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// Create writes the snapshot of the current state to the file.
+// The state is read in a single repeatable read transaction so the node can keep working.
+func Create(fileName string, logger *log.Entry) (*Header, error) {
+	tx, err := model.StartTransaction()
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("starting transaction")
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err = model.GetDB(tx).Exec(`SET TRANSACTION ISOLATION LEVEL REPEATABLE READ READ ONLY`).Error; err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("setting isolation level")
+		return nil, err
+	}
+
+	infoBlock := &model.InfoBlock{}
+	found, err := getInfoBlock(tx, infoBlock)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting info block")
+		return nil, err
+	}
+	if !found || infoBlock.BlockID == 0 {
+		logger.WithFields(log.Fields{"type": consts.NotFound}).Error("blockchain is empty")
+		return nil, errors.New("Blockchain is empty")
+	}
+
+	file, err := os.Create(fileName)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.IOError, "error": err}).Error("creating snapshot file")
+		return nil, err
+	}
+	defer file.Close()
+
+	w := newWriter(file)
+	header := &Header{BlockID: infoBlock.BlockID, Hash: infoBlock.Hash, Time: infoBlock.Time}
+	if err = w.writeHeader(header); err != nil {
+		logger.WithFields(log.Fields{"type": consts.IOError, "error": err}).Error("writing snapshot header")
+		return nil, err
+	}
+
+	tables, err := getTables(tx)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting tables")
+		return nil, err
+	}
+	for _, name := range tables {
+		t, err := getTable(tx, name)
+		if err != nil {
+			logger.WithFields(log.Fields{"type": consts.DBError, "error": err, "table": name}).Error("getting table structure")
+			return nil, err
+		}
+		if err = w.writeTable(t); err != nil {
+			logger.WithFields(log.Fields{"type": consts.IOError, "error": err}).Error("writing snapshot table")
+			return nil, err
+		}
+
+		// only the first block and the snapshot block are required to continue the blockchain
+		if name == blockchainTable {
+			err = writeRows(tx, w, t, "id IN (1, ?)", header.BlockID)
+		} else {
+			err = writeRows(tx, w, t, "")
+		}
+		if err != nil {
+			logger.WithFields(log.Fields{"type": consts.DBError, "error": err, "table": name}).Error("writing table rows")
+			return nil, err
+		}
+	}
+
+	if err = w.close(); err != nil {
+		logger.WithFields(log.Fields{"type": consts.IOError, "error": err}).Error("closing snapshot")
+		return nil, err
+	}
+	return header, file.Sync()
+}
+
+// ReadHeader returns the header of snapshot file
+func ReadHeader(fileName string) (*Header, error) {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	r, err := newReader(file)
+	if err != nil {
+		return nil, err
+	}
+	defer r.close()
+	return r.readHeader()
+}
+
+// createTable creates the table of snapshot, the statements are built from the checked description
+func createTable(tx *model.DbTransaction, t *Table) error {
+	cols := make([]string, 0, len(t.Columns)+1)
+	for _, col := range t.Columns {
+		def := quote(col.Name) + " " + col.Type
+		if col.NotNull {
+			def += " NOT NULL"
+		}
+		if len(col.Sequence) > 0 {
+			if err := model.GetDB(tx).Exec(`CREATE SEQUENCE IF NOT EXISTS ` + quote(col.Sequence)).Error; err != nil {
+				return err
+			}
+		}
+		value, err := columnDefault(col)
+		if err != nil {
+			return err
+		}
+		if len(value) > 0 {
+			def += " DEFAULT " + value
+		}
+		cols = append(cols, def)
+	}
+	if len(t.PrimaryKey) > 0 {
+		cols = append(cols, "PRIMARY KEY ("+quoteList(t.PrimaryKey)+")")
+	}
+	err := model.GetDB(tx).Exec(fmt.Sprintf(`CREATE TABLE %s (%s)`, quote(t.Name), strings.Join(cols, ","))).Error
+	if err != nil {
+		return err
+	}
+	for _, index := range t.Indexes {
+		create := `CREATE INDEX `
+		if index.Unique {
+			create = `CREATE UNIQUE INDEX `
+		}
+		err = model.GetDB(tx).Exec(fmt.Sprintf(`%s%s ON %s (%s)`, create, quote(index.Name), quote(t.Name),
+			quoteList(index.Columns))).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func prepareTable(tx *model.DbTransaction, t *Table) (string, error) {
+	if err := checkTable(t); err != nil {
+		return "", err
+	}
+	if model.IsTable(t.Name) {
+		if err := model.GetDB(tx).Exec(`DELETE FROM ` + quote(t.Name)).Error; err != nil {
+			return "", err
+		}
+	} else if err := createTable(tx, t); err != nil {
+		return "", err
+	}
+
+	cols := make([]string, len(t.Columns))
+	values := make([]string, len(t.Columns))
+	for i, col := range t.Columns {
+		cols[i] = col.Name
+		values[i] = "?::" + col.Type
+	}
+	return fmt.Sprintf(`INSERT INTO %s (%s) VALUES (%s)`, quote(t.Name), quoteList(cols),
+		strings.Join(values, ",")), nil
+}
+
+// finishTable sets the sequences of the restored table after its rows
+func finishTable(tx *model.DbTransaction, t *Table) error {
+	for _, col := range t.Columns {
+		if len(col.Sequence) == 0 {
+			continue
+		}
+		err := model.GetDB(tx).Exec(fmt.Sprintf(`SELECT setval(?::regclass, COALESCE((SELECT max(%s) FROM %s), 0) + 1, false)`,
+			quote(col.Name), quote(t.Name)), quote(col.Sequence)).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// clearState removes the data of the replaced state from the local tables
+func clearState(tx *model.DbTransaction) error {
+	for name, clear := range localTables {
+		if !clear || !model.IsTable(name) {
+			continue
+		}
+		if err := model.GetDB(tx).Exec(`TRUNCATE ` + quote(name)).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// trustedHosts returns the nodes from the config or the full nodes of the current state,
+// the full nodes of the snapshot can't be used because they come from the snapshot itself
+func trustedHosts() ([]string, error) {
+	if hosts := conf.GetNodesAddr(); len(hosts) > 0 {
+		return hosts, nil
+	}
+	if err := syspar.SysUpdate(nil); err != nil {
+		return nil, err
+	}
+	return syspar.GetRemoteHosts(), nil
+}
+
+// VerifyHash checks the hash of the block with the trusted nodes. The most of available
+// nodes must confirm the hash.
+func VerifyHash(blockID int64, hash []byte, logger *log.Entry) error {
+	hosts, err := trustedHosts()
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting trusted hosts")
+		return err
+	}
+	want := string(converter.BinToHex(hash))
+
+	var good, bad int
+	for _, host := range hosts {
+		remote := tcpclient.CheckConfirmation(host, blockID, logger)
+		switch remote {
+		case "0":
+			// the node is unavailable
+		case want:
+			good++
+		default:
+			bad++
+		}
+	}
+	logger.WithFields(log.Fields{"block_id": blockID, "good": good, "bad": bad}).Info("snapshot block confirmations")
+	if good == 0 || bad >= good {
+		return ErrHashNotConfirmed
+	}
+	return nil
+}
+
+// Restore loads the state from the snapshot file into the database. If verify is true
+// then the hash of snapshot block is checked with the trusted nodes before the restore.
+// It only proves that the snapshot block belongs to the blockchain of the network,
+// the tables are restored as they are in the snapshot, so it must be taken from the trusted source.
+func Restore(fileName string, verify bool, logger *log.Entry) (*Header, error) {
+	file, err := os.Open(fileName)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.IOError, "error": err}).Error("opening snapshot file")
+		return nil, err
+	}
+	defer file.Close()
+
+	r, err := newReader(file)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.IOError, "error": err}).Error("reading snapshot file")
+		return nil, err
+	}
+	defer r.close()
+
+	header, err := r.readHeader()
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.UnmarshallingError, "error": err}).Error("reading snapshot header")
+		return nil, err
+	}
+	if verify {
+		if err = VerifyHash(header.BlockID, header.Hash, logger); err != nil {
+			logger.WithFields(log.Fields{"type": consts.BlockError, "error": err, "block_id": header.BlockID}).Error("verifying snapshot hash")
+			return nil, err
+		}
+	}
+
+	tx, err := model.StartTransaction()
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("starting transaction")
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err = clearState(tx); err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("clearing local tables")
+		return nil, err
+	}
+	// the tables of the replaced state which are missing in snapshot are dropped
+	stale, err := getTables(tx)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting tables")
+		return nil, err
+	}
+	staleTables := make(map[string]bool, len(stale))
+	for _, name := range stale {
+		staleTables[name] = true
+	}
+
+	var (
+		table  *Table
+		insert string
+	)
+	for {
+		t, row, err := r.next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			logger.WithFields(log.Fields{"type": consts.UnmarshallingError, "error": err}).Error("reading snapshot")
+			return nil, err
+		}
+		if t != nil {
+			if table != nil {
+				if err = finishTable(tx, table); err != nil {
+					logger.WithFields(log.Fields{"type": consts.DBError, "error": err, "table": table.Name}).Error("setting sequences")
+					return nil, err
+				}
+			}
+			table = t
+			delete(staleTables, table.Name)
+			if insert, err = prepareTable(tx, table); err != nil {
+				logger.WithFields(log.Fields{"type": consts.DBError, "error": err, "table": table.Name}).Error("preparing table")
+				return nil, err
+			}
+			continue
+		}
+		if table == nil || len(row) != len(table.Columns) {
+			logger.WithFields(log.Fields{"type": consts.InvalidObject}).Error("row doesn't match table")
+			return nil, ErrFormat
+		}
+		values := make([]interface{}, len(row))
+		for i, v := range row {
+			if v != nil {
+				values[i] = *v
+			}
+		}
+		if err = model.GetDB(tx).Exec(insert, values...).Error; err != nil {
+			logger.WithFields(log.Fields{"type": consts.DBError, "error": err, "table": table.Name}).Error("inserting row")
+			return nil, err
+		}
+	}
+	if table != nil {
+		if err = finishTable(tx, table); err != nil {
+			logger.WithFields(log.Fields{"type": consts.DBError, "error": err, "table": table.Name}).Error("setting sequences")
+			return nil, err
+		}
+	}
+	for name := range staleTables {
+		if err = model.GetDB(tx).Exec(`DROP TABLE ` + quote(name)).Error; err != nil {
+			logger.WithFields(log.Fields{"type": consts.DBError, "error": err, "table": name}).Error("dropping table")
+			return nil, err
+		}
+	}
+
+	infoBlock := &model.InfoBlock{}
+	if _, err = getInfoBlock(tx, infoBlock); err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting info block")
+		return nil, err
+	}
+	if infoBlock.BlockID != header.BlockID || string(infoBlock.Hash) != string(header.Hash) {
+		logger.WithFields(log.Fields{"type": consts.InvalidObject, "block_id": infoBlock.BlockID}).Error("info block doesn't match snapshot header")
+		return nil, ErrFormat
+	}
+	// the rollback of blocks is impossible before the snapshot block
+	if err = model.SetSnapshotBlockID(tx, header.BlockID); err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("marking snapshot block")
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("committing snapshot")
+		return nil, err
+	}
+
+	if err = syspar.SysUpdate(nil); err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("updating syspar")
+		return nil, err
+	}
+	return header, nil
+}
@@ -0,0 +1,88 @@
+// Apla Software includes an integrated development
+// environment with a multi-level system for the management
+// of access rights to data, interfaces, and Smart contracts. The
+// technical characteristics of the Apla Software are indicated in
+// Apla Technical Paper.
+
+// Apla Users are granted a permission to deal in the Apla
+// Software without restrictions, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of Apla Software, and to permit persons
+// to whom Apla Software is furnished to do so, subject to the
+// following conditions:
+// * the copyright notice of GenesisKernel and EGAAS S.A.
+// and this permission notice shall be included in all copies or
+// substantial portions of the software;
+// * a result of the dealing in Apla Software cannot be
+// implemented outside of the Apla Platform environment.
+
+// THE APLA SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY
+// OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED
+// TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE, ERROR FREE AND NONINFRINGEMENT. IN
+// NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR
+// THE USE OR OTHER DEALINGS IN THE APLA SOFTWARE.
+
+package snapshot
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestColumnDefault(t *testing.T) {
+	cases := []struct {
+		col  Column
+		want string
+		err  bool
+	}{
+		{col: Column{Default: ``}},
+		{col: Column{Default: `0`}, want: `0`},
+		{col: Column{Default: `(-1)`}, want: `(-1)`},
+		{col: Column{Default: `now()`}, want: `now()`},
+		{col: Column{Default: `'0'::bigint`}, want: `'0'::bigint`},
+		{col: Column{Default: `'{}'::jsonb`}, want: `'{}'::jsonb`},
+		{col: Column{Default: `''::character varying`}, want: `''::character varying`},
+		{col: Column{Default: `'it''s'::text`}, want: `'it''s'::text`},
+		{col: Column{Default: `NULL::numeric(30,0)`}, want: `NULL::numeric(30,0)`},
+		{col: Column{Default: `nextval('x'::regclass)`, Sequence: `1_keys_id_seq`},
+			want: `nextval('"1_keys_id_seq"'::regclass)`},
+		{col: Column{Sequence: `seq"; DROP TABLE x; --`},
+			want: `nextval('"seq""; DROP TABLE x; --"'::regclass)`},
+		{col: Column{Default: `'0'::bigint; DROP TABLE x`}, err: true},
+		{col: Column{Default: `'0'::bigint); DROP TABLE x; --`}, err: true},
+		{col: Column{Default: `pg_sleep(10)`}, err: true},
+		{col: Column{Default: `1 + (SELECT 1)`}, err: true},
+	}
+	for _, v := range cases {
+		value, err := columnDefault(v.col)
+		if v.err {
+			assert.Error(t, err, v.col.Default)
+			continue
+		}
+		assert.NoError(t, err, v.col.Default)
+		assert.Equal(t, v.want, value)
+	}
+}
+
+func TestCheckTable(t *testing.T) {
+	table := &Table{
+		Name: "1_keys",
+		Columns: []Column{{Name: "id", Type: "bigint", NotNull: true}, {Name: "amount", Type: "numeric(30,0)"},
+			{Name: "name", Type: "character varying(255)"}, {Name: "time", Type: "timestamp without time zone"}},
+		Indexes: []Index{{Name: "1_keys_index_name", Columns: []string{"name"}, Unique: true}},
+	}
+	assert.NoError(t, checkTable(table))
+
+	for _, typ := range []string{"bigint; DROP TABLE x", "bigint) AS x; --", "text COLLATE x", "pg_sleep"} {
+		table.Columns[0].Type = typ
+		assert.Equal(t, ErrFormat, checkTable(table), typ)
+	}
+	table.Columns[0].Type = "bigint"
+	table.Indexes[0].Columns = nil
+	assert.Equal(t, ErrFormat, checkTable(table))
+}
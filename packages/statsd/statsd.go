@@ -65,3 +65,7 @@ func APIRouteCounterName(method, pattern string) string {
 func DaemonCounterName(daemonName string) string {
 	return "daemon." + daemonName
 }
+
+func TCPServerCounterName(name string) string {
+	return "tcpserver." + name
+}
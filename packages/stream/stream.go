@@ -0,0 +1,227 @@
+// Apla Software includes an integrated development
+// environment with a multi-level system for the management
+// of access rights to data, interfaces, and Smart contracts. The
+// technical characteristics of the Apla Software are indicated in
+// Apla Technical Paper.
+
+// Apla Users are granted a permission to deal in the Apla
+// Software without restrictions, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of Apla Software, and to permit persons
+// to whom Apla Software is furnished to do so, subject to the
+// following conditions:
+// * the copyright notice of GenesisKernel and EGAAS S.A.
+// and this permission notice shall be included in all copies or
+// substantial portions of the software;
+// * a result of the dealing in Apla Software cannot be
+// implemented outside of the Apla Platform environment.
+
+// THE APLA SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY
+// OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED
+// TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE, ERROR FREE AND NONINFRINGEMENT. IN
+// NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR
+// THE USE OR OTHER DEALINGS IN THE APLA SOFTWARE.
+
+package stream
+
+import (
+	"strings"
+	"sync"
+)
+
+// Types of events
+const (
+	TypeBlock    = "block"
+	TypeTx       = "tx"
+	TypeRow      = "row"
+	TypeRollback = "rollback"
+)
+
+// DefaultBufferSize is the default size of the subscription queue
+const DefaultBufferSize = 1000
+
+// BlockEvent is sent when the block has been committed
+type BlockEvent struct {
+	ID           int64  `json:"id"`
+	Hash         []byte `json:"hash"`
+	Time         int64  `json:"time"`
+	EcosystemID  int64  `json:"ecosystem_id"`
+	KeyID        int64  `json:"key_id"`
+	NodePosition int64  `json:"node_position"`
+	Tx           int    `json:"tx_count"`
+}
+
+// TxEvent is sent when the status of the transaction has been changed
+type TxEvent struct {
+	Hash    string `json:"hash"`
+	BlockID int64  `json:"blockid"`
+	Result  string `json:"result,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// RowEvent is sent when the row of the table has been changed by the transaction
+type RowEvent struct {
+	Table  string `json:"table"`
+	ID     string `json:"id"`
+	TxHash string `json:"tx_hash"`
+}
+
+// RollbackEvent is sent when the block has been rolled back.
+// The subscriber should discard the data received for this block and the following ones.
+type RollbackEvent struct {
+	ID int64 `json:"id"`
+}
+
+// Event is a notification about changes of the blockchain
+type Event struct {
+	Type     string         `json:"type"`
+	BlockID  int64          `json:"block_id"`
+	Block    *BlockEvent    `json:"block,omitempty"`
+	Tx       *TxEvent       `json:"tx,omitempty"`
+	Row      *RowEvent      `json:"row,omitempty"`
+	Rollback *RollbackEvent `json:"rollback,omitempty"`
+}
+
+// Filter defines the events which the subscriber wants to receive
+type Filter struct {
+	Blocks bool
+	Txs    map[string]bool
+	Tables map[string]bool
+}
+
+// NewFilter returns filter for blocks, the list of tx hashes and the list of tables
+func NewFilter(blocks bool, txs, tables []string) Filter {
+	f := Filter{
+		Blocks: blocks,
+		Txs:    make(map[string]bool, len(txs)),
+		Tables: make(map[string]bool, len(tables)),
+	}
+	for _, hash := range txs {
+		f.Txs[strings.ToLower(hash)] = true
+	}
+	for _, table := range tables {
+		f.Tables[strings.ToLower(table)] = true
+	}
+	return f
+}
+
+// Match returns true if the event should be sent to the subscriber
+func (f Filter) Match(e *Event) bool {
+	switch e.Type {
+	case TypeBlock:
+		return f.Blocks
+	case TypeTx:
+		return e.Tx != nil && f.Txs[strings.ToLower(e.Tx.Hash)]
+	case TypeRow:
+		return e.Row != nil && f.Tables[strings.ToLower(e.Row.Table)]
+	case TypeRollback:
+		// any subscriber may have received the events of the rolled back block
+		return true
+	}
+	return false
+}
+
+// Subscription is the queue of events of the subscriber.
+// Events is closed if the subscriber doesn't read events fast enough,
+// in this case it should subscribe again and resume from the last received block.
+type Subscription struct {
+	Events <-chan *Event
+	events chan *Event
+	filter Filter
+	closed bool
+}
+
+// Hub delivers events to the subscribers
+type Hub struct {
+	mu   sync.Mutex
+	subs map[*Subscription]struct{}
+}
+
+// NewHub returns new hub
+func NewHub() *Hub {
+	return &Hub{subs: make(map[*Subscription]struct{})}
+}
+
+// Subscribe adds new subscriber with the filter
+func (h *Hub) Subscribe(filter Filter, size int) *Subscription {
+	if size <= 0 {
+		size = DefaultBufferSize
+	}
+	ch := make(chan *Event, size)
+	s := &Subscription{Events: ch, events: ch, filter: filter}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.subs[s] = struct{}{}
+	return s
+}
+
+// Unsubscribe removes the subscriber
+func (h *Hub) Unsubscribe(s *Subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.remove(s)
+}
+
+func (h *Hub) remove(s *Subscription) {
+	delete(h.subs, s)
+	if !s.closed {
+		s.closed = true
+		close(s.events)
+	}
+}
+
+// Len returns the count of subscribers
+func (h *Hub) Len() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.subs)
+}
+
+// Publish sends events to all matching subscribers without blocking
+func (h *Hub) Publish(events ...*Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for s := range h.subs {
+		for _, e := range events {
+			if !s.filter.Match(e) {
+				continue
+			}
+			select {
+			case s.events <- e:
+			default:
+				h.remove(s)
+			}
+			if s.closed {
+				break
+			}
+		}
+	}
+}
+
+var hub = NewHub()
+
+// Subscribe adds new subscriber to the default hub
+func Subscribe(filter Filter, size int) *Subscription {
+	return hub.Subscribe(filter, size)
+}
+
+// Unsubscribe removes the subscriber from the default hub
+func Unsubscribe(s *Subscription) {
+	hub.Unsubscribe(s)
+}
+
+// Len returns the count of subscribers of the default hub
+func Len() int {
+	return hub.Len()
+}
+
+// Publish sends events to the subscribers of the default hub
+func Publish(events ...*Event) {
+	hub.Publish(events...)
+}
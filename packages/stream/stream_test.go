@@ -0,0 +1,75 @@
+// Apla Software includes an integrated development
+// environment with a multi-level system for the management
+// of access rights to data, interfaces, and Smart contracts. The
+// technical characteristics of the Apla Software are indicated in
+// Apla Technical Paper.
+
+// Apla Users are granted a permission to deal in the Apla
+// Software without restrictions, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of Apla Software, and to permit persons
+// to whom Apla Software is furnished to do so, subject to the
+// following conditions:
+// * the copyright notice of GenesisKernel and EGAAS S.A.
+// and this permission notice shall be included in all copies or
+// substantial portions of the software;
+// * a result of the dealing in Apla Software cannot be
+// implemented outside of the Apla Platform environment.
+
+// THE APLA SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY
+// OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED
+// TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE, ERROR FREE AND NONINFRINGEMENT. IN
+// NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR
+// THE USE OR OTHER DEALINGS IN THE APLA SOFTWARE.
+
+package stream
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilter(t *testing.T) {
+	f := NewFilter(false, []string{"ABCD"}, []string{"1_keys"})
+
+	assert.False(t, f.Match(&Event{Type: TypeBlock, Block: &BlockEvent{ID: 1}}))
+	assert.True(t, f.Match(&Event{Type: TypeTx, Tx: &TxEvent{Hash: "abcd"}}))
+	assert.False(t, f.Match(&Event{Type: TypeTx, Tx: &TxEvent{Hash: "abce"}}))
+	assert.True(t, f.Match(&Event{Type: TypeRow, Row: &RowEvent{Table: "1_keys", ID: "1"}}))
+	assert.False(t, f.Match(&Event{Type: TypeRow, Row: &RowEvent{Table: "2_keys", ID: "1"}}))
+	assert.True(t, f.Match(&Event{Type: TypeRollback, BlockID: 5, Rollback: &RollbackEvent{ID: 5}}))
+}
+
+func TestHub(t *testing.T) {
+	h := NewHub()
+	blocks := h.Subscribe(NewFilter(true, nil, nil), 2)
+	rows := h.Subscribe(NewFilter(false, nil, []string{"1_keys"}), 2)
+
+	h.Publish(
+		&Event{Type: TypeBlock, BlockID: 1, Block: &BlockEvent{ID: 1}},
+		&Event{Type: TypeRow, BlockID: 1, Row: &RowEvent{Table: "1_keys", ID: "5"}},
+	)
+	assert.Equal(t, int64(1), (<-blocks.Events).Block.ID)
+	assert.Equal(t, "5", (<-rows.Events).Row.ID)
+
+	// the slow subscriber is removed
+	for i := 0; i < 3; i++ {
+		h.Publish(&Event{Type: TypeBlock, BlockID: int64(i + 2), Block: &BlockEvent{ID: int64(i + 2)}})
+	}
+	assert.Equal(t, 1, h.Len())
+	var count int
+	for range blocks.Events {
+		count++
+	}
+	assert.Equal(t, 2, count)
+
+	h.Unsubscribe(rows)
+	_, ok := <-rows.Events
+	assert.False(t, ok)
+	assert.Equal(t, 0, h.Len())
+}
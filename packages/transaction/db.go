@@ -34,7 +34,9 @@ import (
 	"time"
 
 	"github.com/AplaProject/go-apla/packages/consts"
+	"github.com/AplaProject/go-apla/packages/converter"
 	"github.com/AplaProject/go-apla/packages/model"
+	"github.com/AplaProject/go-apla/packages/stream"
 	"github.com/AplaProject/go-apla/packages/utils"
 
 	log "github.com/sirupsen/logrus"
@@ -45,7 +47,8 @@ var ErrEarlyTime = errors.New("Early transaction time")
 
 // InsertInLogTx is inserting tx in log
 func InsertInLogTx(t *Transaction, blockID int64) error {
-	ltx := &model.LogTransaction{Hash: t.TxHash, Block: blockID}
+	ltx := &model.LogTransaction{Hash: t.TxHash, Block: blockID, KeyID: t.TxKeyID, TxTime: t.TxTime,
+		ContractID: t.ContractID()}
 	if err := ltx.Create(t.DbTransaction); err != nil {
 		log.WithFields(log.Fields{"error": err, "type": consts.DBError}).Error("insert logged transaction")
 		return utils.ErrInfo(err)
@@ -141,6 +144,13 @@ func MarkTransactionBad(dbTransaction *model.DbTransaction, hash []byte, errText
 			log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("setting transaction status error")
 			return utils.ErrInfo(err)
 		}
+		// the status inside of the block is published when the block is committed
+		if dbTransaction == nil {
+			stream.Publish(&stream.Event{
+				Type: stream.TypeTx,
+				Tx:   &stream.TxEvent{Hash: string(converter.BinToHex(hash)), Error: errText},
+			})
+		}
 	}
 	err = DeleteQueueTx(dbTransaction, hash)
 	if err != nil {
@@ -181,6 +191,11 @@ func ProcessQueueTransaction(dbTransaction *model.DbTransaction, hash, binaryTx
 	}
 	counter := tx.Counter
 	counter++
+
+	if err = replacePending(dbTransaction, t); err != nil {
+		return utils.ErrInfo(err)
+	}
+
 	_, err = model.DeleteTransactionByHash(hash)
 	if err != nil {
 		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("deleting transaction by hash")
@@ -189,13 +204,16 @@ func ProcessQueueTransaction(dbTransaction *model.DbTransaction, hash, binaryTx
 
 	// put with verified=1
 	newTx := &model.Transaction{
-		Hash:     hash,
-		Data:     binaryTx,
-		Type:     int8(t.TxType),
-		KeyID:    t.TxKeyID,
-		Counter:  counter,
-		Verified: 1,
-		HighRate: tx.HighRate,
+		Hash:       hash,
+		Data:       binaryTx,
+		Type:       int8(t.TxType),
+		KeyID:      t.TxKeyID,
+		Counter:    counter,
+		Verified:   1,
+		HighRate:   tx.HighRate,
+		Fee:        t.Fee(),
+		TxTime:     t.TxTime,
+		ContractID: t.ContractID(),
 	}
 	err = newTx.Create()
 	if err != nil {
@@ -215,6 +233,10 @@ func ProcessQueueTransaction(dbTransaction *model.DbTransaction, hash, binaryTx
 
 // AllTxParser parses new transactions
 func ProcessTransactionsQueue(dbTransaction *model.DbTransaction) error {
+	if err := ExpireTransactions(dbTransaction); err != nil {
+		return err
+	}
+
 	all, err := model.GetAllUnverifiedAndUnusedTransactions()
 	if err != nil {
 		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting all unverified and unused transactions")
@@ -0,0 +1,234 @@
+// Apla Software includes an integrated development
+// environment with a multi-level system for the management
+// of access rights to data, interfaces, and Smart contracts. The
+// technical characteristics of the Apla Software are indicated in
+// Apla Technical Paper.
+
+// Apla Users are granted a permission to deal in the Apla
+// Software without restrictions, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of Apla Software, and to permit persons
+// to whom Apla Software is furnished to do so, subject to the
+// following conditions:
+// * the copyright notice of GenesisKernel and EGAAS S.A.
+// and this permission notice shall be included in all copies or
+// substantial portions of the software;
+// * a result of the dealing in Apla Software cannot be
+// implemented outside of the Apla Platform environment.
+
+// THE APLA SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY
+// OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED
+// TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE, ERROR FREE AND NONINFRINGEMENT. IN
+// NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR
+// THE USE OR OTHER DEALINGS IN THE APLA SOFTWARE.
+
+package transaction
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/AplaProject/go-apla/packages/consts"
+	"github.com/AplaProject/go-apla/packages/converter"
+	"github.com/AplaProject/go-apla/packages/model"
+	"github.com/AplaProject/go-apla/packages/multisig"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	// ErrMultisigSigner is returned if the sender isn't the signer of the multi-signature account
+	ErrMultisigSigner = errors.New("Sender isn't a signer of the multi-signature account")
+	// ErrMultisigSigned is returned if the sender has already signed the pending transaction
+	ErrMultisigSigned = errors.New("Transaction has already been signed by the sender")
+	// ErrMultisigPending is returned if the pending transaction has not been found
+	ErrMultisigPending = errors.New("Pending transaction has not been found")
+	// ErrMultisigExists is returned if the transaction is already waiting for signatures
+	ErrMultisigExists = errors.New("Transaction is already waiting for signatures")
+)
+
+// GetMultisigAccount returns the multi-signature account or nil if the account is usual
+func GetMultisigAccount(ecosystem, keyID int64) (*model.Key, error) {
+	wallet := &model.Key{}
+	wallet.SetTablePrefix(ecosystem)
+	found, err := wallet.Get(keyID)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting wallet")
+		return nil, err
+	}
+	if !found || wallet.Multi == 0 {
+		return nil, nil
+	}
+	return wallet, nil
+}
+
+func getSigner(signers []model.MultisigSigner, keyID int64) *model.MultisigSigner {
+	for i := range signers {
+		if signers[i].KeyID == keyID {
+			return &signers[i]
+		}
+	}
+	return nil
+}
+
+func joinSigners(signers []model.MultisigSigner) string {
+	ids := make([]string, len(signers))
+	for i, signer := range signers {
+		ids[i] = converter.Int64ToStr(signer.KeyID)
+	}
+	return strings.Join(ids, `,`)
+}
+
+// ProcessMultisig sends the transaction of the multi-signature account if it has enough signatures,
+// otherwise the transaction waits for the signatures of other signers
+func ProcessMultisig(rtx *RawTransaction, wallet *model.Key, ecosystem, sender int64) error {
+	signers, err := model.GetMultisigSigners(nil, ecosystem, wallet.ID)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting multi-signature signers")
+		return err
+	}
+	if getSigner(signers, sender) == nil {
+		return ErrMultisigSigner
+	}
+	signs, err := multisig.SplitSignatures(rtx.Signature())
+	if err != nil {
+		return err
+	}
+	signed, err := multisig.Verify(signers, rtx.Hash(), signs)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.InvalidObject, "error": err, "key_id": wallet.ID}).Error("checking multi-signature")
+		return err
+	}
+	if int64(len(signed)) >= wallet.Multi {
+		return model.SendTx(rtx, sender)
+	}
+
+	pending := &model.MultisigPending{}
+	found, err := pending.Get(rtx.Hash())
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting pending transaction")
+		return err
+	}
+	if found {
+		return ErrMultisigExists
+	}
+	pending = &model.MultisigPending{
+		Hash:       rtx.Hash(),
+		Data:       rtx.Bytes(),
+		KeyID:      wallet.ID,
+		Ecosystem:  ecosystem,
+		Threshold:  wallet.Multi,
+		Signatures: multisig.JoinSignatures(signs),
+		Signers:    joinSigners(signed),
+		Time:       time.Now().Unix(),
+	}
+	if err = pending.Create(nil); err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("saving pending transaction")
+		return err
+	}
+	return nil
+}
+
+// SignMultisig adds the signature of the sender to the pending transaction and sends the transaction
+// when it gets enough signatures. It returns true if the transaction has been sent.
+// The pending transaction is locked until the signature is saved, so concurrent signers
+// can't overwrite the signatures of each other or send the transaction twice.
+func SignMultisig(hash, sign []byte, sender int64) (bool, error) {
+	dbTransaction, err := model.StartTransaction()
+	if err != nil {
+		return false, err
+	}
+	sent, err := signMultisig(dbTransaction, hash, sign, sender)
+	if err != nil {
+		dbTransaction.Rollback()
+		return false, err
+	}
+	if err = dbTransaction.Commit(); err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("committing pending transaction")
+		return false, err
+	}
+	return sent, nil
+}
+
+func signMultisig(dbTransaction *model.DbTransaction, hash, sign []byte, sender int64) (bool, error) {
+	pending := &model.MultisigPending{}
+	found, err := pending.GetForUpdate(dbTransaction, hash)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting pending transaction")
+		return false, err
+	}
+	if !found {
+		return false, ErrMultisigPending
+	}
+	signers, err := model.GetMultisigSigners(dbTransaction, pending.Ecosystem, pending.KeyID)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting multi-signature signers")
+		return false, err
+	}
+	signer := getSigner(signers, sender)
+	if signer == nil {
+		return false, ErrMultisigSigner
+	}
+	for _, id := range strings.Split(pending.Signers, `,`) {
+		if converter.StrToInt64(id) == sender {
+			return false, ErrMultisigSigned
+		}
+	}
+	signs, err := multisig.SplitSignatures(pending.Signatures)
+	if err != nil {
+		return false, err
+	}
+	signed, err := multisig.Verify([]model.MultisigSigner{*signer}, hash, [][]byte{sign})
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.InvalidObject, "error": err, "key_id": sender}).Error("checking multi-signature")
+		return false, err
+	}
+	signs = append(signs, sign)
+	pending.Signatures = multisig.JoinSignatures(signs)
+	pending.Signers = strings.Trim(pending.Signers+`,`+joinSigners(signed), `,`)
+
+	if int64(len(signs)) < pending.Threshold {
+		if err = pending.Save(dbTransaction); err != nil {
+			log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("saving pending transaction")
+			return false, err
+		}
+		return false, nil
+	}
+
+	rtx, err := multisigTransaction(pending.Data, signs)
+	if err != nil {
+		return false, err
+	}
+	if err = pending.Delete(dbTransaction); err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("deleting pending transaction")
+		return false, err
+	}
+	// the row stays locked while the transaction is being sent
+	if err = model.SendTx(rtx, sender); err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("sending tx")
+		return false, err
+	}
+	return true, nil
+}
+
+// multisigTransaction replaces the signatures of the transaction
+func multisigTransaction(data []byte, signs [][]byte) (*RawTransaction, error) {
+	origin := &RawTransaction{}
+	if err := origin.Unmarshall(bytes.NewBuffer(data)); err != nil {
+		return nil, err
+	}
+	data = append([]byte{byte(origin.Type())}, converter.EncodeLengthPlusData(origin.Payload())...)
+	data = append(data, multisig.JoinSignatures(signs)...)
+
+	rtx := &RawTransaction{}
+	if err := rtx.Unmarshall(bytes.NewBuffer(data)); err != nil {
+		return nil, err
+	}
+	return rtx, nil
+}
@@ -0,0 +1,132 @@
+// Apla Software includes an integrated development
+// environment with a multi-level system for the management
+// of access rights to data, interfaces, and Smart contracts. The
+// technical characteristics of the Apla Software are indicated in
+// Apla Technical Paper.
+
+// Apla Users are granted a permission to deal in the Apla
+// Software without restrictions, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of Apla Software, and to permit persons
+// to whom Apla Software is furnished to do so, subject to the
+// following conditions:
+// * the copyright notice of GenesisKernel and EGAAS S.A.
+// and this permission notice shall be included in all copies or
+// substantial portions of the software;
+// * a result of the dealing in Apla Software cannot be
+// implemented outside of the Apla Platform environment.
+
+// THE APLA SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY
+// OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED
+// TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE, ERROR FREE AND NONINFRINGEMENT. IN
+// NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR
+// THE USE OR OTHER DEALINGS IN THE APLA SOFTWARE.
+
+package transaction
+
+import (
+	"errors"
+	"time"
+
+	"github.com/AplaProject/go-apla/packages/conf"
+	"github.com/AplaProject/go-apla/packages/conf/syspar"
+	"github.com/AplaProject/go-apla/packages/consts"
+	"github.com/AplaProject/go-apla/packages/converter"
+	"github.com/AplaProject/go-apla/packages/model"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const errTxExpired = "transaction expired"
+
+// ErrUsedSlot is returned if the blockchain already contains the transaction of the same sender, time and contract
+var ErrUsedSlot = errors.New("Transaction of the same sender, time and contract is already in the blockchain")
+
+// Fee returns the fuel which is offered by the transaction, the pool orders transactions by it.
+// It's MaxSum of the contract or the default maximal cost if MaxSum isn't specified
+func (t *Transaction) Fee() int64 {
+	if t.TxSmart == nil {
+		return 0
+	}
+	if len(t.TxSmart.MaxSum) > 0 {
+		return converter.StrToInt64(t.TxSmart.MaxSum)
+	}
+	return syspar.GetMaxCost()
+}
+
+// ContractID returns identifier of the contract or the type of the struct transaction
+func (t *Transaction) ContractID() int64 {
+	if t.TxSmart != nil {
+		return int64(t.TxSmart.ID)
+	}
+	return t.TxType
+}
+
+// replacePending drops the pending transaction of the same sender, time and contract
+// if the new transaction offers more fuel
+func replacePending(dbTransaction *model.DbTransaction, t *Transaction) error {
+	pending := &model.Transaction{}
+	found, err := pending.GetPending(dbTransaction, t.TxKeyID, t.TxTime, t.ContractID())
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting pending transaction")
+		return err
+	}
+	if !found || string(pending.Hash) == string(t.TxHash) {
+		return nil
+	}
+
+	fee := t.Fee()
+	if fee <= pending.Fee {
+		// the same transaction without higher payment isn't a replacement
+		return nil
+	}
+
+	log.WithFields(log.Fields{"type": consts.DuplicateObject, "tx_hash": pending.Hash, "new_tx_hash": t.TxHash,
+		"fee": pending.Fee, "new_fee": fee}).Info("replacing pending transaction")
+	return MarkTransactionBad(dbTransaction, pending.Hash, "transaction replaced by "+string(converter.BinToHex(t.TxHash)))
+}
+
+// CheckSlot returns the error if the transaction of the same sender, time and contract has been played.
+// Only one transaction of the slot is accepted by the blockchain, so the pending transactions of the slot
+// can replace each other.
+func (t *Transaction) CheckSlot(blockID int64) error {
+	if t.TxSmart == nil || !syspar.IsActive(syspar.TxSlotBlock, blockID) {
+		return nil
+	}
+	found, err := model.IsUsedSlot(t.DbTransaction, t.TxKeyID, t.TxTime, t.ContractID())
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("checking slot of transaction")
+		return err
+	}
+	if found {
+		return ErrUsedSlot
+	}
+	return nil
+}
+
+// ExpireTransactions drops the unused transactions which are older than the lifetime of the pool
+func ExpireTransactions(dbTransaction *model.DbTransaction) error {
+	if conf.Config.TxPoolTTL <= 0 {
+		return nil
+	}
+
+	hashes, err := model.GetExpiredTransactions(dbTransaction, time.Now().Unix()-conf.Config.TxPoolTTL)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting expired transactions")
+		return err
+	}
+	for _, hash := range hashes {
+		if err = MarkTransactionBad(dbTransaction, hash, errTxExpired); err != nil {
+			return err
+		}
+	}
+	if _, err = model.DeleteExpiredMultisigPending(dbTransaction, time.Now().Unix()-conf.Config.TxPoolTTL); err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("deleting expired pending transactions")
+		return err
+	}
+	return nil
+}
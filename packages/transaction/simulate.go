@@ -0,0 +1,135 @@
+// Apla Software includes an integrated development
+// environment with a multi-level system for the management
+// of access rights to data, interfaces, and Smart contracts. The
+// technical characteristics of the Apla Software are indicated in
+// Apla Technical Paper.
+
+// Apla Users are granted a permission to deal in the Apla
+// Software without restrictions, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of Apla Software, and to permit persons
+// to whom Apla Software is furnished to do so, subject to the
+// following conditions:
+// * the copyright notice of GenesisKernel and EGAAS S.A.
+// and this permission notice shall be included in all copies or
+// substantial portions of the software;
+// * a result of the dealing in Apla Software cannot be
+// implemented outside of the Apla Platform environment.
+
+// THE APLA SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY
+// OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED
+// TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE, ERROR FREE AND NONINFRINGEMENT. IN
+// NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR
+// THE USE OR OTHER DEALINGS IN THE APLA SOFTWARE.
+
+package transaction
+
+import (
+	"bytes"
+	"errors"
+	"math/rand"
+	"time"
+
+	"github.com/AplaProject/go-apla/packages/consts"
+	"github.com/AplaProject/go-apla/packages/converter"
+	"github.com/AplaProject/go-apla/packages/model"
+	"github.com/AplaProject/go-apla/packages/script"
+	"github.com/AplaProject/go-apla/packages/smart"
+	"github.com/AplaProject/go-apla/packages/utils/tx"
+
+	"github.com/shopspring/decimal"
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/vmihailenco/msgpack.v2"
+)
+
+// ErrNotContract is returned if the simulated transaction doesn't call a contract
+var ErrNotContract = errors.New("Transaction is not a contract call")
+
+// ChangedRow is the row which has been inserted or updated by the transaction
+type ChangedRow struct {
+	Table  string `json:"table"`
+	ID     string `json:"id"`
+	Insert bool   `json:"insert"`
+	Prev   string `json:"prev,omitempty"` // the previous values of the updated row
+}
+
+// Simulation is the result of the transaction which has been executed and rolled back
+type Simulation struct {
+	Hash     []byte
+	Result   string
+	Fuel     int64
+	Payment  decimal.Decimal
+	FuelStat *script.FuelStat
+	Rows     []ChangedRow
+	Error    error
+}
+
+// Simulate executes the unsigned contract transaction in the database transaction
+// which is always rolled back. The signature isn't required so the fee can be estimated
+// before signing. The error of the contract is returned in Simulation.Error.
+func Simulate(smartTx *tx.SmartContract) (*Simulation, error) {
+	payload, err := msgpack.Marshal(smartTx)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.MarshallingError, "error": err}).Error("marshalling smart contract to msgpack")
+		return nil, err
+	}
+	rtx := &RawTransaction{}
+	if err = rtx.Unmarshall(bytes.NewBuffer(append([]byte{128}, converter.EncodeLengthPlusData(payload)...))); err != nil {
+		return nil, err
+	}
+	t, err := newTransaction(rtx, true)
+	if err != nil {
+		return nil, err
+	}
+	if t.TxContract == nil {
+		return nil, ErrNotContract
+	}
+	t.DryRun = true
+	logger := t.GetLogger()
+
+	now := time.Now().Unix()
+	if err = t.Check(now, false); err != nil {
+		return nil, err
+	}
+	if t.BlockData, err = smart.NextBlockData(now); err != nil {
+		return nil, err
+	}
+
+	dbTx, err := model.StartTransaction()
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("starting transaction")
+		return nil, err
+	}
+	defer dbTx.Rollback()
+
+	t.DbTransaction = dbTx
+	t.Rand = rand.New(rand.NewSource(now))
+	t.FuelStat = script.NewFuelStat()
+
+	sim := &Simulation{Hash: t.TxHash, FuelStat: t.FuelStat}
+	// the contracts aren't flushed in dry run
+	sim.Result, _, sim.Error = t.CallContract()
+	sim.Fuel = t.TxFuel
+	sim.Payment = t.TxPayment
+
+	rollbackTx := &model.RollbackTx{}
+	rows, err := rollbackTx.GetRollbackTransactions(dbTx, t.TxHash)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting rollback transactions")
+		return nil, err
+	}
+	// rollback records are sorted in descending order
+	for i := len(rows) - 1; i >= 0; i-- {
+		sim.Rows = append(sim.Rows, ChangedRow{
+			Table:  rows[i]["table_name"],
+			ID:     rows[i]["table_id"],
+			Insert: len(rows[i]["data"]) == 0,
+			Prev:   rows[i]["data"],
+		})
+	}
+	return sim, nil
+}
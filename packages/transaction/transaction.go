@@ -122,6 +122,7 @@ type Transaction struct {
 	TxKeyID       int64
 	TxTime        int64
 	TxType        int64
+	TxIndex       int64 // the position of the transaction in the block
 	TxCost        int64 // Maximum cost of executing contract
 	TxFuel        int64
 	TxUsedCost    decimal.Decimal // Used cost of CPU resources
@@ -133,8 +134,11 @@ type Transaction struct {
 	tx            custom.TransactionInterface
 	DbTransaction *model.DbTransaction
 	SysUpdate     bool
+	DryRun        bool // the transaction is simulated without the signature
 	Rand          *rand.Rand
 	Notifications []smart.NotifyInfo
+	TxPayment     decimal.Decimal
+	FuelStat      *script.FuelStat
 
 	SmartContract smart.SmartContract
 }
@@ -164,6 +168,16 @@ func UnmarshallTransaction(buffer *bytes.Buffer, fillData bool) (*Transaction, e
 		return t, nil
 	}
 
+	t, err := newTransaction(rtx, fillData)
+	if err != nil {
+		return t, err
+	}
+	txCache.Set(t)
+
+	return t, nil
+}
+
+func newTransaction(rtx *RawTransaction, fillData bool) (*Transaction, error) {
 	t := new(Transaction)
 	t.TxFullData = rtx.Bytes()
 	t.TxType = rtx.Type()
@@ -186,7 +200,6 @@ func UnmarshallTransaction(buffer *bytes.Buffer, fillData bool) (*Transaction, e
 
 		// all other transactions
 	}
-	txCache.Set(t)
 
 	return t, nil
 }
@@ -373,6 +386,7 @@ func (t *Transaction) CallContract() (resultContract string, flushRollback []sma
 	sc := smart.SmartContract{
 		OBS:           false,
 		Rollback:      true,
+		DryRun:        t.DryRun,
 		SysUpdate:     false,
 		VM:            smart.GetVM(),
 		TxSmart:       *t.TxSmart,
@@ -384,12 +398,15 @@ func (t *Transaction) CallContract() (resultContract string, flushRollback []sma
 		TxHash:        t.TxHash,
 		TxSignature:   t.TxSignature,
 		TxSize:        int64(len(t.TxBinaryData)),
+		TxIndex:       t.TxIndex,
 		PublicKeys:    t.PublicKeys,
 		DbTransaction: t.DbTransaction,
 		Rand:          t.Rand,
+		FuelStat:      t.FuelStat,
 	}
 	resultContract, err = sc.CallContract()
 	t.TxFuel = sc.TxFuel
+	t.TxPayment = sc.TxPayment
 	t.SysUpdate = sc.SysUpdate
 	t.Notifications = sc.Notifications
 	if sc.FlushRollback != nil {
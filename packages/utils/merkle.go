@@ -0,0 +1,97 @@
+// Apla Software includes an integrated development
+// environment with a multi-level system for the management
+// of access rights to data, interfaces, and Smart contracts. The
+// technical characteristics of the Apla Software are indicated in
+// Apla Technical Paper.
+
+// Apla Users are granted a permission to deal in the Apla
+// Software without restrictions, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of Apla Software, and to permit persons
+// to whom Apla Software is furnished to do so, subject to the
+// following conditions:
+// * the copyright notice of GenesisKernel and EGAAS S.A.
+// and this permission notice shall be included in all copies or
+// substantial portions of the software;
+// * a result of the dealing in Apla Software cannot be
+// implemented outside of the Apla Platform environment.
+
+// THE APLA SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY
+// OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED
+// TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE, ERROR FREE AND NONINFRINGEMENT. IN
+// NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR
+// THE USE OR OTHER DEALINGS IN THE APLA SOFTWARE.
+
+package utils
+
+import (
+	"bytes"
+
+	"github.com/AplaProject/go-apla/packages/converter"
+	"github.com/AplaProject/go-apla/packages/crypto"
+)
+
+// MerkleStep is the sibling node on the path from the leaf to the root of Merkle tree
+type MerkleStep struct {
+	Hash string `json:"hash"`
+	Left bool   `json:"left"` // the sibling is on the left side
+}
+
+func merkleHash(data []byte) []byte {
+	hash, _ := crypto.DoubleHash(data)
+	return converter.BinToHex(hash)
+}
+
+// MerkleTreeProof returns the path of the sibling nodes for the value with the specified index.
+// It follows MerkleTreeRoot so the last node of the odd level is moved up without a sibling.
+func MerkleTreeProof(dataArray [][]byte, index int) []MerkleStep {
+	if index < 0 || index >= len(dataArray) {
+		return nil
+	}
+	level := make([][]byte, len(dataArray))
+	for i, v := range dataArray {
+		level[i] = merkleHash(v)
+	}
+	path := make([]MerkleStep, 0)
+	for len(level) > 1 {
+		next := make([][]byte, 0, (len(level)+1)/2)
+		for i := 0; i < len(level); i += 2 {
+			if i+1 == len(level) {
+				next = append(next, level[i])
+				continue
+			}
+			switch index {
+			case i:
+				path = append(path, MerkleStep{Hash: string(level[i+1])})
+			case i + 1:
+				path = append(path, MerkleStep{Hash: string(level[i]), Left: true})
+			}
+			next = append(next, merkleHash(append(append([]byte{}, level[i]...), level[i+1]...)))
+		}
+		index /= 2
+		level = next
+	}
+	return path
+}
+
+// MerkleRootByProof calculates the root of Merkle tree by the value and the path of its sibling nodes
+func MerkleRootByProof(data []byte, path []MerkleStep) []byte {
+	hash := merkleHash(data)
+	for _, step := range path {
+		if step.Left {
+			hash = merkleHash(append([]byte(step.Hash), hash...))
+		} else {
+			hash = merkleHash(append(hash, step.Hash...))
+		}
+	}
+	return hash
+}
+
+// CheckMerkleProof returns true if the value belongs to Merkle tree with the specified root
+func CheckMerkleProof(data []byte, path []MerkleStep, root []byte) bool {
+	return bytes.Equal(MerkleRootByProof(data, path), root)
+}
@@ -33,14 +33,14 @@ import (
 	"github.com/AplaProject/go-apla/packages/converter"
 	"github.com/AplaProject/go-apla/packages/crypto"
 	"github.com/AplaProject/go-apla/packages/model"
+	"github.com/AplaProject/go-apla/packages/signer"
 	log "github.com/sirupsen/logrus"
 	"gopkg.in/vmihailenco/msgpack.v2"
 )
 
-func newTransaction(smartTx SmartContract, privateKey []byte, internal bool) (data, hash []byte, err error) {
+func newTransaction(smartTx SmartContract, s signer.Signer, internal bool) (data, hash []byte, err error) {
 	var publicKey []byte
-	if publicKey, err = crypto.PrivateToPublic(privateKey); err != nil {
-		log.WithFields(log.Fields{"type": consts.CryptoError, "error": err}).Error("converting node private key to public")
+	if publicKey, err = s.PublicKey(); err != nil {
 		return
 	}
 	smartTx.PublicKey = publicKey
@@ -59,7 +59,7 @@ func newTransaction(smartTx SmartContract, privateKey []byte, internal bool) (da
 		return
 	}
 
-	signature, err := crypto.Sign(privateKey, hash)
+	signature, err := s.Sign(hash)
 	if err != nil {
 		log.WithFields(log.Fields{"type": consts.CryptoError, "error": err}).Error("signing by node private key")
 		return
@@ -69,12 +69,13 @@ func newTransaction(smartTx SmartContract, privateKey []byte, internal bool) (da
 	return
 }
 
-func NewInternalTransaction(smartTx SmartContract, privateKey []byte) (data, hash []byte, err error) {
-	return newTransaction(smartTx, privateKey, true)
+// NewInternalTransaction creates the transaction which is signed by the signer of the node
+func NewInternalTransaction(smartTx SmartContract, s signer.Signer) (data, hash []byte, err error) {
+	return newTransaction(smartTx, s, true)
 }
 
 func NewTransaction(smartTx SmartContract, privateKey []byte) (data, hash []byte, err error) {
-	return newTransaction(smartTx, privateKey, false)
+	return newTransaction(smartTx, signer.NewKeySigner(privateKey), false)
 }
 
 // CreateTransaction creates transaction
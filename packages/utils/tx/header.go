@@ -28,6 +28,8 @@
 
 package tx
 
+import "github.com/AplaProject/go-apla/packages/crypto"
+
 // Header is contain header data
 type Header struct {
 	ID          int
@@ -36,4 +38,5 @@ type Header struct {
 	KeyID       int64
 	NetworkID   int64
 	PublicKey   []byte
+	KeyType     crypto.KeyType `msgpack:",omitempty"`
 }
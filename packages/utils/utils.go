@@ -50,6 +50,7 @@ import (
 	"github.com/AplaProject/go-apla/packages/consts"
 	"github.com/AplaProject/go-apla/packages/converter"
 	"github.com/AplaProject/go-apla/packages/crypto"
+	"github.com/AplaProject/go-apla/packages/keystore"
 	"github.com/AplaProject/go-apla/packages/model"
 	uuid "github.com/satori/go.uuid"
 
@@ -275,8 +276,13 @@ func CopyFileContents(src, dst string) error {
 	return ErrInfo(err)
 }
 
-// CheckSign checks the signature
+// CheckSign checks the signature of P-256 key
 func CheckSign(publicKeys [][]byte, forSign []byte, signs []byte, nodeKeyOrLogin bool) (bool, error) {
+	return CheckSignType(crypto.KeyTypeP256, publicKeys, forSign, signs, nodeKeyOrLogin)
+}
+
+// CheckSignType checks the signature of the public key of the specified type
+func CheckSignType(keyType crypto.KeyType, publicKeys [][]byte, forSign []byte, signs []byte, nodeKeyOrLogin bool) (bool, error) {
 	defer func() {
 		if r := recover(); r != nil {
 			log.WithFields(log.Fields{"type": consts.PanicRecoveredError, "error": r}).Error("recovered panic in check sign")
@@ -314,7 +320,7 @@ func CheckSign(publicKeys [][]byte, forSign []byte, signs []byte, nodeKeyOrLogin
 			return false, fmt.Errorf("sign error %d!=%d", len(publicKeys), len(signsSlice))
 		}
 	}
-	return crypto.CheckSign(publicKeys[0], forSign, signsSlice[0])
+	return keyType.CheckSign(publicKeys[0], forSign, signsSlice[0])
 }
 
 // MerkleTreeRoot rertun Merkle value
@@ -416,14 +422,8 @@ func GetParent() string {
 
 // GetNodeKeys returns node private key and public key
 func GetNodeKeys() (string, string, error) {
-	nprivkey, err := ioutil.ReadFile(filepath.Join(conf.Config.KeysDir, consts.NodePrivateKeyFilename))
-	if err != nil {
-		log.WithFields(log.Fields{"type": consts.IOError, "error": err}).Error("reading node private key from file")
-		return "", "", err
-	}
-	key, err := hex.DecodeString(string(nprivkey))
+	key, err := GetNodePrivateKey()
 	if err != nil {
-		log.WithFields(log.Fields{"type": consts.ConversionError, "error": err}).Error("decoding private key from hex")
 		return "", "", err
 	}
 	npubkey, err := crypto.PrivateToPublic(key)
@@ -431,21 +431,12 @@ func GetNodeKeys() (string, string, error) {
 		log.WithFields(log.Fields{"type": consts.CryptoError, "error": err}).Error("converting node private key to public")
 		return "", "", err
 	}
-	return string(nprivkey), crypto.PubToHex(npubkey), nil
+	return hex.EncodeToString(key), crypto.PubToHex(npubkey), nil
 }
 
+// GetNodePrivateKey returns node private key, the encrypted key is decrypted by the passphrase of the keystore
 func GetNodePrivateKey() ([]byte, error) {
-	data, err := ioutil.ReadFile(filepath.Join(conf.Config.KeysDir, consts.NodePrivateKeyFilename))
-	if err != nil {
-		log.WithFields(log.Fields{"type": consts.IOError, "error": err}).Error("reading node private key from file")
-		return nil, err
-	}
-	privateKey, err := hex.DecodeString(string(data))
-	if err != nil {
-		log.WithFields(log.Fields{"type": consts.ConversionError, "error": err}).Error("decoding private key from hex")
-		return nil, err
-	}
-	return privateKey, nil
+	return keystore.ReadFile(filepath.Join(conf.Config.KeysDir, consts.NodePrivateKeyFilename))
 }
 
 func GetHostPort(h string) string {